@@ -2,10 +2,10 @@ package api
 
 import (
 	db "bank/db/sqlc"
-	"database/sql"
 	"errors"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -29,6 +29,12 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		return
 	}
 
+	idempotency, err := server.idempotencyParams(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	arg := db.CreateAccountParams{
 		Owner:    request.Owner,
 		UserID:   userID,
@@ -36,8 +42,22 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		Currency: request.Currency,
 	}
 
-	account, err := server.store.CreateAccount(ctx, arg)
+	var account db.Account
+	if idempotency == nil {
+		account, err = server.store.CreateAccount(ctx, arg)
+	} else {
+		var result db.CreateAccountTxResult
+		result, err = server.store.CreateAccountTx(ctx, db.CreateAccountTxParams{
+			CreateAccountParams: arg,
+			Idempotency:         idempotency,
+		})
+		account = result.Account
+	}
 	if err != nil {
+		if errors.Is(err, db.ErrIdempotencyKeyReused) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
 		log.Println(err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -59,9 +79,9 @@ func (server *Server) getAccount(ctx *gin.Context) {
 	}
 
 	userID, _ := getUserIdFromAuth(ctx)
-	account, err := server.store.GetUserAccount(ctx, db.GetUserAccountParams{userID, request.ID})
+	account, err := server.store.GetUserAccount(ctx, db.GetUserAccountParams{UserID: userID, ID: request.ID})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, db.ErrRecordNotFound) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
 			return
 		}
@@ -74,8 +94,13 @@ func (server *Server) getAccount(ctx *gin.Context) {
 }
 
 type listAccountRequest struct {
-	PageNum int32 `form:"page_num" binding:"required,min=1"`
-	Count   int32 `form:"count" binding:"required,min=1,max=10"`
+	PageSize  int32  `form:"page_size" binding:"required,min=1,max=100"`
+	PageToken string `form:"page_token"`
+}
+
+type listAccountsResponse struct {
+	Accounts      []db.Account `json:"accounts"`
+	NextPageToken string       `json:"next_page_token,omitempty"`
 }
 
 func (server *Server) listAccounts(ctx *gin.Context) {
@@ -86,12 +111,20 @@ func (server *Server) listAccounts(ctx *gin.Context) {
 		return
 	}
 
+	pageToken, err := db.DecodePageToken(request.PageToken)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	userID, _ := getUserIdFromAuth(ctx)
 
+	// one extra row tells whether there is a next page
 	arg := db.ListAccountsParams{
-		UserID: userID,
-		Limit:  request.Count,
-		Offset: (request.PageNum - 1) * request.Count,
+		UserID:          userID,
+		CursorCreatedAt: pageToken.CursorCreatedAt(),
+		CursorID:        pageToken.CursorID(),
+		PageLimit:       request.PageSize + 1,
 	}
 
 	accounts, err := server.store.ListAccounts(ctx, arg)
@@ -101,5 +134,63 @@ func (server *Server) listAccounts(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, accounts)
+	var response listAccountsResponse
+	if len(accounts) > int(request.PageSize) {
+		accounts = accounts[:request.PageSize]
+		last := accounts[len(accounts)-1]
+		response.NextPageToken = db.EncodePageToken(last.CreatedAt, last.ID)
+	}
+	response.Accounts = accounts
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+type getAccountStatementQuery struct {
+	From time.Time `form:"from" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   time.Time `form:"to" binding:"required,gtfield=From" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+func (server *Server) getAccountStatement(ctx *gin.Context) {
+	var request getAccountRequest
+	err := ctx.ShouldBindUri(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var query getAccountStatementQuery
+	err = ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	userID, _ := getUserIdFromAuth(ctx)
+	_, err = server.store.GetUserAccount(ctx, db.GetUserAccountParams{UserID: userID, ID: request.ID})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		log.Println(err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	statement, err := server.store.GetAccountStatementTx(ctx, db.GetAccountStatementTxParams{
+		AccountID: request.ID,
+		From:      query.From,
+		To:        query.To,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrAccountNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		log.Println(err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, statement)
 }
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -64,7 +65,7 @@ func TestGetAccountApi(t *testing.T) {
 				store.EXPECT().
 					GetUserAccount(gomock.Any(), gomock.Eq(db.GetUserAccountParams{UserID: account.UserID, ID: account.ID})).
 					Times(1).
-					Return(db.Account{}, sql.ErrNoRows)
+					Return(db.Account{}, db.ErrRecordNotFound)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
@@ -88,7 +89,7 @@ func TestGetAccountApi(t *testing.T) {
 				store.EXPECT().
 					GetUserAccount(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(db.Account{}, sql.ErrNoRows)
+					Return(db.Account{}, db.ErrRecordNotFound)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
@@ -134,7 +135,7 @@ func TestGetAccountApi(t *testing.T) {
 
 		tc.buildStubs(store)
 
-		server := newTestServer(t, store)
+		server := newTestServer(t, store, nil)
 		recorder := httptest.NewRecorder()
 
 		url := fmt.Sprintf("/accounts/%d", tc.accountID)
@@ -247,7 +248,7 @@ func TestCreateAccount(t *testing.T) {
 
 		tc.buildStubs(store)
 
-		server := newTestServer(t, store)
+		server := newTestServer(t, store, nil)
 		recorder := httptest.NewRecorder()
 
 		payload, _ := json.Marshal(tc.params)
@@ -271,30 +272,32 @@ func TestListAccounts(t *testing.T) {
 		randomAccount(user.ID),
 		randomAccount(user.ID),
 		randomAccount(user.ID),
+		randomAccount(user.ID),
+	}
+	cursor := time.Now().UTC().Truncate(time.Second)
+	pageToken := db.EncodePageToken(cursor, 1000)
+
+	type listParams struct {
+		PageSize  int64
+		PageToken string
 	}
 
 	testCases := []struct {
-		name   string
-		params struct {
-			PageNum int64
-			Count   int64
-		}
+		name            string
+		params          listParams
 		setupAuthHeader func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubs      func(store *mockdb.MockStore)
 		checkResponse   func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
-			name: "OK",
-			params: struct {
-				PageNum int64
-				Count   int64
-			}{1, 5},
+			name:   "OK",
+			params: listParams{PageSize: 10},
 			setupAuthHeader: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authHeaderTypeBearer, user.ID, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					ListAccounts(gomock.Any(), gomock.Eq(db.ListAccountsParams{UserID: user.ID, Limit: 5, Offset: 0})).
+					ListAccounts(gomock.Any(), gomock.Eq(db.ListAccountsParams{UserID: user.ID, PageLimit: 11})).
 					Times(1).
 					Return(accounts, nil)
 			},
@@ -303,25 +306,54 @@ func TestListAccounts(t *testing.T) {
 
 				//check response body
 				body := recorder.Body.Bytes()
-				var gotAccounts []db.Account
-				err := json.Unmarshal(body, &gotAccounts)
+				var got listAccountsResponse
+				err := json.Unmarshal(body, &got)
 				require.NoError(t, err)
 
-				require.Equal(t, accounts, gotAccounts)
+				require.Equal(t, accounts, got.Accounts)
+				require.Empty(t, got.NextPageToken)
 			},
 		},
 		{
-			name: "Validation fail",
-			params: struct {
-				PageNum int64
-				Count   int64
-			}{1, 11},
+			name:   "Next page",
+			params: listParams{PageSize: 5, PageToken: pageToken},
 			setupAuthHeader: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authHeaderTypeBearer, user.ID, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListAccountsParams{
+					UserID:          user.ID,
+					CursorCreatedAt: pgtype.Timestamptz{Time: cursor, Valid: true},
+					CursorID:        pgtype.Int8{Int64: 1000, Valid: true},
+					PageLimit:       6,
+				}
 				store.EXPECT().
-					ListAccounts(gomock.Any(), gomock.Any).
+					ListAccounts(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(accounts, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				body := recorder.Body.Bytes()
+				var got listAccountsResponse
+				err := json.Unmarshal(body, &got)
+				require.NoError(t, err)
+
+				last := accounts[4]
+				require.Equal(t, accounts[:5], got.Accounts)
+				require.Equal(t, db.EncodePageToken(last.CreatedAt, last.ID), got.NextPageToken)
+			},
+		},
+		{
+			name:   "Validation fail",
+			params: listParams{PageSize: 101},
+			setupAuthHeader: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authHeaderTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccounts(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -329,17 +361,29 @@ func TestListAccounts(t *testing.T) {
 			},
 		},
 		{
-			name: "DB error",
-			params: struct {
-				PageNum int64
-				Count   int64
-			}{2, 5},
+			name:   "Invalid page token",
+			params: listParams{PageSize: 5, PageToken: "not-a-token"},
+			setupAuthHeader: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authHeaderTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccounts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "DB error",
+			params: listParams{PageSize: 5},
 			setupAuthHeader: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authHeaderTypeBearer, user.ID, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					ListAccounts(gomock.Any(), gomock.Eq(db.ListAccountsParams{UserID: user.ID, Limit: 5, Offset: 5})).
+					ListAccounts(gomock.Any(), gomock.Eq(db.ListAccountsParams{UserID: user.ID, PageLimit: 6})).
 					Times(1).
 					Return([]db.Account{}, sql.ErrConnDone)
 			},
@@ -356,10 +400,10 @@ func TestListAccounts(t *testing.T) {
 
 		tc.buildStubs(store)
 
-		server := newTestServer(t, store)
+		server := newTestServer(t, store, nil)
 		recorder := httptest.NewRecorder()
 
-		url := fmt.Sprintf("/accounts?page_num=%d&count=%d", tc.params.PageNum, tc.params.Count)
+		url := fmt.Sprintf("/accounts?page_size=%d&page_token=%s", tc.params.PageSize, tc.params.PageToken)
 
 		request, err := http.NewRequest(http.MethodGet, url, nil)
 		tc.setupAuthHeader(t, request, server.tokenMaker)
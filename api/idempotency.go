@@ -0,0 +1,31 @@
+package api
+
+import (
+	db "bank/db/sqlc"
+	"bank/validation"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const idempotencyHeaderName = "Idempotency-Key"
+
+// idempotencyParams reads the optional Idempotency-Key header.
+// It returns nil params when the header is absent.
+func (server *Server) idempotencyParams(ctx *gin.Context, userID int64) (*db.IdempotencyParams, error) {
+	key := ctx.GetHeader(idempotencyHeaderName)
+	if len(key) == 0 {
+		return nil, nil
+	}
+
+	if valErr := validation.ValidateIdempotencyKey(key); valErr != nil {
+		return nil, fmt.Errorf("%s %w", valErr.Field, valErr.Error)
+	}
+
+	return &db.IdempotencyParams{
+		UserID:    userID,
+		Key:       key,
+		ExpiresAt: time.Now().Add(server.config.IdempotencyKeyTTL),
+	}, nil
+}
@@ -1,25 +1,57 @@
 package api
 
 import (
+	"bank/async"
 	db "bank/db/sqlc"
+	"bank/token"
 	"bank/utils"
+	"context"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
-func newTestServer(t *testing.T, store db.Store) *Server {
+func newTestServer(t *testing.T, store db.Store, taskDistributor async.TaskDistributor) *Server {
 	srv, err := NewServer(utils.Config{
 		TokenSymmetricKey:   utils.RandomString(32),
 		AccessTokenDuration: time.Minute,
-	}, store)
+	}, store, taskDistributor)
 	require.NoError(t, err)
+	srv.revocation = token.NewRevocationChecker(notRevokedStore{}, 0)
+	srv.setupRouter()
 	return srv
 }
 
+// notRevokedStore lets every token bound to a test session pass the revocation check,
+// so that the tests of the handlers only stub the queries of the handlers.
+type notRevokedStore struct{}
+
+// testSessions maps the sessions of the test tokens onto their users.
+var testSessions sync.Map
+
+func newTestSession(userID int64) uuid.UUID {
+	sessionID := uuid.New()
+	testSessions.Store(sessionID, userID)
+	return sessionID
+}
+
+func (notRevokedStore) GetUser(ctx context.Context, id int64) (db.User, error) {
+	return db.User{ID: id}, nil
+}
+
+func (notRevokedStore) GetSession(ctx context.Context, id uuid.UUID) (db.Session, error) {
+	userID, ok := testSessions.Load(id)
+	if !ok {
+		return db.Session{}, db.ErrRecordNotFound
+	}
+	return db.Session{ID: id, UserID: userID.(int64)}, nil
+}
+
 func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
@@ -15,7 +15,7 @@ const (
 	authPayloadKey       = "authPayloadKey"
 )
 
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+func authMiddleware(tokenMaker token.Maker, revocation *token.RevocationChecker) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader(authHeaderName)
 		if len(authHeader) == 0 {
@@ -43,6 +43,14 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
 			return
 		}
+		if err = authPayload.CheckType(token.TokenTypeAccess); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		if err = revocation.Check(ctx, authPayload); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
 		ctx.Set(authPayloadKey, authPayload)
 		ctx.Next()
 	}
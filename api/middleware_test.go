@@ -21,7 +21,7 @@ func addAuthorization(
 	userID int64,
 	duration time.Duration,
 ) {
-	token, payload, err := tokenMaker.CreateToken(userID, utils.Depositor, duration)
+	token, payload, err := tokenMaker.CreateToken(userID, utils.Depositor, token.TokenTypeAccess, newTestSession(userID), duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -79,17 +79,28 @@ func TestAuthMiddleware(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name: "RefreshToken",
+			setupAuthHeader: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				refreshToken, _, err := tokenMaker.CreateToken(1, utils.Depositor, token.TokenTypeRefresh, newTestSession(1), time.Minute)
+				require.NoError(t, err)
+				request.Header.Set(authHeaderName, fmt.Sprintf("%s %s", authHeaderTypeBearer, refreshToken))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 	}
 
 	for i := range testCases {
 		tc := testCases[i]
 
 		t.Run(tc.name, func(t *testing.T) {
-			server := newTestServer(t, nil)
+			server := newTestServer(t, nil, nil)
 			authPath := "/auth"
 			server.router.GET(
 				authPath,
-				authMiddleware(server.tokenMaker),
+				authMiddleware(server.tokenMaker, server.revocation),
 				func(ctx *gin.Context) {
 					ctx.JSON(http.StatusOK, gin.H{})
 				},
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"bank/async"
 	db "bank/db/sqlc"
+	"bank/fx"
+	"bank/lockout"
 	"bank/token"
 	"bank/utils"
 
@@ -11,21 +14,33 @@ import (
 )
 
 type Server struct {
-	store      db.Store
-	router     *gin.Engine
-	tokenMaker token.Maker
-	config     *utils.Config
+	store           db.Store
+	router          *gin.Engine
+	tokenMaker      token.Maker
+	revocation      *token.RevocationChecker
+	config          *utils.Config
+	fxRateProvider  fx.FXRateProvider
+	loginGuard      *lockout.Guard
+	taskDistributor async.TaskDistributor
 }
 
-func NewServer(config utils.Config, store db.Store) (*Server, error) {
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+func NewServer(config utils.Config, store db.Store, taskDistributor async.TaskDistributor) (*Server, error) {
+	tokenMaker, err := token.NewMaker(config)
+	if err != nil {
+		return nil, err
+	}
+	fxRateProvider, err := fx.NewRateProvider(config, store)
 	if err != nil {
 		return nil, err
 	}
 	server := &Server{
-		store:      store,
-		tokenMaker: tokenMaker,
-		config:     &config,
+		store:           store,
+		tokenMaker:      tokenMaker,
+		revocation:      token.NewRevocationChecker(store, config.RevocationCacheTTL),
+		config:          &config,
+		fxRateProvider:  fxRateProvider,
+		loginGuard:      lockout.NewGuard(store, config),
+		taskDistributor: taskDistributor,
 	}
 
 	server.setupRouter()
@@ -42,12 +57,14 @@ func (server *Server) setupRouter() {
 
 	router.POST("/users", server.createUser)
 	router.POST("/users/login", server.loginUser)
+	router.POST("/users/login/mfa", server.loginUserMFA)
 	router.POST("/token/renew", server.renewToken)
 
-	auth := router.Group("/", authMiddleware(server.tokenMaker))
+	auth := router.Group("/", authMiddleware(server.tokenMaker, server.revocation))
 	auth.POST("/accounts", server.createAccount)
 	auth.GET("/accounts/:id", server.getAccount)
 	auth.GET("/accounts", server.listAccounts)
+	auth.GET("/accounts/:id/statement", server.getAccountStatement)
 	auth.POST("/transfers", server.createTransfer)
 
 	server.router = router
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bank/token"
 	"bank/utils"
 	"fmt"
 	"net/http"
@@ -31,6 +32,10 @@ func (server *Server) renewToken(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
+	if err = payload.CheckType(token.TokenTypeRefresh); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
 
 	if payload.ExpiresAt.Before(time.Now()) {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("token expired")))
@@ -59,7 +64,7 @@ func (server *Server) renewToken(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), server.config.AccessTokenDuration)
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), token.TokenTypeAccess, session.FamilyID, server.config.AccessTokenDuration)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
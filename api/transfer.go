@@ -1,14 +1,19 @@
 package api
 
 import (
+	"bank/async"
 	db "bank/db/sqlc"
-	"database/sql"
+	"bank/fx"
+	"bank/mfa"
+	"bank/utils"
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
 )
 
 type createTransferRequest struct {
@@ -16,6 +21,7 @@ type createTransferRequest struct {
 	ToAccountID   int64  `json:"to_account_id" binding:"required"`
 	Currency      string `json:"currency" binding:"required,currency"`
 	Amount        int64  `json:"amount" binding:"required,gt=0"`
+	TOTPCode      string `json:"totp_code"`
 }
 
 func (server *Server) createTransfer(ctx *gin.Context) {
@@ -27,22 +33,44 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 	}
 
 	userID, _ := getUserIdFromAuth(ctx)
-	_, err = server.store.GetUserAccount(ctx, db.GetUserAccountParams{userID, request.FromAccountID})
+	_, err = server.store.GetUserAccount(ctx, db.GetUserAccountParams{UserID: userID, ID: request.FromAccountID})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, db.ErrRecordNotFound) {
 			ctx.JSON(http.StatusForbidden, errorResponse(errors.New("forbidden")))
 			return
 		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
+	if err = server.checkEmailVerified(ctx, userID); err != nil {
+		return
+	}
+
+	idempotency, err := server.idempotencyParams(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	fromAccount, err := server.validAccount(ctx, request.FromAccountID, request.Currency)
 	if err != nil {
 		return
 	}
 
-	toAccount, err := server.validAccount(ctx, request.ToAccountID, request.Currency)
+	toAccount, err := server.fetchAccount(ctx, request.ToAccountID)
+	if err != nil {
+		return
+	}
+
+	rate, err := server.fxRateProvider.GetRate(ctx, fromAccount.Currency, toAccount.Currency)
+	if err != nil {
+		ctx.JSON(fxRateErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	exponents, err := utils.CurrencyExponents(fromAccount.Currency, toAccount.Currency)
 	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
@@ -50,26 +78,78 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		FromAccountID: fromAccount.ID,
 		ToAccountID:   toAccount.ID,
 		Amount:        request.Amount,
+		ExchangeRate:  rate,
+		Exponents:     exponents,
+		Idempotency:   idempotency,
+		// the code is spent last and within the transaction, so a failed transfer or a replay doesn't burn it
+		BeforeTransfer: func(txCtx context.Context) error {
+			return server.checkTransferMFA(txCtx, userID, request.Amount, request.TOTPCode)
+		},
+		// txCtx carries the transaction, so that the outbox distributor writes the task along with the transfer
+		AfterTransfer: func(txCtx context.Context, transfer db.Transfer) error {
+			payload := &async.PayloadNotifyTransfer{TransferID: transfer.ID}
+			return server.taskDistributor.DistributeTaskNotifyTransfer(txCtx, payload, asynq.MaxRetry(10))
+		},
 	}
 
 	result, err := server.store.TransferTx(ctx, arg)
 	if err != nil {
-		log.Println(err)
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(transferErrorStatus(err), errorResponse(err))
 		return
 	}
 
 	ctx.JSON(http.StatusCreated, result)
 }
 
-func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (*db.Account, error) {
-	account, err := server.store.GetAccount(ctx, accountID)
+// checkEmailVerified keeps users who haven't verified their email from sending money.
+// It writes the error response itself.
+func (server *Server) checkEmailVerified(ctx *gin.Context, userID int64) error {
+	user, err := server.store.GetUser(ctx, userID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, db.ErrRecordNotFound) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return nil, err
+			return err
 		}
+		log.Println(err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return err
+	}
+
+	if !user.IsVerified.Bool {
+		err = errors.New("email must be verified before sending transfers")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return err
+	}
+
+	return nil
+}
+
+var errMFACodeRequired = errors.New("two-factor code is required")
 
+// checkTransferMFA asks users with two-factor authentication for a fresh code
+// when the amount reaches the configured threshold. It uses the transaction ctx carries, if any.
+func (server *Server) checkTransferMFA(ctx context.Context, userID int64, amount int64, code string) error {
+	threshold := server.config.TransferMFAThreshold
+	if threshold <= 0 || amount < threshold {
+		return nil
+	}
+
+	querier := db.QuerierFromContext(ctx, server.store)
+	enabled, err := mfa.Enabled(ctx, querier, userID)
+	if err != nil || !enabled {
+		return err
+	}
+
+	if code == "" {
+		return errMFACodeRequired
+	}
+
+	return mfa.Verify(ctx, querier, userID, code)
+}
+
+func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (*db.Account, error) {
+	account, err := server.fetchAccount(ctx, accountID)
+	if err != nil {
 		return nil, err
 	}
 
@@ -78,5 +158,50 @@ func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency s
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return nil, err
 	}
+	return account, nil
+}
+
+func (server *Server) fetchAccount(ctx *gin.Context, accountID int64) (*db.Account, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return nil, err
+		}
+		log.Println(err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return nil, err
+	}
 	return &account, nil
 }
+
+// transferErrorStatus maps the domain errors of db.TransferTx to HTTP statuses.
+func transferErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrInsufficientFunds), errors.Is(err, db.ErrIdempotencyKeyReused),
+		errors.Is(err, db.ErrAccountFrozen), errors.Is(err, db.ErrAccountClosed):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, db.ErrAccountNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrSameAccount), errors.Is(err, db.ErrCurrencyMismatch), errors.Is(err, db.ErrAmountTooSmall):
+		return http.StatusBadRequest
+	case errors.Is(err, errMFACodeRequired), errors.Is(err, mfa.ErrInvalidCode):
+		return http.StatusForbidden
+	}
+
+	log.Println(err)
+	return http.StatusInternalServerError
+}
+
+// fxRateErrorStatus maps the errors of fx.FXRateProvider to HTTP statuses.
+func fxRateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, fx.ErrUnsupportedPair):
+		return http.StatusBadRequest
+	case errors.Is(err, fx.ErrRateNotFound):
+		return http.StatusUnprocessableEntity
+	}
+
+	log.Println(err)
+	return http.StatusInternalServerError
+}
@@ -1,34 +1,67 @@
 package api
 
 import (
+	"bank/async"
+	mockasync "bank/async/mock"
 	mockdb "bank/db/mock"
 	db "bank/db/sqlc"
 	"bank/token"
+	"bank/utils"
 	"bytes"
+	"context"
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
+// eqTransferTxParams matches the tx params by the transfer, as the callbacks can't be compared.
+func eqTransferTxParams(args db.TransferTxParams) gomock.Matcher {
+	return gomock.Cond(func(x any) bool {
+		params, isOk := x.(db.TransferTxParams)
+		params.BeforeTransfer, params.AfterTransfer = nil, nil
+		return isOk && reflect.DeepEqual(params, args)
+	})
+}
+
+// runTransferHooks stands in for the transaction of db.TransferTx and runs its callbacks.
+func runTransferHooks(result db.TransferTxResult) func(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+	return func(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+		if err := arg.BeforeTransfer(ctx); err != nil {
+			return db.TransferTxResult{}, err
+		}
+		if err := arg.AfterTransfer(ctx, result.Transfer); err != nil {
+			return db.TransferTxResult{}, err
+		}
+		return result, nil
+	}
+}
+
 func TestCreateTransfer(t *testing.T) {
 	user1 := randomUser("password")
+	user1.IsVerified = pgtype.Bool{Bool: true, Valid: true}
+	unverifiedUser := user1
+	unverifiedUser.IsVerified = pgtype.Bool{Bool: false, Valid: true}
 	user2 := randomUser("password1")
 	acc1 := randomAccount(user1.ID)
 	acc2 := randomAccount(user2.ID)
-	// acc3 := randomAccount()
-	acc1.Currency, acc2.Currency = "USD", "USD"
+	acc3 := randomAccount(user2.ID)
+	acc1.Currency, acc2.Currency, acc3.Currency = "USD", "USD", "EUR"
+	transfer := db.Transfer{ID: utils.RandomInt(1, 1000), FromAccountID: acc1.ID, ToAccountID: acc2.ID, Amount: 100}
 
 	testCases := []struct {
 		name            string
 		body            gin.H
 		setupAuthHeader func(t *testing.T, request *http.Request, tokenMaker token.Maker)
-		buildStubs      func(store *mockdb.MockStore)
+		buildStubs      func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor)
 		checkResponse   func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
@@ -42,8 +75,9 @@ func TestCreateTransfer(t *testing.T) {
 			setupAuthHeader: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authHeaderTypeBearer, acc1.UserID, time.Minute)
 			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().GetUserAccount(gomock.Any(), gomock.Eq(db.GetUserAccountParams{acc1.UserID, acc1.ID})).Times(1).Return(acc1, nil)
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUserAccount(gomock.Any(), gomock.Eq(db.GetUserAccountParams{UserID: acc1.UserID, ID: acc1.ID})).Times(1).Return(acc1, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
 
@@ -51,24 +85,163 @@ func TestCreateTransfer(t *testing.T) {
 					FromAccountID: acc1.ID,
 					ToAccountID:   acc2.ID,
 					Amount:        int64(100),
+					ExchangeRate:  1,
+					Exponents:     map[string]int32{"USD": 2},
+				}
+
+				store.EXPECT().
+					TransferTx(gomock.Any(), eqTransferTxParams(arg)).
+					Times(1).
+					DoAndReturn(runTransferHooks(db.TransferTxResult{Transfer: transfer}))
+				distributor.EXPECT().
+					DistributeTaskNotifyTransfer(gomock.Any(), gomock.Eq(&async.PayloadNotifyTransfer{TransferID: transfer.ID}), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "Cross currency",
+			body: gin.H{
+				"from_account_id": acc1.ID,
+				"to_account_id":   acc3.ID,
+				"currency":        "USD",
+				"amount":          int64(100),
+			},
+			setupAuthHeader: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authHeaderTypeBearer, acc1.UserID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUserAccount(gomock.Any(), gomock.Any()).Times(1).Return(acc1, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc3.ID)).Times(1).Return(acc3, nil)
+				// only the inverse pair is stored
+				store.EXPECT().
+					GetFXRate(gomock.Any(), gomock.Eq(db.GetFXRateParams{BaseCurrency: "USD", QuoteCurrency: "EUR"})).
+					Times(1).
+					Return(db.FxRate{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					GetFXRate(gomock.Any(), gomock.Eq(db.GetFXRateParams{BaseCurrency: "EUR", QuoteCurrency: "USD"})).
+					Times(1).
+					Return(db.FxRate{BaseCurrency: "EUR", QuoteCurrency: "USD", Rate: 1.25}, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountID: acc1.ID,
+					ToAccountID:   acc3.ID,
+					Amount:        int64(100),
+					ExchangeRate:  0.8,
+					Exponents:     map[string]int32{"USD": 2, "EUR": 2},
 				}
 
-				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+				store.EXPECT().TransferTx(gomock.Any(), eqTransferTxParams(arg)).Times(1)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusCreated, recorder.Code)
 			},
 		},
+		{
+			name: "Email not verified",
+			body: gin.H{
+				"from_account_id": acc1.ID,
+				"to_account_id":   acc2.ID,
+				"currency":        "USD",
+				"amount":          int64(100),
+			},
+			setupAuthHeader: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authHeaderTypeBearer, acc1.UserID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUserAccount(gomock.Any(), gomock.Any()).Times(1).Return(acc1, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(unverifiedUser, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+				distributor.EXPECT().DistributeTaskNotifyTransfer(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Outbox down",
+			body: gin.H{
+				"from_account_id": acc1.ID,
+				"to_account_id":   acc2.ID,
+				"currency":        "USD",
+				"amount":          int64(100),
+			},
+			setupAuthHeader: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authHeaderTypeBearer, acc1.UserID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUserAccount(gomock.Any(), gomock.Any()).Times(1).Return(acc1, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
+				// the notification can't be written, so the transfer is rolled back
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(runTransferHooks(db.TransferTxResult{Transfer: transfer}))
+				distributor.EXPECT().DistributeTaskNotifyTransfer(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "Insufficient funds",
+			body: gin.H{
+				"from_account_id": acc1.ID,
+				"to_account_id":   acc2.ID,
+				"currency":        "USD",
+				"amount":          int64(100),
+			},
+			setupAuthHeader: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authHeaderTypeBearer, acc1.UserID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUserAccount(gomock.Any(), gomock.Any()).Times(1).Return(acc1, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "Internal error",
+			body: gin.H{
+				"from_account_id": acc1.ID,
+				"to_account_id":   acc2.ID,
+				"currency":        "USD",
+				"amount":          int64(100),
+			},
+			setupAuthHeader: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authHeaderTypeBearer, acc1.UserID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUserAccount(gomock.Any(), gomock.Any()).Times(1).Return(acc1, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		ctrl := gomock.NewController(t)
 
 		store := mockdb.NewMockStore(ctrl)
+		distributor := mockasync.NewMockTaskDistributor(ctrl)
 
-		tc.buildStubs(store)
+		tc.buildStubs(store, distributor)
 
-		server := newTestServer(t, store)
+		server := newTestServer(t, store, distributor)
 		recorder := httptest.NewRecorder()
 
 		payload, _ := json.Marshal(tc.body)
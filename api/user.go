@@ -1,16 +1,21 @@
 package api
 
 import (
+	"bank/async"
 	db "bank/db/sqlc"
+	"bank/lockout"
+	"bank/mfa"
 	"bank/token"
 	"bank/utils"
-	"database/sql"
+	"context"
 	"errors"
 	"log"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
@@ -95,10 +100,15 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
+	if err = server.loginGuard.Check(ctx, request.Email, ctx.ClientIP()); err != nil {
+		ctx.JSON(loginAttemptErrorStatus(err), errorResponse(err))
+		return
+	}
+
 	user, err := server.store.GetUserByEmail(ctx, request.Email)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "user doesn't exist"})
+		if errors.Is(err, db.ErrRecordNotFound) {
+			server.failLogin(ctx, request.Email, nil)
 			return
 		}
 		log.Println(err)
@@ -108,31 +118,169 @@ func (server *Server) loginUser(ctx *gin.Context) {
 
 	err = utils.CompareHashAndPassword(user.HashedPassword, request.Password)
 	if err != nil {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "wrong password"})
+		server.failLogin(ctx, request.Email, &user.ID)
+		return
+	}
+
+	mfaRequired, err := mfa.Enabled(ctx, server.store, user.ID)
+	if err != nil {
+		log.Println(err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if mfaRequired {
+		challenge, err := mfa.StartChallenge(ctx, server.store, user.ID, server.config.MFAChallengeDuration)
+		if err != nil {
+			log.Println(err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusOK, mfaChallengeResponse{
+			MFARequired:       true,
+			MFAToken:          challenge.ID.String(),
+			MFATokenExpiresAt: challenge.ExpiresAt,
+		})
+		return
+	}
+
+	server.createLoginTokens(ctx, user)
+}
+
+var errInvalidCredentials = errors.New("invalid email or password")
+
+// failLogin records the failed attempt. An unknown email and a wrong password get the same answer,
+// so that the accounts can't be enumerated.
+func (server *Server) failLogin(ctx *gin.Context, email string, userID *int64) {
+	if err := server.loginGuard.Fail(ctx, email, ctx.ClientIP(), userID); err != nil {
+		log.Println(err)
+	}
+	ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidCredentials))
+}
+
+// loginAttemptErrorStatus maps the errors of lockout.Guard to HTTP statuses.
+func loginAttemptErrorStatus(err error) int {
+	var attemptErr *lockout.AttemptError
+	if errors.As(err, &attemptErr) {
+		return http.StatusTooManyRequests
+	}
+
+	log.Println(err)
+	return http.StatusInternalServerError
+}
+
+type mfaChallengeResponse struct {
+	MFARequired       bool      `json:"mfa_required"`
+	MFAToken          string    `json:"mfa_token"`
+	MFATokenExpiresAt time.Time `json:"mfa_token_expires_at"`
+}
+
+type loginUserMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required,uuid"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// loginUserMFA is the second step of the login for users with two-factor authentication.
+func (server *Server) loginUserMFA(ctx *gin.Context) {
+	var request loginUserMFARequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	challengeID := uuid.MustParse(request.MFAToken)
+	userID, err := mfa.ChallengeUser(ctx, server.store, challengeID)
+	if err != nil {
+		if errors.Is(err, mfa.ErrInvalidChallenge) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		log.Println(err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, userID)
+	if err != nil {
+		log.Println(err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// a locked out email gets no codes tried, however many challenges its password opened
+	if err = server.loginGuard.Check(ctx, user.Email, ctx.ClientIP()); err != nil {
+		ctx.JSON(loginAttemptErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	_, err = mfa.CompleteChallenge(ctx, server.store, challengeID, request.Code)
+	if err != nil {
+		if errors.Is(err, mfa.ErrInvalidCode) {
+			server.failLoginMFA(ctx, user)
+		}
+		if errors.Is(err, mfa.ErrInvalidChallenge) || errors.Is(err, mfa.ErrInvalidCode) || errors.Is(err, mfa.ErrNotEnrolled) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		log.Println(err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), server.config.AccessTokenDuration)
+	server.createLoginTokens(ctx, user)
+}
+
+// failLoginMFA counts a wrong code as a failed login of the user, so that fresh challenges
+// don't give unlimited tries at the second factor.
+func (server *Server) failLoginMFA(ctx *gin.Context, user db.User) {
+	if err := server.loginGuard.Fail(ctx, user.Email, ctx.ClientIP(), &user.ID); err != nil {
+		log.Println(err)
+	}
+}
+
+// createLoginTokens starts a new session of the user and responds with its token pair.
+// Only then has the login succeeded, with the second factor if any, and the failures are forgotten.
+// A login from a new device is reported to the user by email.
+func (server *Server) createLoginTokens(ctx *gin.Context, user db.User) {
+	if err := server.loginGuard.Succeed(ctx, user.Email); err != nil {
+		log.Println(err)
+	}
+
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), token.TokenTypeRefresh, uuid.Nil, server.config.RefreshTokenDuration)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), server.config.RefreshTokenDuration)
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), token.TokenTypeAccess, refreshPayload.ID, server.config.AccessTokenDuration)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	server.store.CreateSession(ctx, db.CreateSessionParams{
-		ID:           refreshPayload.ID,
-		UserID:       user.ID,
-		RefreshToken: refreshToken,
-		UserAgent:    ctx.Request.UserAgent(),
-		ClientIp:     ctx.ClientIP(),
-		IsBlocked:    false,
-		ExpiresAt:    time.Now().Add(server.config.RefreshTokenDuration),
+	// the access token authenticates only while its session exists, so the login fails without one
+	_, err = server.store.CreateLoginSessionTx(ctx, db.CreateLoginSessionTxParams{
+		CreateSessionParams: db.CreateSessionParams{
+			ID:           refreshPayload.ID,
+			UserID:       user.ID,
+			RefreshToken: refreshToken,
+			UserAgent:    ctx.Request.UserAgent(),
+			ClientIp:     ctx.ClientIP(),
+			IsBlocked:    false,
+			ExpiresAt:    refreshPayload.ExpiresAt,
+			FamilyID:     refreshPayload.ID,
+		},
+		// txCtx carries the transaction, so that the alert is written to the outbox along with the session
+		AfterNewDevice: func(txCtx context.Context, session db.Session) error {
+			payload := &async.PayloadNotifyNewDeviceLogin{SessionID: session.ID}
+			return server.taskDistributor.DistributeTaskNotifyNewDeviceLogin(txCtx, payload, asynq.MaxRetry(10))
+		},
 	})
+	if err != nil {
+		log.Println(err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
 
 	rsp := loginUserResponse{
 		accessToken,
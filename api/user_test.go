@@ -5,13 +5,17 @@ import (
 	db "bank/db/sqlc"
 	"bank/utils"
 	"bytes"
+	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -83,7 +87,7 @@ func TestCreateUser(t *testing.T) {
 
 		tc.buildStubs(store)
 
-		server := newTestServer(t, store)
+		server := newTestServer(t, store, nil)
 		recorder := httptest.NewRecorder()
 
 		payload, _ := json.Marshal(tc.params)
@@ -120,7 +124,12 @@ func TestLoginUserAPI(t *testing.T) {
 					Return(user, nil)
 
 				store.EXPECT().
-					CreateSession(gomock.Any(), gomock.Any()).
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(db.UserTotp{}, db.ErrRecordNotFound)
+
+				store.EXPECT().
+					CreateLoginSessionTx(gomock.Any(), gomock.Any()).
 					Times(1)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -140,6 +149,116 @@ func TestLoginUserAPI(t *testing.T) {
 				require.Empty(t, response.User.HashedPassword)
 			},
 		},
+		{
+			name: "Session not created",
+			params: gin.H{
+				"password": password,
+				"email":    user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(db.UserTotp{}, db.ErrRecordNotFound)
+
+				store.EXPECT().
+					CreateLoginSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateLoginSessionTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "access_token")
+			},
+		},
+		{
+			name: "MFA required",
+			params: gin.H{
+				"password": password,
+				"email":    user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(db.UserTotp{UserID: user.ID, ConfirmedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}, nil)
+
+				store.EXPECT().
+					CreateMFAChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateMFAChallengeParams) (db.MfaChallenge, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						return db.MfaChallenge{ID: arg.ID, UserID: arg.UserID, ExpiresAt: arg.ExpiresAt}, nil
+					})
+
+				store.EXPECT().
+					CreateLoginSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response mfaChallengeResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+
+				require.True(t, response.MFARequired)
+				require.NotEmpty(t, response.MFAToken)
+				require.NotContains(t, recorder.Body.String(), "access_token")
+			},
+		},
+		{
+			name: "Unknown email",
+			params: gin.H{
+				"password": password,
+				"email":    "unknown@example.com",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+
+				store.EXPECT().
+					CreateLoginSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errInvalidCredentials.Error())
+			},
+		},
+		{
+			name: "Wrong password",
+			params: gin.H{
+				"password": "wrong-password",
+				"email":    user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateLoginSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errInvalidCredentials.Error())
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -149,7 +268,7 @@ func TestLoginUserAPI(t *testing.T) {
 
 		tc.buildStubs(store)
 
-		server := newTestServer(t, store)
+		server := newTestServer(t, store, nil)
 		recorder := httptest.NewRecorder()
 
 		payload, _ := json.Marshal(tc.params)
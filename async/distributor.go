@@ -8,6 +8,9 @@ import (
 
 type TaskDistributor interface {
 	DistributeTaskVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail, opt ...asynq.Option) error
+	DistributeTaskPasswordReset(ctx context.Context, payload *PayloadSendPasswordReset, opt ...asynq.Option) error
+	DistributeTaskNotifyTransfer(ctx context.Context, payload *PayloadNotifyTransfer, opt ...asynq.Option) error
+	DistributeTaskNotifyNewDeviceLogin(ctx context.Context, payload *PayloadNotifyNewDeviceLogin, opt ...asynq.Option) error
 }
 
 type RedisTaskDistributor struct {
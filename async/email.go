@@ -0,0 +1,39 @@
+package async
+
+import (
+	db "bank/db/sqlc"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// sendEmail renders the template in the locale of the user and sends it to the user.
+func (r *RedisTaskProcessor) sendEmail(template string, user db.User, data any) error {
+	email, err := r.renderer.Render(template, user.Locale, data)
+	if err != nil {
+		return err
+	}
+	email.To = []string{user.Email}
+
+	if err = r.mailSender.Send(email); err != nil {
+		return fmt.Errorf("failed to send a %s email to %s: %w", template, user.Email, err)
+	}
+	return nil
+}
+
+// link builds a public URL of the bank from the configured base URL.
+func (r *RedisTaskProcessor) link(path string, query url.Values) string {
+	return withQuery(strings.TrimRight(r.baseURL, "/")+path, query)
+}
+
+// withQuery adds the query to the URL of a page, which may have a query of its own.
+func withQuery(page string, query url.Values) string {
+	if len(query) == 0 {
+		return page
+	}
+	separator := "?"
+	if strings.Contains(page, "?") {
+		separator = "&"
+	}
+	return page + separator + query.Encode()
+}
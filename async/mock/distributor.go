@@ -41,6 +41,63 @@ func (m *MockTaskDistributor) EXPECT() *MockTaskDistributorMockRecorder {
 	return m.recorder
 }
 
+// DistributeTaskNotifyNewDeviceLogin mocks base method.
+func (m *MockTaskDistributor) DistributeTaskNotifyNewDeviceLogin(arg0 context.Context, arg1 *async.PayloadNotifyNewDeviceLogin, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DistributeTaskNotifyNewDeviceLogin", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DistributeTaskNotifyNewDeviceLogin indicates an expected call of DistributeTaskNotifyNewDeviceLogin.
+func (mr *MockTaskDistributorMockRecorder) DistributeTaskNotifyNewDeviceLogin(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskNotifyNewDeviceLogin", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskNotifyNewDeviceLogin), varargs...)
+}
+
+// DistributeTaskNotifyTransfer mocks base method.
+func (m *MockTaskDistributor) DistributeTaskNotifyTransfer(arg0 context.Context, arg1 *async.PayloadNotifyTransfer, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DistributeTaskNotifyTransfer", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DistributeTaskNotifyTransfer indicates an expected call of DistributeTaskNotifyTransfer.
+func (mr *MockTaskDistributorMockRecorder) DistributeTaskNotifyTransfer(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskNotifyTransfer", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskNotifyTransfer), varargs...)
+}
+
+// DistributeTaskPasswordReset mocks base method.
+func (m *MockTaskDistributor) DistributeTaskPasswordReset(arg0 context.Context, arg1 *async.PayloadSendPasswordReset, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DistributeTaskPasswordReset", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DistributeTaskPasswordReset indicates an expected call of DistributeTaskPasswordReset.
+func (mr *MockTaskDistributorMockRecorder) DistributeTaskPasswordReset(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskPasswordReset", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskPasswordReset), varargs...)
+}
+
 // DistributeTaskVerifyEmail mocks base method.
 func (m *MockTaskDistributor) DistributeTaskVerifyEmail(arg0 context.Context, arg1 *async.PayloadSendVerifyEmail, arg2 ...asynq.Option) error {
 	m.ctrl.T.Helper()
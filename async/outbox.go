@@ -0,0 +1,211 @@
+package async
+
+import (
+	db "bank/db/sqlc"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+// OutboxTaskDistributor writes the tasks to the outbox table, an OutboxRelay publishes them to asynq afterwards.
+// Tasks distributed with the ctx of a store transaction commit or roll back together with it.
+type OutboxTaskDistributor struct {
+	store db.Store
+}
+
+func NewOutboxTaskDistributor(store db.Store) TaskDistributor {
+	return &OutboxTaskDistributor{
+		store: store,
+	}
+}
+
+// DistributeTaskVerifyEmail implements TaskDistributor.
+func (d *OutboxTaskDistributor) DistributeTaskVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail, opt ...asynq.Option) error {
+	return d.distribute(ctx, taskNameSendVerifyEmail, payload, opt)
+}
+
+// DistributeTaskPasswordReset implements TaskDistributor.
+func (d *OutboxTaskDistributor) DistributeTaskPasswordReset(ctx context.Context, payload *PayloadSendPasswordReset, opt ...asynq.Option) error {
+	return d.distribute(ctx, taskNameSendPasswordReset, payload, opt)
+}
+
+// DistributeTaskNotifyTransfer implements TaskDistributor.
+func (d *OutboxTaskDistributor) DistributeTaskNotifyTransfer(ctx context.Context, payload *PayloadNotifyTransfer, opt ...asynq.Option) error {
+	return d.distribute(ctx, taskNameNotifyTransfer, payload, opt)
+}
+
+// DistributeTaskNotifyNewDeviceLogin implements TaskDistributor.
+func (d *OutboxTaskDistributor) DistributeTaskNotifyNewDeviceLogin(ctx context.Context, payload *PayloadNotifyNewDeviceLogin, opt ...asynq.Option) error {
+	return d.distribute(ctx, taskNameNotifyNewDeviceLogin, payload, opt)
+}
+
+func (d *OutboxTaskDistributor) distribute(ctx context.Context, taskType string, payload any, opts []asynq.Option) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal task payload: %w", err)
+	}
+
+	arg := db.CreateOutboxTaskParams{
+		TaskType: taskType,
+		Payload:  payloadBytes,
+	}
+	for _, opt := range opts {
+		switch opt.Type() {
+		case asynq.QueueOpt:
+			arg.Queue = opt.Value().(string)
+		case asynq.MaxRetryOpt:
+			arg.MaxRetry = pgtype.Int4{Int32: int32(opt.Value().(int)), Valid: true}
+		case asynq.TimeoutOpt:
+			arg.Timeout = pgtype.Int8{Int64: int64(opt.Value().(time.Duration)), Valid: true}
+		case asynq.ProcessInOpt:
+			// the delay counts from now rather than from the moment the relay gets to the task
+			arg.ProcessAt = pgtype.Timestamptz{Time: time.Now().Add(opt.Value().(time.Duration)), Valid: true}
+		case asynq.ProcessAtOpt:
+			arg.ProcessAt = pgtype.Timestamptz{Time: opt.Value().(time.Time), Valid: true}
+		default:
+			return fmt.Errorf("task option %s isn't supported by the outbox", opt)
+		}
+	}
+
+	task, err := db.QuerierFromContext(ctx, d.store).CreateOutboxTask(ctx, arg)
+	if err != nil {
+		return fmt.Errorf("couldn't write task to the outbox: %w", err)
+	}
+
+	log.Info().Str("type", task.TaskType).Int64("outbox_id", task.ID).Bytes("payload", task.Payload).
+		Msg("written task to the outbox")
+
+	return nil
+}
+
+const (
+	outboxBatchSize       = 100
+	outboxMaxBackoff      = 5 * time.Minute
+	outboxRetention       = 7 * 24 * time.Hour
+	outboxCleanupInterval = time.Hour
+)
+
+type taskEnqueuer interface {
+	EnqueueContext(ctx context.Context, task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error)
+}
+
+// OutboxRelay publishes the outbox tasks to asynq, retrying the failed ones with a backoff.
+type OutboxRelay struct {
+	store       db.Store
+	client      taskEnqueuer
+	interval    time.Duration
+	lastCleanup time.Time
+}
+
+func NewOutboxRelay(store db.Store, redisOpt asynq.RedisClientOpt, interval time.Duration) *OutboxRelay {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &OutboxRelay{
+		store:    store,
+		client:   asynq.NewClient(redisOpt),
+		interval: interval,
+	}
+}
+
+// Run relays the outbox until ctx is done.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		// a full batch means there may be more due tasks, so the next one goes without waiting
+		result, err := r.RelayOnce(ctx)
+		if err != nil {
+			log.Err(err).Msg("failed to relay the outbox")
+		}
+		if err == nil && result.Sent+result.Failed == outboxBatchSize {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce publishes one batch of due outbox tasks and from time to time deletes the long sent ones.
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (db.RelayOutboxTxResult, error) {
+	result, err := r.store.RelayOutboxTx(ctx, db.RelayOutboxTxParams{
+		Limit: outboxBatchSize,
+		Publish: func(task db.Outbox) error {
+			return r.publish(ctx, task)
+		},
+		Backoff: outboxBackoff,
+	})
+	if err != nil {
+		return result, err
+	}
+	if result.Failed > 0 {
+		log.Warn().Int("sent", result.Sent).Int("failed", result.Failed).Msg("relayed the outbox with failures")
+	}
+
+	if time.Since(r.lastCleanup) >= outboxCleanupInterval {
+		deleted, err := r.store.DeleteSentOutboxTasks(ctx, time.Now().Add(-outboxRetention))
+		if err != nil {
+			return result, fmt.Errorf("couldn't delete sent outbox tasks: %w", err)
+		}
+		r.lastCleanup = time.Now()
+		log.Info().Int64("deleted", deleted).Msg("deleted sent outbox tasks")
+	}
+
+	return result, nil
+}
+
+func (r *OutboxRelay) publish(ctx context.Context, outboxTask db.Outbox) error {
+	// the ID makes a task published again after the outbox failed to record it as sent a conflict
+	opts := []asynq.Option{asynq.TaskID(fmt.Sprintf("outbox:%d", outboxTask.ID))}
+	if outboxTask.Queue != "" {
+		opts = append(opts, asynq.Queue(outboxTask.Queue))
+	}
+	if outboxTask.MaxRetry.Valid {
+		opts = append(opts, asynq.MaxRetry(int(outboxTask.MaxRetry.Int32)))
+	}
+	if outboxTask.Timeout.Valid {
+		opts = append(opts, asynq.Timeout(time.Duration(outboxTask.Timeout.Int64)))
+	}
+	if outboxTask.ProcessAt.Valid {
+		opts = append(opts, asynq.ProcessAt(outboxTask.ProcessAt.Time))
+	}
+
+	task := asynq.NewTask(outboxTask.TaskType, outboxTask.Payload, opts...)
+	info, err := r.client.EnqueueContext(ctx, task)
+	if err != nil {
+		if errors.Is(err, asynq.ErrTaskIDConflict) {
+			return nil
+		}
+		return fmt.Errorf("couldn't enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("outbox_id", outboxTask.ID).Str("queue", info.Queue).
+		Bytes("payload", task.Payload()).Int("max_retry", info.MaxRetry).Msg("enqueued task")
+
+	return nil
+}
+
+// outboxBackoff doubles the wait with every failed attempt, starting from a second.
+func outboxBackoff(attempts int32) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 10 {
+		return outboxMaxBackoff
+	}
+	return min(time.Second<<(attempts-1), outboxMaxBackoff)
+}
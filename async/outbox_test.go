@@ -0,0 +1,106 @@
+package async
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type fakeEnqueuer struct {
+	tasks []*asynq.Task
+	err   error
+}
+
+func (e *fakeEnqueuer) EnqueueContext(ctx context.Context, task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	e.tasks = append(e.tasks, task)
+	return &asynq.TaskInfo{Queue: "default"}, nil
+}
+
+func TestOutboxTaskDistributor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	distributor := NewOutboxTaskDistributor(store)
+
+	processAt := time.Now().Add(time.Hour)
+	store.EXPECT().
+		CreateOutboxTask(gomock.Any(), gomock.Eq(db.CreateOutboxTaskParams{
+			TaskType:  taskNameSendVerifyEmail,
+			Payload:   []byte(`{"user_id":7}`),
+			Queue:     "critical",
+			MaxRetry:  pgtype.Int4{Int32: 5, Valid: true},
+			Timeout:   pgtype.Int8{Int64: int64(time.Minute), Valid: true},
+			ProcessAt: pgtype.Timestamptz{Time: processAt, Valid: true},
+		})).
+		Times(1).
+		Return(db.Outbox{ID: 1}, nil)
+
+	err := distributor.DistributeTaskVerifyEmail(context.Background(), &PayloadSendVerifyEmail{UserID: 7},
+		asynq.Queue("critical"), asynq.MaxRetry(5), asynq.Timeout(time.Minute), asynq.ProcessAt(processAt))
+	require.NoError(t, err)
+
+	err = distributor.DistributeTaskVerifyEmail(context.Background(), &PayloadSendVerifyEmail{UserID: 7}, asynq.Unique(time.Minute))
+	require.ErrorContains(t, err, "isn't supported")
+}
+
+func TestOutboxRelayPublish(t *testing.T) {
+	enqueuer := &fakeEnqueuer{}
+	relay := &OutboxRelay{client: enqueuer}
+
+	err := relay.publish(context.Background(), db.Outbox{ID: 42, TaskType: taskNameNotifyTransfer, Payload: []byte(`{"transfer_id":1}`)})
+	require.NoError(t, err)
+	require.Len(t, enqueuer.tasks, 1)
+	require.Equal(t, taskNameNotifyTransfer, enqueuer.tasks[0].Type())
+
+	// the task was published before but not recorded as sent
+	enqueuer.err = asynq.ErrTaskIDConflict
+	require.NoError(t, relay.publish(context.Background(), db.Outbox{ID: 42}))
+
+	enqueuer.err = errors.New("redis is down")
+	require.Error(t, relay.publish(context.Background(), db.Outbox{ID: 43}))
+}
+
+func TestOutboxRelayOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	enqueuer := &fakeEnqueuer{}
+	relay := &OutboxRelay{store: store, client: enqueuer}
+
+	store.EXPECT().
+		RelayOutboxTx(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(ctx context.Context, arg db.RelayOutboxTxParams) (db.RelayOutboxTxResult, error) {
+			require.NoError(t, arg.Publish(db.Outbox{ID: 1, TaskType: taskNameSendVerifyEmail}))
+			return db.RelayOutboxTxResult{Sent: 1}, nil
+		})
+	store.EXPECT().
+		DeleteSentOutboxTasks(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(int64(3), nil)
+
+	result, err := relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Sent)
+	require.Len(t, enqueuer.tasks, 1)
+
+	// the cleanup waits for the next interval
+	_, err = relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	require.Equal(t, time.Second, outboxBackoff(1))
+	require.Equal(t, 4*time.Second, outboxBackoff(3))
+	require.Equal(t, outboxMaxBackoff, outboxBackoff(10))
+	require.Equal(t, outboxMaxBackoff, outboxBackoff(1000))
+}
@@ -3,6 +3,7 @@ package async
 import (
 	db "bank/db/sqlc"
 	"bank/mail"
+	"bank/utils"
 	"context"
 
 	"github.com/hibiken/asynq"
@@ -12,23 +13,39 @@ import (
 type TaskProcessor interface {
 	Start() error
 	ProcessTaskSendVerifyEmail(context.Context, *asynq.Task) error
+	ProcessTaskSendPasswordReset(context.Context, *asynq.Task) error
+	ProcessTaskNotifyTransfer(context.Context, *asynq.Task) error
+	ProcessTaskNotifyNewDeviceLogin(context.Context, *asynq.Task) error
 }
 
 type RedisTaskProcessor struct {
 	server     *asynq.Server
 	store      db.Store
 	mailSender mail.EmailSender
+	renderer   *mail.Renderer
+	baseURL    string
+	// passwordResetURL is the page the reset link opens, the API route takes a POST only
+	passwordResetURL string
 }
 
 func (r *RedisTaskProcessor) Start() error {
 	mux := asynq.NewServeMux()
 
 	mux.HandleFunc(taskNameSendVerifyEmail, r.ProcessTaskSendVerifyEmail)
+	mux.HandleFunc(taskNameSendPasswordReset, r.ProcessTaskSendPasswordReset)
+	mux.HandleFunc(taskNameNotifyTransfer, r.ProcessTaskNotifyTransfer)
+	mux.HandleFunc(taskNameNotifyNewDeviceLogin, r.ProcessTaskNotifyNewDeviceLogin)
 
 	return r.server.Start(mux)
 }
 
-func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, mailSender mail.EmailSender) TaskProcessor {
+func NewRedisTaskProcessor(
+	config utils.Config,
+	redisOpt asynq.RedisClientOpt,
+	store db.Store,
+	mailSender mail.EmailSender,
+	renderer *mail.Renderer,
+) TaskProcessor {
 	return &RedisTaskProcessor{
 		server: asynq.NewServer(redisOpt, asynq.Config{
 			ErrorHandler: asynq.ErrorHandlerFunc(func(ctx context.Context, task *asynq.Task, err error) {
@@ -39,7 +56,10 @@ func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, mailSe
 			}),
 			Logger: &Logger{},
 		}),
-		store:      store,
-		mailSender: mailSender,
+		store:            store,
+		mailSender:       mailSender,
+		renderer:         renderer,
+		baseURL:          config.PublicBaseURL,
+		passwordResetURL: config.PasswordResetURL,
 	}
 }
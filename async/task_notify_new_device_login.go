@@ -0,0 +1,82 @@
+package async
+
+import (
+	db "bank/db/sqlc"
+	"bank/mail"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	taskNameNotifyNewDeviceLogin = "task:notify_new_device_login"
+	newDeviceLoginTimeLayout     = "2006-01-02 15:04 MST"
+)
+
+type PayloadNotifyNewDeviceLogin struct {
+	SessionID uuid.UUID `json:"session_id"`
+}
+
+// DistributeTaskNotifyNewDeviceLogin implements TaskDistributor.
+func (r *RedisTaskDistributor) DistributeTaskNotifyNewDeviceLogin(ctx context.Context, payload *PayloadNotifyNewDeviceLogin, opt ...asynq.Option) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(taskNameNotifyNewDeviceLogin, payloadBytes, opt...)
+	info, err := r.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("couldn't enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Dur("timeout", info.Retention).Str("queue", info.Queue).Bytes("payload", task.Payload()).
+		Int("max_retry", info.MaxRetry).Msg("enqueued task")
+
+	return nil
+}
+
+// ProcessTaskNotifyNewDeviceLogin emails the user about a login from a device none of their
+// earlier sessions came from. It is a security alert, so it is sent whatever the notification preferences.
+func (r *RedisTaskProcessor) ProcessTaskNotifyNewDeviceLogin(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadNotifyNewDeviceLogin
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	session, err := r.store.GetSession(ctx, payload.SessionID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("session not found: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("store.GetSession err: %w", err)
+	}
+
+	user, err := r.store.GetUser(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("user not found: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("store.GetUser err: %w", err)
+	}
+
+	err = r.sendEmail(mail.TemplateNewDeviceLogin, user, mail.NewDeviceLoginData{
+		FullName:  user.FullName,
+		Time:      session.CreatedAt.UTC().Format(newDeviceLoginTimeLayout),
+		ClientIP:  session.ClientIp,
+		UserAgent: session.UserAgent,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("type", task.Type()).Str("email", user.Email).Bytes("payload", task.Payload()).
+		Msg("processed task")
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package async
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/mail"
+	"bank/utils"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProcessTaskNotifyNewDeviceLogin(t *testing.T) {
+	user := db.User{ID: utils.RandomInt(1, 1000), Email: utils.RandomEmail(), FullName: "John Doe"}
+	session := db.Session{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		UserAgent: "Mozilla/5.0",
+		ClientIp:  "10.0.0.2",
+		CreatedAt: time.Date(2024, 1, 2, 15, 4, 0, 0, time.UTC),
+	}
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+
+	sender, err := mail.NewCaptureSender("Mini Bank", "noreply@example.com", t.TempDir())
+	require.NoError(t, err)
+	renderer, err := mail.NewRenderer("", "en", "Mini Bank")
+	require.NoError(t, err)
+	processor := &RedisTaskProcessor{store: store, mailSender: sender, renderer: renderer}
+
+	payload, err := json.Marshal(&PayloadNotifyNewDeviceLogin{SessionID: session.ID})
+	require.NoError(t, err)
+	err = processor.ProcessTaskNotifyNewDeviceLogin(context.Background(), asynq.NewTask(taskNameNotifyNewDeviceLogin, payload))
+	require.NoError(t, err)
+
+	messages := sender.Messages()
+	require.Len(t, messages, 1)
+	require.Equal(t, []string{user.Email}, messages[0].To)
+	require.Contains(t, messages[0].Text, "2024-01-02 15:04 UTC")
+	require.Contains(t, messages[0].Text, session.ClientIp)
+	require.Contains(t, messages[0].Text, session.UserAgent)
+}
@@ -0,0 +1,138 @@
+package async
+
+import (
+	db "bank/db/sqlc"
+	"bank/mail"
+	"bank/notify"
+	"bank/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const taskNameNotifyTransfer = "task:notify_transfer"
+
+type PayloadNotifyTransfer struct {
+	TransferID int64 `json:"transfer_id"`
+}
+
+// DistributeTaskNotifyTransfer implements TaskDistributor.
+func (r *RedisTaskDistributor) DistributeTaskNotifyTransfer(ctx context.Context, payload *PayloadNotifyTransfer, opt ...asynq.Option) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(taskNameNotifyTransfer, payloadBytes, opt...)
+	info, err := r.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("couldn't enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Dur("timeout", info.Retention).Str("queue", info.Queue).Bytes("payload", task.Payload()).
+		Int("max_retry", info.MaxRetry).Msg("enqueued task")
+
+	return nil
+}
+
+// ProcessTaskNotifyTransfer notifies the sender and the recipient of a completed transfer
+// through the channels they chose. A retry doesn't duplicate the in-app notifications,
+// the emails are sent at least once.
+func (r *RedisTaskProcessor) ProcessTaskNotifyTransfer(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadNotifyTransfer
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	transfer, err := r.store.GetTransfer(ctx, payload.TransferID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("transfer not found: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("store.GetTransfer err: %w", err)
+	}
+
+	fromAccount, err := r.store.GetAccount(ctx, transfer.FromAccountID)
+	if err != nil {
+		return fmt.Errorf("store.GetAccount err: %w", err)
+	}
+	toAccount, err := r.store.GetAccount(ctx, transfer.ToAccountID)
+	if err != nil {
+		return fmt.Errorf("store.GetAccount err: %w", err)
+	}
+
+	err = r.notifyTransferParty(ctx, transfer, notify.EventTransferSent, fromAccount, toAccount, transfer.Amount)
+	if err != nil {
+		return err
+	}
+	err = r.notifyTransferParty(ctx, transfer, notify.EventTransferReceived, toAccount, fromAccount, transfer.ToAmount)
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("type", task.Type()).Int64("transfer_id", transfer.ID).Bytes("payload", task.Payload()).
+		Msg("processed task")
+
+	return nil
+}
+
+// notifyTransferParty notifies the owner of account about the transfer, amount is in the currency of account.
+func (r *RedisTaskProcessor) notifyTransferParty(
+	ctx context.Context,
+	transfer db.Transfer,
+	event string,
+	account db.Account,
+	counterparty db.Account,
+	amount int64,
+) error {
+	preference, err := notify.PreferenceOf(ctx, r.store, account.UserID, event)
+	if err != nil {
+		return fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+
+	if preference.InApp {
+		_, err = r.store.CreateNotification(ctx, db.CreateNotificationParams{
+			UserID:                account.UserID,
+			Event:                 event,
+			TransferID:            pgtype.Int8{Int64: transfer.ID, Valid: true},
+			AccountID:             account.ID,
+			CounterpartyAccountID: counterparty.ID,
+			Amount:                amount,
+			Currency:              account.Currency,
+		})
+		// no rows: the notification was created by a previous attempt
+		if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("failed to create notification: %w", err)
+		}
+	}
+
+	if !preference.Email {
+		return nil
+	}
+
+	user, err := r.store.GetUser(ctx, account.UserID)
+	if err != nil {
+		return fmt.Errorf("store.GetUser err: %w", err)
+	}
+
+	formatted := utils.FormatAmount(amount, account.Currency)
+	if event == notify.EventTransferSent {
+		return r.sendEmail(mail.TemplateTransferSent, user, mail.TransferSentData{
+			FullName:      user.FullName,
+			Amount:        formatted,
+			FromAccountID: transfer.FromAccountID,
+			ToAccountID:   transfer.ToAccountID,
+		})
+	}
+	return r.sendEmail(mail.TemplateTransferReceived, user, mail.TransferReceivedData{
+		FullName:      user.FullName,
+		Amount:        formatted,
+		FromAccountID: transfer.FromAccountID,
+		ToAccountID:   transfer.ToAccountID,
+	})
+}
@@ -0,0 +1,96 @@
+package async
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/mail"
+	"bank/notify"
+	"bank/utils"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProcessTaskNotifyTransfer(t *testing.T) {
+	sender := db.User{ID: 1, Email: utils.RandomEmail(), FullName: "John Doe"}
+	recipient := db.User{ID: 2, Email: utils.RandomEmail(), FullName: "Jane Doe"}
+	fromAccount := db.Account{ID: 10, UserID: sender.ID, Currency: utils.USD}
+	toAccount := db.Account{ID: 20, UserID: recipient.ID, Currency: utils.EUR}
+	transfer := db.Transfer{ID: 100, FromAccountID: fromAccount.ID, ToAccountID: toAccount.ID, Amount: 1000, ToAmount: 900}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkMails func(t *testing.T, messages []mail.Message)
+	}{
+		{
+			name: "Email opt-in",
+			buildStubs: func(store *mockdb.MockStore) {
+				// the sender wants emails only, the recipient keeps the defaults
+				store.EXPECT().
+					ListNotificationPreferences(gomock.Any(), gomock.Eq(sender.ID)).
+					Times(1).
+					Return([]db.NotificationPreference{{UserID: sender.ID, Event: notify.EventTransferSent, InApp: false, Email: true}}, nil)
+				store.EXPECT().ListNotificationPreferences(gomock.Any(), gomock.Eq(recipient.ID)).Times(1).Return(nil, nil)
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(sender.ID)).Times(1).Return(sender, nil)
+				store.EXPECT().
+					CreateNotification(gomock.Any(), gomock.Eq(db.CreateNotificationParams{
+						UserID:                recipient.ID,
+						Event:                 notify.EventTransferReceived,
+						TransferID:            pgtype.Int8{Int64: transfer.ID, Valid: true},
+						AccountID:             toAccount.ID,
+						CounterpartyAccountID: fromAccount.ID,
+						Amount:                transfer.ToAmount,
+						Currency:              utils.EUR,
+					})).
+					Times(1)
+			},
+			checkMails: func(t *testing.T, messages []mail.Message) {
+				require.Len(t, messages, 1)
+				require.Equal(t, []string{sender.Email}, messages[0].To)
+				require.Contains(t, messages[0].Subject, utils.FormatAmount(transfer.Amount, utils.USD))
+			},
+		},
+		{
+			name: "Retry",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListNotificationPreferences(gomock.Any(), gomock.Any()).Times(2).Return(nil, nil)
+				// both notifications were created by the previous attempt
+				store.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).Times(2).Return(db.Notification{}, db.ErrRecordNotFound)
+			},
+			checkMails: func(t *testing.T, messages []mail.Message) {
+				require.Empty(t, messages)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+			tc.buildStubs(store)
+
+			mailSender, err := mail.NewCaptureSender("Mini Bank", "noreply@example.com", "")
+			require.NoError(t, err)
+			renderer, err := mail.NewRenderer("", "en", "Mini Bank")
+			require.NoError(t, err)
+			processor := &RedisTaskProcessor{store: store, mailSender: mailSender, renderer: renderer, baseURL: "https://bank.example.com"}
+
+			payload, err := json.Marshal(&PayloadNotifyTransfer{TransferID: transfer.ID})
+			require.NoError(t, err)
+			err = processor.ProcessTaskNotifyTransfer(context.Background(), asynq.NewTask(taskNameNotifyTransfer, payload))
+			require.NoError(t, err)
+
+			tc.checkMails(t, mailSender.Messages())
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package async
+
+import (
+	db "bank/db/sqlc"
+	"bank/mail"
+	"bank/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	taskNameSendPasswordReset = "task:send_password_reset"
+	passwordResetExpiresIn    = 30 * time.Minute
+	passwordResetCodeSize     = 16
+)
+
+type PayloadSendPasswordReset struct {
+	UserID int64 `json:"user_id"`
+}
+
+// DistributeTaskPasswordReset implements TaskDistributor.
+func (r *RedisTaskDistributor) DistributeTaskPasswordReset(ctx context.Context, payload *PayloadSendPasswordReset, opt ...asynq.Option) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(taskNameSendPasswordReset, payloadBytes, opt...)
+	info, err := r.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("couldn't enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Dur("timeout", info.Retention).Str("queue", info.Queue).Bytes("payload", task.Payload()).
+		Int("max_retry", info.MaxRetry).Msg("enqueued task")
+
+	return nil
+}
+
+// ProcessTaskSendPasswordReset creates a single-use reset code and emails it to the user.
+// Only the hash of the code is stored.
+func (r *RedisTaskProcessor) ProcessTaskSendPasswordReset(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendPasswordReset
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := r.store.GetUser(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("user not found: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("store.GetUser err: %w", err)
+	}
+
+	code, err := utils.GenerateCode(passwordResetCodeSize)
+	if err != nil {
+		return fmt.Errorf("failed to generate password reset code: %w", err)
+	}
+	passwordReset, err := r.store.CreatePasswordReset(ctx, db.CreatePasswordResetParams{
+		UserID:    user.ID,
+		CodeHash:  utils.HashCode(code),
+		ExpiredAt: time.Now().Add(passwordResetExpiresIn),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create password_reset: %w", err)
+	}
+
+	// the link opens a page that asks for the new password, by default the one the gateway serves
+	page := r.passwordResetURL
+	if page == "" {
+		page = r.link("/reset_password", nil)
+	}
+	err = r.sendEmail(mail.TemplatePasswordReset, user, mail.PasswordResetData{
+		FullName:         user.FullName,
+		Link:             withQuery(page, url.Values{"id": {strconv.FormatInt(passwordReset.ID, 10)}, "code": {code}}),
+		ExpiresInMinutes: int(passwordResetExpiresIn.Minutes()),
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("type", task.Type()).Str("email", user.Email).Bytes("payload", task.Payload()).
+		Msg("processed task")
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package async
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/mail"
+	"bank/utils"
+	"context"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProcessTaskSendPasswordReset(t *testing.T) {
+	user := db.User{ID: utils.RandomInt(1, 1000), Email: utils.RandomEmail(), FullName: "John Doe"}
+
+	testCases := []struct {
+		name             string
+		passwordResetURL string
+		page             string
+	}{
+		{
+			name:             "Reset page",
+			passwordResetURL: "https://app.example.com/reset-password?lang=en",
+			page:             "https://app.example.com/reset-password",
+		},
+		{
+			name: "Gateway page",
+			page: "https://bank.example.com/reset_password",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+
+			var passwordReset db.PasswordReset
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+			store.EXPECT().
+				CreatePasswordReset(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, arg db.CreatePasswordResetParams) (db.PasswordReset, error) {
+					require.Equal(t, user.ID, arg.UserID)
+					passwordReset = db.PasswordReset{ID: utils.RandomInt(1, 1000), UserID: arg.UserID, CodeHash: arg.CodeHash}
+					return passwordReset, nil
+				})
+
+			sender, err := mail.NewCaptureSender("Mini Bank", "noreply@example.com", t.TempDir())
+			require.NoError(t, err)
+			renderer, err := mail.NewRenderer("", "en", "Mini Bank")
+			require.NoError(t, err)
+			processor := &RedisTaskProcessor{
+				store:            store,
+				mailSender:       sender,
+				renderer:         renderer,
+				baseURL:          "https://bank.example.com/",
+				passwordResetURL: tc.passwordResetURL,
+			}
+
+			payload, err := json.Marshal(&PayloadSendPasswordReset{UserID: user.ID})
+			require.NoError(t, err)
+			err = processor.ProcessTaskSendPasswordReset(context.Background(), asynq.NewTask(taskNameSendPasswordReset, payload))
+			require.NoError(t, err)
+
+			messages := sender.Messages()
+			require.Len(t, messages, 1)
+			require.Equal(t, []string{user.Email}, messages[0].To)
+
+			// the link opens the page, not the POST-only API route
+			rawLink := regexp.MustCompile(`https://\S+`).FindString(messages[0].Text)
+			link, err := url.Parse(rawLink)
+			require.NoError(t, err)
+			require.Equal(t, tc.page, link.Scheme+"://"+link.Host+link.Path)
+			require.Equal(t, strconv.FormatInt(passwordReset.ID, 10), link.Query().Get("id"))
+			require.Equal(t, passwordReset.CodeHash, utils.HashCode(link.Query().Get("code")))
+			if tc.passwordResetURL != "" {
+				require.Equal(t, "en", link.Query().Get("lang"))
+			}
+		})
+	}
+}
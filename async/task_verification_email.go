@@ -2,12 +2,14 @@ package async
 
 import (
 	db "bank/db/sqlc"
+	"bank/mail"
 	"bank/utils"
 	"context"
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/hibiken/asynq"
@@ -50,11 +52,16 @@ func (r *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Context, tas
 
 	user, err := r.store.GetUser(ctx, payload.UserID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, db.ErrRecordNotFound) {
 			return fmt.Errorf("user not found: %w", asynq.SkipRetry)
 		}
 		return fmt.Errorf("store.GetUser err: %w", err)
 	}
+	if user.IsVerified.Bool {
+		// verified in the meantime, e.g. with the code of an earlier email
+		log.Info().Str("type", task.Type()).Int64("user_id", user.ID).Msg("skipped task: email is already verified")
+		return nil
+	}
 
 	verifyEmail, err := r.store.CreateVerifyEmail(ctx, db.CreateVerifyEmailParams{
 		UserID:    user.ID,
@@ -68,15 +75,13 @@ func (r *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Context, tas
 		return fmt.Errorf("failed to create verify_email: %w", err)
 	}
 
-	link := fmt.Sprintf("http://localhost:8080/v1/verify_email?id=%d&code=%s", verifyEmail.ID, verifyEmail.Code)
-	err = r.mailSender.Send(
-		"Verify email",
-		fmt.Sprintf("Thanks for signing up! Please follow the <a href=\"%s\">link</a> to verify", link),
-		[]string{user.Email}, nil, nil, nil,
-	)
-
+	err = r.sendEmail(mail.TemplateVerifyEmail, user, mail.VerifyEmailData{
+		FullName:         user.FullName,
+		Link:             r.link("/v1/verify_email", url.Values{"id": {strconv.FormatInt(verifyEmail.ID, 10)}, "code": {verifyEmail.Code}}),
+		ExpiresInMinutes: int(codeExpiresIn.Minutes()),
+	})
 	if err != nil {
-		return fmt.Errorf("failed to send a verification email to %s: %w", user.Email, err)
+		return err
 	}
 
 	log.Info().Str("type", task.Type()).Str("email", user.Email).Bytes("payload", task.Payload()).
@@ -0,0 +1,75 @@
+package async
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/mail"
+	"bank/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProcessTaskSendVerifyEmail(t *testing.T) {
+	user := db.User{ID: utils.RandomInt(1, 1000), Email: utils.RandomEmail(), FullName: "Ivan Petrov", Locale: "ru-RU"}
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	var verifyEmail db.VerifyEmail
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+	store.EXPECT().
+		CreateVerifyEmail(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateVerifyEmailParams) (db.VerifyEmail, error) {
+			require.Equal(t, user.ID, arg.UserID)
+			require.Equal(t, user.Email, arg.Email)
+			verifyEmail = db.VerifyEmail{ID: utils.RandomInt(1, 1000), UserID: arg.UserID, Email: arg.Email, Code: arg.Code}
+			return verifyEmail, nil
+		})
+
+	sender, err := mail.NewCaptureSender("Mini Bank", "noreply@example.com", t.TempDir())
+	require.NoError(t, err)
+	renderer, err := mail.NewRenderer("", "en", "Mini Bank")
+	require.NoError(t, err)
+	processor := &RedisTaskProcessor{store: store, mailSender: sender, renderer: renderer, baseURL: "https://bank.example.com/"}
+
+	payload, err := json.Marshal(&PayloadSendVerifyEmail{UserID: user.ID})
+	require.NoError(t, err)
+	err = processor.ProcessTaskSendVerifyEmail(context.Background(), asynq.NewTask(taskNameSendVerifyEmail, payload))
+	require.NoError(t, err)
+
+	messages := sender.Messages()
+	require.Len(t, messages, 1)
+	require.Equal(t, []string{user.Email}, messages[0].To)
+	link := fmt.Sprintf("https://bank.example.com/v1/verify_email?code=%s&amp;id=%d", verifyEmail.Code, verifyEmail.ID)
+	require.Equal(t, "Подтвердите email", messages[0].Subject)
+	require.Contains(t, messages[0].HTML, link)
+	require.Contains(t, messages[0].Text, strings.ReplaceAll(link, "&amp;", "&"))
+}
+
+func TestProcessTaskSendVerifyEmailAlreadyVerified(t *testing.T) {
+	user := db.User{ID: utils.RandomInt(1, 1000), Email: utils.RandomEmail(), IsVerified: pgtype.Bool{Bool: true, Valid: true}}
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+	store.EXPECT().CreateVerifyEmail(gomock.Any(), gomock.Any()).Times(0)
+
+	sender, err := mail.NewCaptureSender("Mini Bank", "noreply@example.com", "")
+	require.NoError(t, err)
+	processor := &RedisTaskProcessor{store: store, mailSender: sender}
+
+	payload, err := json.Marshal(&PayloadSendVerifyEmail{UserID: user.ID})
+	require.NoError(t, err)
+	err = processor.ProcessTaskSendVerifyEmail(context.Background(), asynq.NewTask(taskNameSendVerifyEmail, payload))
+	require.NoError(t, err)
+	require.Empty(t, sender.Messages())
+}
@@ -0,0 +1,13 @@
+CREATE TABLE "idempotency_keys" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "idempotency_key" varchar NOT NULL,
+  "request_hash" varchar NOT NULL,
+  "response" jsonb,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "expires_at" timestamptz NOT NULL
+);
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "idempotency_keys" ADD CONSTRAINT "user_id_idempotency_key_key" UNIQUE ("user_id", "idempotency_key");
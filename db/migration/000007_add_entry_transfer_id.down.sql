@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "entries_account_id_created_at_idx";
+
+ALTER TABLE IF EXISTS "entries" DROP CONSTRAINT IF EXISTS "entries_transfer_id_fkey";
+
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "transfer_id";
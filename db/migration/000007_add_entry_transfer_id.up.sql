@@ -0,0 +1,5 @@
+ALTER TABLE "entries" ADD "transfer_id" bigint;
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "entries" ("account_id", "created_at");
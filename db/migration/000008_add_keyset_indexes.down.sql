@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS "transfers_created_at_id_idx";
+
+DROP INDEX IF EXISTS "entries_account_id_created_at_id_idx";
+
+CREATE INDEX ON "entries" ("account_id", "created_at");
+
+DROP INDEX IF EXISTS "accounts_user_id_created_at_id_idx";
@@ -0,0 +1,7 @@
+CREATE INDEX ON "accounts" ("user_id", "created_at", "id");
+
+DROP INDEX IF EXISTS "entries_account_id_created_at_idx";
+
+CREATE INDEX ON "entries" ("account_id", "created_at", "id");
+
+CREATE INDEX ON "transfers" ("created_at", "id");
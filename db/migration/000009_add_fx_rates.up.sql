@@ -0,0 +1,19 @@
+CREATE TABLE "fx_rates" (
+  "base_currency" varchar NOT NULL,
+  "quote_currency" varchar NOT NULL,
+  "rate" float8 NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("base_currency", "quote_currency")
+);
+
+COMMENT ON COLUMN "fx_rates"."rate" IS 'units of quote currency per one unit of base currency';
+
+ALTER TABLE "transfers" ADD "to_amount" bigint;
+
+UPDATE "transfers" SET "to_amount" = "amount";
+
+ALTER TABLE "transfers" ALTER COLUMN "to_amount" SET NOT NULL;
+
+ALTER TABLE "transfers" ADD "exchange_rate" float8 NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN "transfers"."to_amount" IS 'amount credited in the currency of the destination account';
@@ -0,0 +1,17 @@
+CREATE TABLE "currencies" (
+  "code" varchar(3) PRIMARY KEY,
+  "numeric_code" integer UNIQUE NOT NULL,
+  "exponent" integer NOT NULL,
+  "symbol" varchar NOT NULL,
+  "enabled" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "currencies"."exponent" IS 'number of minor-unit digits, 2 for cents';
+
+INSERT INTO "currencies" ("code", "numeric_code", "exponent", "symbol")
+VALUES ('USD', 840, 2, '$'),
+       ('EUR', 978, 2, '€'),
+       ('UAH', 980, 2, '₴');
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
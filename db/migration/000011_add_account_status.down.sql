@@ -0,0 +1,7 @@
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "status_changed_at";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "status_reason";
+
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "accounts_status_check";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "status";
@@ -0,0 +1,7 @@
+ALTER TABLE "accounts" ADD COLUMN "status" varchar(16) NOT NULL DEFAULT 'active';
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_status_check" CHECK ("status" IN ('active', 'frozen', 'closed'));
+
+ALTER TABLE "accounts" ADD COLUMN "status_reason" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "accounts" ADD COLUMN "status_changed_at" timestamptz NOT NULL DEFAULT (now());
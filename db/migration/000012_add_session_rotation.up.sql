@@ -0,0 +1,11 @@
+ALTER TABLE "sessions" ADD COLUMN "family_id" uuid;
+
+UPDATE "sessions" SET "family_id" = "id";
+
+ALTER TABLE "sessions" ALTER COLUMN "family_id" SET NOT NULL;
+
+ALTER TABLE "sessions" ADD COLUMN "rotated_at" timestamptz;
+
+COMMENT ON COLUMN "sessions"."family_id" IS 'id of the login session every rotated refresh token descends from';
+
+CREATE INDEX ON "sessions" ("family_id");
@@ -0,0 +1,40 @@
+CREATE TABLE "user_totp" (
+  "user_id" bigint PRIMARY KEY,
+  "secret" varchar NOT NULL,
+  "confirmed_at" timestamptz,
+  "last_used_step" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "totp_recovery_codes" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "code_hash" varchar NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "mfa_challenges" (
+  "id" uuid PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "attempts" int NOT NULL DEFAULT 0,
+  "expires_at" timestamptz NOT NULL,
+  "consumed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "totp_recovery_codes" ("user_id");
+
+CREATE INDEX ON "mfa_challenges" ("user_id");
+
+COMMENT ON COLUMN "user_totp"."confirmed_at" IS 'the second factor is enforced once the user has proven to hold the secret';
+
+COMMENT ON COLUMN "user_totp"."last_used_step" IS 'time step of the last accepted code, a code is never accepted twice';
+
+COMMENT ON COLUMN "totp_recovery_codes"."code_hash" IS 'sha256 of the recovery code';
+
+ALTER TABLE "user_totp" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "totp_recovery_codes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "mfa_challenges" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
@@ -0,0 +1,14 @@
+CREATE TABLE "password_resets" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "code_hash" varchar NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "expired_at" timestamptz NOT NULL
+);
+
+CREATE INDEX ON "password_resets" ("user_id", "created_at");
+
+COMMENT ON COLUMN "password_resets"."code_hash" IS 'sha256 of the emailed code, the code itself is never stored';
+
+ALTER TABLE "password_resets" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
@@ -0,0 +1,36 @@
+CREATE TABLE "login_failures" (
+  "id" bigserial PRIMARY KEY,
+  "email" varchar NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "login_lockouts" (
+  "id" bigserial PRIMARY KEY,
+  "email" varchar NOT NULL,
+  "user_id" bigint,
+  "client_ip" varchar NOT NULL,
+  "failed_attempts" int NOT NULL,
+  "locked_until" timestamptz NOT NULL,
+  "unlocked_at" timestamptz,
+  "unlocked_by" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "login_failures" ("email", "created_at");
+
+CREATE INDEX ON "login_failures" ("client_ip", "created_at");
+
+CREATE INDEX ON "login_lockouts" ("email", "locked_until");
+
+CREATE INDEX ON "login_lockouts" ("created_at", "id");
+
+COMMENT ON COLUMN "login_failures"."email" IS 'failures are tracked by the email typed in, so that unknown emails behave like the known ones';
+
+COMMENT ON COLUMN "login_lockouts"."user_id" IS 'null when the email does not belong to any user';
+
+COMMENT ON COLUMN "login_lockouts"."client_ip" IS 'client of the attempt that triggered the lockout';
+
+ALTER TABLE "login_lockouts" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "login_lockouts" ADD FOREIGN KEY ("unlocked_by") REFERENCES "users" ("id");
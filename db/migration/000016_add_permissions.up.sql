@@ -0,0 +1,52 @@
+CREATE TABLE "roles" (
+  "name" varchar PRIMARY KEY,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "permissions" (
+  "name" varchar PRIMARY KEY,
+  "description" varchar NOT NULL
+);
+
+CREATE TABLE "role_permissions" (
+  "role" varchar NOT NULL,
+  "permission" varchar NOT NULL,
+  PRIMARY KEY ("role", "permission")
+);
+
+COMMENT ON COLUMN "permissions"."name" IS 'resource:action:scope, where scope "own" limits the action to the resources of the user and "any" does not';
+
+ALTER TABLE "role_permissions" ADD FOREIGN KEY ("role") REFERENCES "roles" ("name");
+
+ALTER TABLE "role_permissions" ADD FOREIGN KEY ("permission") REFERENCES "permissions" ("name");
+
+INSERT INTO "roles" ("name")
+VALUES ('depositor'),
+       ('banker');
+
+INSERT INTO "permissions" ("name", "description")
+VALUES ('accounts:create:own', 'open accounts'),
+       ('accounts:read:own', 'read own accounts, their entries and statements'),
+       ('accounts:read:any', 'read the accounts of any user'),
+       ('accounts:update:any', 'freeze, unfreeze and close any account'),
+       ('transfers:create:own', 'transfer money from own accounts'),
+       ('transfers:read:own', 'read the transfers of own accounts'),
+       ('transfers:read:any', 'read and search all transfers'),
+       ('users:update:own', 'update own profile'),
+       ('users:update:any', 'update the profile of any user'),
+       ('users:unlock:any', 'lift the login lockouts of any user'),
+       ('sessions:manage:own', 'list and revoke own sessions'),
+       ('sessions:revoke:any', 'revoke the sessions of any user'),
+       ('mfa:manage:own', 'enrol and disable own two-factor authentication'),
+       ('lockouts:read:any', 'read the login lockouts');
+
+INSERT INTO "role_permissions" ("role", "permission")
+SELECT 'depositor', "name"
+FROM "permissions"
+WHERE "name" LIKE '%:own';
+
+INSERT INTO "role_permissions" ("role", "permission")
+SELECT 'banker', "name"
+FROM "permissions";
+
+ALTER TABLE "users" ADD FOREIGN KEY ("role") REFERENCES "roles" ("name");
@@ -0,0 +1,2 @@
+-- the empty locale means no preference, the emails then go out in the default locale
+ALTER TABLE "users" ADD COLUMN "locale" varchar NOT NULL DEFAULT '';
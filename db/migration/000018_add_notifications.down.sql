@@ -0,0 +1,7 @@
+DELETE FROM "role_permissions" WHERE "permission" = 'notifications:manage:own';
+
+DELETE FROM "permissions" WHERE "name" = 'notifications:manage:own';
+
+DROP TABLE IF EXISTS "notification_preferences";
+
+DROP TABLE IF EXISTS "notifications";
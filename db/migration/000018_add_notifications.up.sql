@@ -0,0 +1,44 @@
+CREATE TABLE "notifications" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "event" varchar NOT NULL,
+  "transfer_id" bigint,
+  "account_id" bigint NOT NULL,
+  "counterparty_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "read_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "notification_preferences" (
+  "user_id" bigint NOT NULL,
+  "event" varchar NOT NULL,
+  "in_app" boolean NOT NULL,
+  "email" boolean NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("user_id", "event")
+);
+
+CREATE UNIQUE INDEX ON "notifications" ("user_id", "event", "transfer_id");
+
+CREATE INDEX ON "notifications" ("user_id", "created_at", "id");
+
+COMMENT ON COLUMN "notifications"."account_id" IS 'account of the user the event happened to';
+
+COMMENT ON COLUMN "notifications"."amount" IS 'in the currency of account_id';
+
+COMMENT ON TABLE "notification_preferences" IS 'a missing row means the default: in-app only';
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "notification_preferences" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+INSERT INTO "permissions" ("name", "description")
+VALUES ('notifications:manage:own', 'read own notifications and set their preferences');
+
+INSERT INTO "role_permissions" ("role", "permission")
+VALUES ('depositor', 'notifications:manage:own'),
+       ('banker', 'notifications:manage:own');
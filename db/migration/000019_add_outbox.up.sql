@@ -0,0 +1,26 @@
+CREATE TABLE "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "task_type" varchar NOT NULL,
+  "payload" bytea NOT NULL,
+  "queue" varchar NOT NULL DEFAULT '',
+  "max_retry" int,
+  "timeout" bigint,
+  "process_at" timestamptz,
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "sent_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "outbox" ("next_attempt_at", "id") WHERE "sent_at" IS NULL;
+
+CREATE INDEX ON "outbox" ("sent_at") WHERE "sent_at" IS NOT NULL;
+
+COMMENT ON COLUMN "outbox"."queue" IS 'empty for the default asynq queue';
+
+COMMENT ON COLUMN "outbox"."max_retry" IS 'null for the asynq default';
+
+COMMENT ON COLUMN "outbox"."timeout" IS 'task timeout in nanoseconds, null for the asynq default';
+
+COMMENT ON COLUMN "outbox"."next_attempt_at" IS 'when the relay publishes the task to asynq next';
@@ -0,0 +1,11 @@
+CREATE TABLE "verify_email_requests" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "verify_email_requests" ("user_id", "created_at");
+
+COMMENT ON TABLE "verify_email_requests" IS 'resends of the verification email, kept for rate limiting';
+
+ALTER TABLE "verify_email_requests" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
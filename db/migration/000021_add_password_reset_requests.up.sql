@@ -0,0 +1,11 @@
+CREATE TABLE "password_reset_requests" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "password_reset_requests" ("user_id", "created_at");
+
+COMMENT ON TABLE "password_reset_requests" IS 'password reset requests, recorded before the email is sent and kept for rate limiting';
+
+ALTER TABLE "password_reset_requests" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
@@ -0,0 +1,2 @@
+-- a login compares the device with the earlier sessions of the user
+CREATE INDEX ON "sessions" ("user_id");
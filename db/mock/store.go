@@ -13,6 +13,7 @@ import (
 	db "bank/db/sqlc"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -56,6 +57,154 @@ func (mr *MockStoreMockRecorder) AddBalanceToAccount(arg0, arg1 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBalanceToAccount", reflect.TypeOf((*MockStore)(nil).AddBalanceToAccount), arg0, arg1)
 }
 
+// AttemptMFAChallenge mocks base method.
+func (m *MockStore) AttemptMFAChallenge(arg0 context.Context, arg1 db.AttemptMFAChallengeParams) (db.MfaChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttemptMFAChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.MfaChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttemptMFAChallenge indicates an expected call of AttemptMFAChallenge.
+func (mr *MockStoreMockRecorder) AttemptMFAChallenge(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttemptMFAChallenge", reflect.TypeOf((*MockStore)(nil).AttemptMFAChallenge), arg0, arg1)
+}
+
+// BlockSessionFamily mocks base method.
+func (m *MockStore) BlockSessionFamily(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockSessionFamily", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockSessionFamily indicates an expected call of BlockSessionFamily.
+func (mr *MockStoreMockRecorder) BlockSessionFamily(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSessionFamily", reflect.TypeOf((*MockStore)(nil).BlockSessionFamily), arg0, arg1)
+}
+
+// BlockUserSessions mocks base method.
+func (m *MockStore) BlockUserSessions(arg0 context.Context, arg1 db.BlockUserSessionsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockUserSessions indicates an expected call of BlockUserSessions.
+func (mr *MockStoreMockRecorder) BlockUserSessions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUserSessions", reflect.TypeOf((*MockStore)(nil).BlockUserSessions), arg0, arg1)
+}
+
+// ClaimOutboxTasks mocks base method.
+func (m *MockStore) ClaimOutboxTasks(arg0 context.Context, arg1 int32) ([]db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOutboxTasks", arg0, arg1)
+	ret0, _ := ret[0].([]db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOutboxTasks indicates an expected call of ClaimOutboxTasks.
+func (mr *MockStoreMockRecorder) ClaimOutboxTasks(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOutboxTasks", reflect.TypeOf((*MockStore)(nil).ClaimOutboxTasks), arg0, arg1)
+}
+
+// ClearLoginFailures mocks base method.
+func (m *MockStore) ClearLoginFailures(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearLoginFailures", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearLoginFailures indicates an expected call of ClearLoginFailures.
+func (mr *MockStoreMockRecorder) ClearLoginFailures(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearLoginFailures", reflect.TypeOf((*MockStore)(nil).ClearLoginFailures), arg0, arg1)
+}
+
+// ConfirmUserTOTP mocks base method.
+func (m *MockStore) ConfirmUserTOTP(arg0 context.Context, arg1 int64) (db.UserTotp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmUserTOTP", arg0, arg1)
+	ret0, _ := ret[0].(db.UserTotp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmUserTOTP indicates an expected call of ConfirmUserTOTP.
+func (mr *MockStoreMockRecorder) ConfirmUserTOTP(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmUserTOTP", reflect.TypeOf((*MockStore)(nil).ConfirmUserTOTP), arg0, arg1)
+}
+
+// ConsumeMFAChallenge mocks base method.
+func (m *MockStore) ConsumeMFAChallenge(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeMFAChallenge", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeMFAChallenge indicates an expected call of ConsumeMFAChallenge.
+func (mr *MockStoreMockRecorder) ConsumeMFAChallenge(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeMFAChallenge", reflect.TypeOf((*MockStore)(nil).ConsumeMFAChallenge), arg0, arg1)
+}
+
+// CountPasswordResetRequests mocks base method.
+func (m *MockStore) CountPasswordResetRequests(arg0 context.Context, arg1 db.CountPasswordResetRequestsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPasswordResetRequests", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPasswordResetRequests indicates an expected call of CountPasswordResetRequests.
+func (mr *MockStoreMockRecorder) CountPasswordResetRequests(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPasswordResetRequests", reflect.TypeOf((*MockStore)(nil).CountPasswordResetRequests), arg0, arg1)
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockStore) CountUnreadNotifications(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockStoreMockRecorder) CountUnreadNotifications(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockStore)(nil).CountUnreadNotifications), arg0, arg1)
+}
+
+// CountVerifyEmailRequests mocks base method.
+func (m *MockStore) CountVerifyEmailRequests(arg0 context.Context, arg1 db.CountVerifyEmailRequestsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountVerifyEmailRequests", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountVerifyEmailRequests indicates an expected call of CountVerifyEmailRequests.
+func (mr *MockStoreMockRecorder) CountVerifyEmailRequests(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountVerifyEmailRequests", reflect.TypeOf((*MockStore)(nil).CountVerifyEmailRequests), arg0, arg1)
+}
+
 // CreateAccount mocks base method.
 func (m *MockStore) CreateAccount(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -71,6 +220,21 @@ func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
 }
 
+// CreateAccountTx mocks base method.
+func (m *MockStore) CreateAccountTx(arg0 context.Context, arg1 db.CreateAccountTxParams) (db.CreateAccountTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateAccountTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountTx indicates an expected call of CreateAccountTx.
+func (mr *MockStoreMockRecorder) CreateAccountTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountTx", reflect.TypeOf((*MockStore)(nil).CreateAccountTx), arg0, arg1)
+}
+
 // CreateEntry mocks base method.
 func (m *MockStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
 	m.ctrl.T.Helper()
@@ -86,6 +250,153 @@ func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
 }
 
+// CreateIdempotencyKey mocks base method.
+func (m *MockStore) CreateIdempotencyKey(arg0 context.Context, arg1 db.CreateIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(db.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdempotencyKey indicates an expected call of CreateIdempotencyKey.
+func (mr *MockStoreMockRecorder) CreateIdempotencyKey(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdempotencyKey", reflect.TypeOf((*MockStore)(nil).CreateIdempotencyKey), arg0, arg1)
+}
+
+// CreateLoginFailure mocks base method.
+func (m *MockStore) CreateLoginFailure(arg0 context.Context, arg1 db.CreateLoginFailureParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoginFailure", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLoginFailure indicates an expected call of CreateLoginFailure.
+func (mr *MockStoreMockRecorder) CreateLoginFailure(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoginFailure", reflect.TypeOf((*MockStore)(nil).CreateLoginFailure), arg0, arg1)
+}
+
+// CreateLoginLockout mocks base method.
+func (m *MockStore) CreateLoginLockout(arg0 context.Context, arg1 db.CreateLoginLockoutParams) (db.LoginLockout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoginLockout", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginLockout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoginLockout indicates an expected call of CreateLoginLockout.
+func (mr *MockStoreMockRecorder) CreateLoginLockout(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoginLockout", reflect.TypeOf((*MockStore)(nil).CreateLoginLockout), arg0, arg1)
+}
+
+// CreateLoginSessionTx mocks base method.
+func (m *MockStore) CreateLoginSessionTx(arg0 context.Context, arg1 db.CreateLoginSessionTxParams) (db.CreateLoginSessionTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoginSessionTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateLoginSessionTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoginSessionTx indicates an expected call of CreateLoginSessionTx.
+func (mr *MockStoreMockRecorder) CreateLoginSessionTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoginSessionTx", reflect.TypeOf((*MockStore)(nil).CreateLoginSessionTx), arg0, arg1)
+}
+
+// CreateMFAChallenge mocks base method.
+func (m *MockStore) CreateMFAChallenge(arg0 context.Context, arg1 db.CreateMFAChallengeParams) (db.MfaChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMFAChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.MfaChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMFAChallenge indicates an expected call of CreateMFAChallenge.
+func (mr *MockStoreMockRecorder) CreateMFAChallenge(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMFAChallenge", reflect.TypeOf((*MockStore)(nil).CreateMFAChallenge), arg0, arg1)
+}
+
+// CreateNotification mocks base method.
+func (m *MockStore) CreateNotification(arg0 context.Context, arg1 db.CreateNotificationParams) (db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotification", arg0, arg1)
+	ret0, _ := ret[0].(db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNotification indicates an expected call of CreateNotification.
+func (mr *MockStoreMockRecorder) CreateNotification(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotification", reflect.TypeOf((*MockStore)(nil).CreateNotification), arg0, arg1)
+}
+
+// CreateOutboxTask mocks base method.
+func (m *MockStore) CreateOutboxTask(arg0 context.Context, arg1 db.CreateOutboxTaskParams) (db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxTask", arg0, arg1)
+	ret0, _ := ret[0].(db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutboxTask indicates an expected call of CreateOutboxTask.
+func (mr *MockStoreMockRecorder) CreateOutboxTask(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxTask", reflect.TypeOf((*MockStore)(nil).CreateOutboxTask), arg0, arg1)
+}
+
+// CreatePasswordReset mocks base method.
+func (m *MockStore) CreatePasswordReset(arg0 context.Context, arg1 db.CreatePasswordResetParams) (db.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePasswordReset indicates an expected call of CreatePasswordReset.
+func (mr *MockStoreMockRecorder) CreatePasswordReset(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordReset", reflect.TypeOf((*MockStore)(nil).CreatePasswordReset), arg0, arg1)
+}
+
+// CreatePasswordResetRequest mocks base method.
+func (m *MockStore) CreatePasswordResetRequest(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordResetRequest", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePasswordResetRequest indicates an expected call of CreatePasswordResetRequest.
+func (mr *MockStoreMockRecorder) CreatePasswordResetRequest(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordResetRequest", reflect.TypeOf((*MockStore)(nil).CreatePasswordResetRequest), arg0, arg1)
+}
+
+// CreateRecoveryCode mocks base method.
+func (m *MockStore) CreateRecoveryCode(arg0 context.Context, arg1 db.CreateRecoveryCodeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRecoveryCode indicates an expected call of CreateRecoveryCode.
+func (mr *MockStoreMockRecorder) CreateRecoveryCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecoveryCode", reflect.TypeOf((*MockStore)(nil).CreateRecoveryCode), arg0, arg1)
+}
+
 // CreateSession mocks base method.
 func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
 	m.ctrl.T.Helper()
@@ -119,268 +430,815 @@ func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 any) *gomock.Call {
 // CreateUser mocks base method.
 func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateUserTX mocks base method.
+func (m *MockStore) CreateUserTX(arg0 context.Context, arg1 db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserTX", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateUserTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserTX indicates an expected call of CreateUserTX.
+func (mr *MockStoreMockRecorder) CreateUserTX(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserTX", reflect.TypeOf((*MockStore)(nil).CreateUserTX), arg0, arg1)
+}
+
+// CreateVerifyEmail mocks base method.
+func (m *MockStore) CreateVerifyEmail(arg0 context.Context, arg1 db.CreateVerifyEmailParams) (db.VerifyEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVerifyEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.VerifyEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVerifyEmail indicates an expected call of CreateVerifyEmail.
+func (mr *MockStoreMockRecorder) CreateVerifyEmail(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVerifyEmail", reflect.TypeOf((*MockStore)(nil).CreateVerifyEmail), arg0, arg1)
+}
+
+// CreateVerifyEmailRequest mocks base method.
+func (m *MockStore) CreateVerifyEmailRequest(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVerifyEmailRequest", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateVerifyEmailRequest indicates an expected call of CreateVerifyEmailRequest.
+func (mr *MockStoreMockRecorder) CreateVerifyEmailRequest(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVerifyEmailRequest", reflect.TypeOf((*MockStore)(nil).CreateVerifyEmailRequest), arg0, arg1)
+}
+
+// DeleteEntry mocks base method.
+func (m *MockStore) DeleteEntry(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEntry", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEntry indicates an expected call of DeleteEntry.
+func (mr *MockStoreMockRecorder) DeleteEntry(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntry", reflect.TypeOf((*MockStore)(nil).DeleteEntry), arg0, arg1)
+}
+
+// DeleteRecoveryCodes mocks base method.
+func (m *MockStore) DeleteRecoveryCodes(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecoveryCodes indicates an expected call of DeleteRecoveryCodes.
+func (mr *MockStoreMockRecorder) DeleteRecoveryCodes(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodes", reflect.TypeOf((*MockStore)(nil).DeleteRecoveryCodes), arg0, arg1)
+}
+
+// DeleteSentOutboxTasks mocks base method.
+func (m *MockStore) DeleteSentOutboxTasks(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSentOutboxTasks", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSentOutboxTasks indicates an expected call of DeleteSentOutboxTasks.
+func (mr *MockStoreMockRecorder) DeleteSentOutboxTasks(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSentOutboxTasks", reflect.TypeOf((*MockStore)(nil).DeleteSentOutboxTasks), arg0, arg1)
+}
+
+// DeleteTransfer mocks base method.
+func (m *MockStore) DeleteTransfer(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTransfer", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTransfer indicates an expected call of DeleteTransfer.
+func (mr *MockStoreMockRecorder) DeleteTransfer(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransfer", reflect.TypeOf((*MockStore)(nil).DeleteTransfer), arg0, arg1)
+}
+
+// DeleteUserTOTP mocks base method.
+func (m *MockStore) DeleteUserTOTP(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserTOTP", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserTOTP indicates an expected call of DeleteUserTOTP.
+func (mr *MockStoreMockRecorder) DeleteUserTOTP(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserTOTP", reflect.TypeOf((*MockStore)(nil).DeleteUserTOTP), arg0, arg1)
+}
+
+// DisableTOTPTx mocks base method.
+func (m *MockStore) DisableTOTPTx(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTPTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTPTx indicates an expected call of DisableTOTPTx.
+func (mr *MockStoreMockRecorder) DisableTOTPTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTPTx", reflect.TypeOf((*MockStore)(nil).DisableTOTPTx), arg0, arg1)
+}
+
+// EnrolTOTPTx mocks base method.
+func (m *MockStore) EnrolTOTPTx(arg0 context.Context, arg1 db.EnrolTOTPTxParams) (db.EnrolTOTPTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrolTOTPTx", arg0, arg1)
+	ret0, _ := ret[0].(db.EnrolTOTPTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrolTOTPTx indicates an expected call of EnrolTOTPTx.
+func (mr *MockStoreMockRecorder) EnrolTOTPTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrolTOTPTx", reflect.TypeOf((*MockStore)(nil).EnrolTOTPTx), arg0, arg1)
+}
+
+// EnrolUserTOTP mocks base method.
+func (m *MockStore) EnrolUserTOTP(arg0 context.Context, arg1 db.EnrolUserTOTPParams) (db.UserTotp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrolUserTOTP", arg0, arg1)
+	ret0, _ := ret[0].(db.UserTotp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrolUserTOTP indicates an expected call of EnrolUserTOTP.
+func (mr *MockStoreMockRecorder) EnrolUserTOTP(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrolUserTOTP", reflect.TypeOf((*MockStore)(nil).EnrolUserTOTP), arg0, arg1)
+}
+
+// ExpirePasswordResets mocks base method.
+func (m *MockStore) ExpirePasswordResets(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePasswordResets", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpirePasswordResets indicates an expected call of ExpirePasswordResets.
+func (mr *MockStoreMockRecorder) ExpirePasswordResets(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePasswordResets", reflect.TypeOf((*MockStore)(nil).ExpirePasswordResets), arg0, arg1)
+}
+
+// GetAccount mocks base method.
+func (m *MockStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
+}
+
+// GetAccountForUpdate mocks base method.
+func (m *MockStore) GetAccountForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountForUpdate indicates an expected call of GetAccountForUpdate.
+func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
+}
+
+// GetAccountStatementTx mocks base method.
+func (m *MockStore) GetAccountStatementTx(arg0 context.Context, arg1 db.GetAccountStatementTxParams) (db.GetAccountStatementTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountStatementTx", arg0, arg1)
+	ret0, _ := ret[0].(db.GetAccountStatementTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountStatementTx indicates an expected call of GetAccountStatementTx.
+func (mr *MockStoreMockRecorder) GetAccountStatementTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountStatementTx", reflect.TypeOf((*MockStore)(nil).GetAccountStatementTx), arg0, arg1)
+}
+
+// GetActiveLoginLockout mocks base method.
+func (m *MockStore) GetActiveLoginLockout(arg0 context.Context, arg1 string) (db.LoginLockout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveLoginLockout", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginLockout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveLoginLockout indicates an expected call of GetActiveLoginLockout.
+func (mr *MockStoreMockRecorder) GetActiveLoginLockout(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveLoginLockout", reflect.TypeOf((*MockStore)(nil).GetActiveLoginLockout), arg0, arg1)
+}
+
+// GetEntriesSumSince mocks base method.
+func (m *MockStore) GetEntriesSumSince(arg0 context.Context, arg1 db.GetEntriesSumSinceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntriesSumSince", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntriesSumSince indicates an expected call of GetEntriesSumSince.
+func (mr *MockStoreMockRecorder) GetEntriesSumSince(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntriesSumSince", reflect.TypeOf((*MockStore)(nil).GetEntriesSumSince), arg0, arg1)
+}
+
+// GetEntry mocks base method.
+func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+}
+
+// GetFXRate mocks base method.
+func (m *MockStore) GetFXRate(arg0 context.Context, arg1 db.GetFXRateParams) (db.FxRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFXRate", arg0, arg1)
+	ret0, _ := ret[0].(db.FxRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFXRate indicates an expected call of GetFXRate.
+func (mr *MockStoreMockRecorder) GetFXRate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFXRate", reflect.TypeOf((*MockStore)(nil).GetFXRate), arg0, arg1)
+}
+
+// GetIdempotencyKey mocks base method.
+func (m *MockStore) GetIdempotencyKey(arg0 context.Context, arg1 db.GetIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(db.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockStoreMockRecorder) GetIdempotencyKey(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockStore)(nil).GetIdempotencyKey), arg0, arg1)
+}
+
+// GetLoginDeviceStats mocks base method.
+func (m *MockStore) GetLoginDeviceStats(arg0 context.Context, arg1 db.GetLoginDeviceStatsParams) (db.GetLoginDeviceStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginDeviceStats", arg0, arg1)
+	ret0, _ := ret[0].(db.GetLoginDeviceStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginDeviceStats indicates an expected call of GetLoginDeviceStats.
+func (mr *MockStoreMockRecorder) GetLoginDeviceStats(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginDeviceStats", reflect.TypeOf((*MockStore)(nil).GetLoginDeviceStats), arg0, arg1)
+}
+
+// GetLoginFailureStats mocks base method.
+func (m *MockStore) GetLoginFailureStats(arg0 context.Context, arg1 db.GetLoginFailureStatsParams) (db.GetLoginFailureStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginFailureStats", arg0, arg1)
+	ret0, _ := ret[0].(db.GetLoginFailureStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginFailureStats indicates an expected call of GetLoginFailureStats.
+func (mr *MockStoreMockRecorder) GetLoginFailureStats(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginFailureStats", reflect.TypeOf((*MockStore)(nil).GetLoginFailureStats), arg0, arg1)
+}
+
+// GetPasswordReset mocks base method.
+func (m *MockStore) GetPasswordReset(arg0 context.Context, arg1 int64) (db.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordReset indicates an expected call of GetPasswordReset.
+func (mr *MockStoreMockRecorder) GetPasswordReset(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordReset", reflect.TypeOf((*MockStore)(nil).GetPasswordReset), arg0, arg1)
+}
+
+// GetPendingMFAChallenge mocks base method.
+func (m *MockStore) GetPendingMFAChallenge(arg0 context.Context, arg1 uuid.UUID) (db.MfaChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingMFAChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.MfaChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingMFAChallenge indicates an expected call of GetPendingMFAChallenge.
+func (mr *MockStoreMockRecorder) GetPendingMFAChallenge(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingMFAChallenge", reflect.TypeOf((*MockStore)(nil).GetPendingMFAChallenge), arg0, arg1)
+}
+
+// GetSession mocks base method.
+func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+}
+
+// GetSessionForUpdate mocks base method.
+func (m *MockStore) GetSessionForUpdate(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionForUpdate indicates an expected call of GetSessionForUpdate.
+func (mr *MockStoreMockRecorder) GetSessionForUpdate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionForUpdate", reflect.TypeOf((*MockStore)(nil).GetSessionForUpdate), arg0, arg1)
+}
+
+// GetTransfer mocks base method.
+func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 int64) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserAccount mocks base method.
+func (m *MockStore) GetUserAccount(arg0 context.Context, arg1 db.GetUserAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserAccount indicates an expected call of GetUserAccount.
+func (mr *MockStoreMockRecorder) GetUserAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAccount", reflect.TypeOf((*MockStore)(nil).GetUserAccount), arg0, arg1)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockStore) GetUserByEmail(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockStoreMockRecorder) GetUserByEmail(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), arg0, arg1)
+}
+
+// GetUserForUpdate mocks base method.
+func (m *MockStore) GetUserForUpdate(arg0 context.Context, arg1 int64) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserForUpdate indicates an expected call of GetUserForUpdate.
+func (mr *MockStoreMockRecorder) GetUserForUpdate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserForUpdate", reflect.TypeOf((*MockStore)(nil).GetUserForUpdate), arg0, arg1)
+}
+
+// GetUserTOTP mocks base method.
+func (m *MockStore) GetUserTOTP(arg0 context.Context, arg1 int64) (db.UserTotp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTOTP", arg0, arg1)
+	ret0, _ := ret[0].(db.UserTotp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTOTP indicates an expected call of GetUserTOTP.
+func (mr *MockStoreMockRecorder) GetUserTOTP(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTOTP", reflect.TypeOf((*MockStore)(nil).GetUserTOTP), arg0, arg1)
+}
+
+// GetVerifyEmail mocks base method.
+func (m *MockStore) GetVerifyEmail(arg0 context.Context, arg1 int64) (db.VerifyEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVerifyEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.VerifyEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVerifyEmail indicates an expected call of GetVerifyEmail.
+func (mr *MockStoreMockRecorder) GetVerifyEmail(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVerifyEmail", reflect.TypeOf((*MockStore)(nil).GetVerifyEmail), arg0, arg1)
+}
+
+// ListAccountTransfers mocks base method.
+func (m *MockStore) ListAccountTransfers(arg0 context.Context, arg1 db.ListAccountTransfersParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountTransfers indicates an expected call of ListAccountTransfers.
+func (mr *MockStoreMockRecorder) ListAccountTransfers(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountTransfers", reflect.TypeOf((*MockStore)(nil).ListAccountTransfers), arg0, arg1)
+}
+
+// ListAccounts mocks base method.
+func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+}
+
+// ListActiveSessions mocks base method.
+func (m *MockStore) ListActiveSessions(arg0 context.Context, arg1 int64) ([]db.ListActiveSessionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveSessions", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListActiveSessionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveSessions indicates an expected call of ListActiveSessions.
+func (mr *MockStoreMockRecorder) ListActiveSessions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveSessions", reflect.TypeOf((*MockStore)(nil).ListActiveSessions), arg0, arg1)
+}
+
+// ListCurrencies mocks base method.
+func (m *MockStore) ListCurrencies(arg0 context.Context) ([]db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCurrencies", arg0)
+	ret0, _ := ret[0].([]db.Currency)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 any) *gomock.Call {
+// ListCurrencies indicates an expected call of ListCurrencies.
+func (mr *MockStoreMockRecorder) ListCurrencies(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrencies", reflect.TypeOf((*MockStore)(nil).ListCurrencies), arg0)
 }
 
-// CreateUserTX mocks base method.
-func (m *MockStore) CreateUserTX(arg0 context.Context, arg1 db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+// ListEntries mocks base method.
+func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUserTX", arg0, arg1)
-	ret0, _ := ret[0].(db.CreateUserTxResult)
+	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUserTX indicates an expected call of CreateUserTX.
-func (mr *MockStoreMockRecorder) CreateUserTX(arg0, arg1 any) *gomock.Call {
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserTX", reflect.TypeOf((*MockStore)(nil).CreateUserTX), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
 }
 
-// CreateVerifyEmail mocks base method.
-func (m *MockStore) CreateVerifyEmail(arg0 context.Context, arg1 db.CreateVerifyEmailParams) (db.VerifyEmail, error) {
+// ListLoginLockouts mocks base method.
+func (m *MockStore) ListLoginLockouts(arg0 context.Context, arg1 db.ListLoginLockoutsParams) ([]db.LoginLockout, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateVerifyEmail", arg0, arg1)
-	ret0, _ := ret[0].(db.VerifyEmail)
+	ret := m.ctrl.Call(m, "ListLoginLockouts", arg0, arg1)
+	ret0, _ := ret[0].([]db.LoginLockout)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateVerifyEmail indicates an expected call of CreateVerifyEmail.
-func (mr *MockStoreMockRecorder) CreateVerifyEmail(arg0, arg1 any) *gomock.Call {
+// ListLoginLockouts indicates an expected call of ListLoginLockouts.
+func (mr *MockStoreMockRecorder) ListLoginLockouts(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVerifyEmail", reflect.TypeOf((*MockStore)(nil).CreateVerifyEmail), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoginLockouts", reflect.TypeOf((*MockStore)(nil).ListLoginLockouts), arg0, arg1)
 }
 
-// DeleteAccount mocks base method.
-func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
+// ListNotificationPreferences mocks base method.
+func (m *MockStore) ListNotificationPreferences(arg0 context.Context, arg1 int64) ([]db.NotificationPreference, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ListNotificationPreferences", arg0, arg1)
+	ret0, _ := ret[0].([]db.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteAccount indicates an expected call of DeleteAccount.
-func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 any) *gomock.Call {
+// ListNotificationPreferences indicates an expected call of ListNotificationPreferences.
+func (mr *MockStoreMockRecorder) ListNotificationPreferences(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotificationPreferences", reflect.TypeOf((*MockStore)(nil).ListNotificationPreferences), arg0, arg1)
 }
 
-// DeleteEntry mocks base method.
-func (m *MockStore) DeleteEntry(arg0 context.Context, arg1 int64) error {
+// ListNotifications mocks base method.
+func (m *MockStore) ListNotifications(arg0 context.Context, arg1 db.ListNotificationsParams) ([]db.Notification, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteEntry", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ListNotifications", arg0, arg1)
+	ret0, _ := ret[0].([]db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteEntry indicates an expected call of DeleteEntry.
-func (mr *MockStoreMockRecorder) DeleteEntry(arg0, arg1 any) *gomock.Call {
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockStoreMockRecorder) ListNotifications(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntry", reflect.TypeOf((*MockStore)(nil).DeleteEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockStore)(nil).ListNotifications), arg0, arg1)
 }
 
-// DeleteTransfer mocks base method.
-func (m *MockStore) DeleteTransfer(arg0 context.Context, arg1 int64) error {
+// ListRolePermissions mocks base method.
+func (m *MockStore) ListRolePermissions(arg0 context.Context, arg1 string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTransfer", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ListRolePermissions", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteTransfer indicates an expected call of DeleteTransfer.
-func (mr *MockStoreMockRecorder) DeleteTransfer(arg0, arg1 any) *gomock.Call {
+// ListRolePermissions indicates an expected call of ListRolePermissions.
+func (mr *MockStoreMockRecorder) ListRolePermissions(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransfer", reflect.TypeOf((*MockStore)(nil).DeleteTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRolePermissions", reflect.TypeOf((*MockStore)(nil).ListRolePermissions), arg0, arg1)
 }
 
-// GetAccount mocks base method.
-func (m *MockStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+// ListStatementEntries mocks base method.
+func (m *MockStore) ListStatementEntries(arg0 context.Context, arg1 db.ListStatementEntriesParams) ([]db.ListStatementEntriesRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "ListStatementEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListStatementEntriesRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccount indicates an expected call of GetAccount.
-func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 any) *gomock.Call {
+// ListStatementEntries indicates an expected call of ListStatementEntries.
+func (mr *MockStoreMockRecorder) ListStatementEntries(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatementEntries", reflect.TypeOf((*MockStore)(nil).ListStatementEntries), arg0, arg1)
 }
 
-// GetAccountForUpdate mocks base method.
-func (m *MockStore) GetAccountForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
+// ListTransfers mocks base method.
+func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountForUpdate", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountForUpdate indicates an expected call of GetAccountForUpdate.
-func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 any) *gomock.Call {
+// ListTransfers indicates an expected call of ListTransfers.
+func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
 }
 
-// GetEntry mocks base method.
-func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+// LockLoginTx mocks base method.
+func (m *MockStore) LockLoginTx(arg0 context.Context, arg1 db.LockLoginTxParams) (db.LockLoginTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "LockLoginTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LockLoginTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntry indicates an expected call of GetEntry.
-func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 any) *gomock.Call {
+// LockLoginTx indicates an expected call of LockLoginTx.
+func (mr *MockStoreMockRecorder) LockLoginTx(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockLoginTx", reflect.TypeOf((*MockStore)(nil).LockLoginTx), arg0, arg1)
 }
 
-// GetSession mocks base method.
-func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+// MarkNotificationsRead mocks base method.
+func (m *MockStore) MarkNotificationsRead(arg0 context.Context, arg1 db.MarkNotificationsReadParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "MarkNotificationsRead", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSession indicates an expected call of GetSession.
-func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 any) *gomock.Call {
+// MarkNotificationsRead indicates an expected call of MarkNotificationsRead.
+func (mr *MockStoreMockRecorder) MarkNotificationsRead(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationsRead", reflect.TypeOf((*MockStore)(nil).MarkNotificationsRead), arg0, arg1)
 }
 
-// GetTransfer mocks base method.
-func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+// MarkOutboxTaskFailed mocks base method.
+func (m *MockStore) MarkOutboxTaskFailed(arg0 context.Context, arg1 db.MarkOutboxTaskFailedParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
-	ret0, _ := ret[0].(db.Transfer)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "MarkOutboxTaskFailed", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetTransfer indicates an expected call of GetTransfer.
-func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 any) *gomock.Call {
+// MarkOutboxTaskFailed indicates an expected call of MarkOutboxTaskFailed.
+func (mr *MockStoreMockRecorder) MarkOutboxTaskFailed(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxTaskFailed", reflect.TypeOf((*MockStore)(nil).MarkOutboxTaskFailed), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 int64) (db.User, error) {
+// MarkOutboxTaskSent mocks base method.
+func (m *MockStore) MarkOutboxTaskSent(arg0 context.Context, arg1 int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "MarkOutboxTaskSent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 any) *gomock.Call {
+// MarkOutboxTaskSent indicates an expected call of MarkOutboxTaskSent.
+func (mr *MockStoreMockRecorder) MarkOutboxTaskSent(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxTaskSent", reflect.TypeOf((*MockStore)(nil).MarkOutboxTaskSent), arg0, arg1)
 }
 
-// GetUserAccount mocks base method.
-func (m *MockStore) GetUserAccount(arg0 context.Context, arg1 db.GetUserAccountParams) (db.Account, error) {
+// RelayOutboxTx mocks base method.
+func (m *MockStore) RelayOutboxTx(arg0 context.Context, arg1 db.RelayOutboxTxParams) (db.RelayOutboxTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserAccount", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "RelayOutboxTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RelayOutboxTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserAccount indicates an expected call of GetUserAccount.
-func (mr *MockStoreMockRecorder) GetUserAccount(arg0, arg1 any) *gomock.Call {
+// RelayOutboxTx indicates an expected call of RelayOutboxTx.
+func (mr *MockStoreMockRecorder) RelayOutboxTx(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAccount", reflect.TypeOf((*MockStore)(nil).GetUserAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelayOutboxTx", reflect.TypeOf((*MockStore)(nil).RelayOutboxTx), arg0, arg1)
 }
 
-// GetUserByEmail mocks base method.
-func (m *MockStore) GetUserByEmail(arg0 context.Context, arg1 string) (db.User, error) {
+// RenewSessionTx mocks base method.
+func (m *MockStore) RenewSessionTx(arg0 context.Context, arg1 db.RenewSessionTxParams) (db.RenewSessionTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByEmail", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "RenewSessionTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RenewSessionTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByEmail indicates an expected call of GetUserByEmail.
-func (mr *MockStoreMockRecorder) GetUserByEmail(arg0, arg1 any) *gomock.Call {
+// RenewSessionTx indicates an expected call of RenewSessionTx.
+func (mr *MockStoreMockRecorder) RenewSessionTx(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewSessionTx", reflect.TypeOf((*MockStore)(nil).RenewSessionTx), arg0, arg1)
 }
 
-// GetVerifyEmail mocks base method.
-func (m *MockStore) GetVerifyEmail(arg0 context.Context, arg1 int64) (db.VerifyEmail, error) {
+// RequestPasswordResetTx mocks base method.
+func (m *MockStore) RequestPasswordResetTx(arg0 context.Context, arg1 db.RequestPasswordResetTxParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetVerifyEmail", arg0, arg1)
-	ret0, _ := ret[0].(db.VerifyEmail)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "RequestPasswordResetTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetVerifyEmail indicates an expected call of GetVerifyEmail.
-func (mr *MockStoreMockRecorder) GetVerifyEmail(arg0, arg1 any) *gomock.Call {
+// RequestPasswordResetTx indicates an expected call of RequestPasswordResetTx.
+func (mr *MockStoreMockRecorder) RequestPasswordResetTx(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVerifyEmail", reflect.TypeOf((*MockStore)(nil).GetVerifyEmail), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPasswordResetTx", reflect.TypeOf((*MockStore)(nil).RequestPasswordResetTx), arg0, arg1)
 }
 
-// ListAccounts mocks base method.
-func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+// ResendVerifyEmailTx mocks base method.
+func (m *MockStore) ResendVerifyEmailTx(arg0 context.Context, arg1 db.ResendVerifyEmailTxParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
-	ret0, _ := ret[0].([]db.Account)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "ResendVerifyEmailTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ListAccounts indicates an expected call of ListAccounts.
-func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 any) *gomock.Call {
+// ResendVerifyEmailTx indicates an expected call of ResendVerifyEmailTx.
+func (mr *MockStoreMockRecorder) ResendVerifyEmailTx(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerifyEmailTx", reflect.TypeOf((*MockStore)(nil).ResendVerifyEmailTx), arg0, arg1)
 }
 
-// ListEntries mocks base method.
-func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+// ResetPasswordTx mocks base method.
+func (m *MockStore) ResetPasswordTx(arg0 context.Context, arg1 db.ResetPasswordTxParams) (db.ResetPasswordTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
-	ret0, _ := ret[0].([]db.Entry)
+	ret := m.ctrl.Call(m, "ResetPasswordTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ResetPasswordTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListEntries indicates an expected call of ListEntries.
-func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 any) *gomock.Call {
+// ResetPasswordTx indicates an expected call of ResetPasswordTx.
+func (mr *MockStoreMockRecorder) ResetPasswordTx(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPasswordTx", reflect.TypeOf((*MockStore)(nil).ResetPasswordTx), arg0, arg1)
 }
 
-// ListTransfers mocks base method.
-func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
+// RotateSession mocks base method.
+func (m *MockStore) RotateSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
-	ret0, _ := ret[0].([]db.Transfer)
+	ret := m.ctrl.Call(m, "RotateSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListTransfers indicates an expected call of ListTransfers.
-func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 any) *gomock.Call {
+// RotateSession indicates an expected call of RotateSession.
+func (mr *MockStoreMockRecorder) RotateSession(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSession", reflect.TypeOf((*MockStore)(nil).RotateSession), arg0, arg1)
 }
 
 // TransferTx mocks base method.
@@ -398,6 +1256,36 @@ func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
 }
 
+// UnlockUserLogin mocks base method.
+func (m *MockStore) UnlockUserLogin(arg0 context.Context, arg1 db.UnlockUserLoginParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockUserLogin", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlockUserLogin indicates an expected call of UnlockUserLogin.
+func (mr *MockStoreMockRecorder) UnlockUserLogin(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockUserLogin", reflect.TypeOf((*MockStore)(nil).UnlockUserLogin), arg0, arg1)
+}
+
+// UnlockUserLoginTx mocks base method.
+func (m *MockStore) UnlockUserLoginTx(arg0 context.Context, arg1 db.UnlockUserLoginTxParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockUserLoginTx", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlockUserLoginTx indicates an expected call of UnlockUserLoginTx.
+func (mr *MockStoreMockRecorder) UnlockUserLoginTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockUserLoginTx", reflect.TypeOf((*MockStore)(nil).UnlockUserLoginTx), arg0, arg1)
+}
+
 // UpdateAccount mocks base method.
 func (m *MockStore) UpdateAccount(arg0 context.Context, arg1 db.UpdateAccountParams) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -413,6 +1301,36 @@ func (mr *MockStoreMockRecorder) UpdateAccount(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), arg0, arg1)
 }
 
+// UpdateAccountStatus mocks base method.
+func (m *MockStore) UpdateAccountStatus(arg0 context.Context, arg1 db.UpdateAccountStatusParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountStatus indicates an expected call of UpdateAccountStatus.
+func (mr *MockStoreMockRecorder) UpdateAccountStatus(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatus", reflect.TypeOf((*MockStore)(nil).UpdateAccountStatus), arg0, arg1)
+}
+
+// UpdateAccountStatusTx mocks base method.
+func (m *MockStore) UpdateAccountStatusTx(arg0 context.Context, arg1 db.UpdateAccountStatusTxParams) (db.UpdateAccountStatusTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountStatusTx", arg0, arg1)
+	ret0, _ := ret[0].(db.UpdateAccountStatusTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountStatusTx indicates an expected call of UpdateAccountStatusTx.
+func (mr *MockStoreMockRecorder) UpdateAccountStatusTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatusTx", reflect.TypeOf((*MockStore)(nil).UpdateAccountStatusTx), arg0, arg1)
+}
+
 // UpdateEntry mocks base method.
 func (m *MockStore) UpdateEntry(arg0 context.Context, arg1 db.UpdateEntryParams) (db.Entry, error) {
 	m.ctrl.T.Helper()
@@ -428,6 +1346,20 @@ func (mr *MockStoreMockRecorder) UpdateEntry(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEntry", reflect.TypeOf((*MockStore)(nil).UpdateEntry), arg0, arg1)
 }
 
+// UpdateIdempotencyKeyResponse mocks base method.
+func (m *MockStore) UpdateIdempotencyKeyResponse(arg0 context.Context, arg1 db.UpdateIdempotencyKeyResponseParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIdempotencyKeyResponse", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIdempotencyKeyResponse indicates an expected call of UpdateIdempotencyKeyResponse.
+func (mr *MockStoreMockRecorder) UpdateIdempotencyKeyResponse(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIdempotencyKeyResponse", reflect.TypeOf((*MockStore)(nil).UpdateIdempotencyKeyResponse), arg0, arg1)
+}
+
 // UpdateTransfer mocks base method.
 func (m *MockStore) UpdateTransfer(arg0 context.Context, arg1 db.UpdateTransferParams) (db.Transfer, error) {
 	m.ctrl.T.Helper()
@@ -458,6 +1390,21 @@ func (mr *MockStoreMockRecorder) UpdateUser(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStore)(nil).UpdateUser), arg0, arg1)
 }
 
+// UpdateUserTx mocks base method.
+func (m *MockStore) UpdateUserTx(arg0 context.Context, arg1 db.UpdateUserTxParams) (db.UpdateUserTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserTx", arg0, arg1)
+	ret0, _ := ret[0].(db.UpdateUserTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserTx indicates an expected call of UpdateUserTx.
+func (mr *MockStoreMockRecorder) UpdateUserTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserTx", reflect.TypeOf((*MockStore)(nil).UpdateUserTx), arg0, arg1)
+}
+
 // UpdateVerifyEmails mocks base method.
 func (m *MockStore) UpdateVerifyEmails(arg0 context.Context, arg1 db.UpdateVerifyEmailsParams) error {
 	m.ctrl.T.Helper()
@@ -471,3 +1418,93 @@ func (mr *MockStoreMockRecorder) UpdateVerifyEmails(arg0, arg1 any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVerifyEmails", reflect.TypeOf((*MockStore)(nil).UpdateVerifyEmails), arg0, arg1)
 }
+
+// UpsertFXRate mocks base method.
+func (m *MockStore) UpsertFXRate(arg0 context.Context, arg1 db.UpsertFXRateParams) (db.FxRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertFXRate", arg0, arg1)
+	ret0, _ := ret[0].(db.FxRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertFXRate indicates an expected call of UpsertFXRate.
+func (mr *MockStoreMockRecorder) UpsertFXRate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertFXRate", reflect.TypeOf((*MockStore)(nil).UpsertFXRate), arg0, arg1)
+}
+
+// UpsertNotificationPreference mocks base method.
+func (m *MockStore) UpsertNotificationPreference(arg0 context.Context, arg1 db.UpsertNotificationPreferenceParams) (db.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotificationPreference", arg0, arg1)
+	ret0, _ := ret[0].(db.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertNotificationPreference indicates an expected call of UpsertNotificationPreference.
+func (mr *MockStoreMockRecorder) UpsertNotificationPreference(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationPreference", reflect.TypeOf((*MockStore)(nil).UpsertNotificationPreference), arg0, arg1)
+}
+
+// UsePasswordReset mocks base method.
+func (m *MockStore) UsePasswordReset(arg0 context.Context, arg1 int64) (db.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UsePasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UsePasswordReset indicates an expected call of UsePasswordReset.
+func (mr *MockStoreMockRecorder) UsePasswordReset(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UsePasswordReset", reflect.TypeOf((*MockStore)(nil).UsePasswordReset), arg0, arg1)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockStore) UseRecoveryCode(arg0 context.Context, arg1 db.UseRecoveryCodeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockStoreMockRecorder) UseRecoveryCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockStore)(nil).UseRecoveryCode), arg0, arg1)
+}
+
+// UseTOTPStep mocks base method.
+func (m *MockStore) UseTOTPStep(arg0 context.Context, arg1 db.UseTOTPStepParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTOTPStep", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseTOTPStep indicates an expected call of UseTOTPStep.
+func (mr *MockStoreMockRecorder) UseTOTPStep(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTOTPStep", reflect.TypeOf((*MockStore)(nil).UseTOTPStep), arg0, arg1)
+}
+
+// VerifyUserEmail mocks base method.
+func (m *MockStore) VerifyUserEmail(arg0 context.Context, arg1 db.VerifyUserEmailParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyUserEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyUserEmail indicates an expected call of VerifyUserEmail.
+func (mr *MockStoreMockRecorder) VerifyUserEmail(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyUserEmail", reflect.TypeOf((*MockStore)(nil).VerifyUserEmail), arg0, arg1)
+}
@@ -25,9 +25,11 @@ FOR NO KEY UPDATE;
 -- name: ListAccounts :many
 SELECT *
 FROM accounts
-WHERE user_id = $1
-ORDER BY id DESC
-LIMIT $2 OFFSET $3;
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
 
 -- name: UpdateAccount :one
 UPDATE accounts SET balance = $2
@@ -39,5 +41,10 @@ UPDATE accounts SET balance = balance + sqlc.arg(amount)
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
--- name: DeleteAccount :exec
-DELETE FROM accounts WHERE id = $1;
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status            = sqlc.arg(status),
+    status_reason     = sqlc.arg(status_reason),
+    status_changed_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
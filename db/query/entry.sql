@@ -1,7 +1,8 @@
 -- name: CreateEntry :one
 INSERT INTO entries (account_id,
-                     amount)
-VALUES ($1, $2)
+                     amount,
+                     transfer_id)
+VALUES ($1, $2, $3)
 RETURNING *;
 
 -- name: GetEntry :one
@@ -12,8 +13,32 @@ WHERE id = $1;
 -- name: ListEntries :many
 SELECT *
 FROM entries
-ORDER BY id DESC
-LIMIT $1 OFFSET $2;
+WHERE account_id = sqlc.arg(account_id)
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: GetEntriesSumSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint
+FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(since);
+
+-- name: ListStatementEntries :many
+SELECT e.id,
+       e.account_id,
+       e.amount,
+       e.transfer_id,
+       e.created_at,
+       COALESCE(CASE WHEN t.from_account_id = e.account_id THEN t.to_account_id ELSE t.from_account_id END,
+                0)::bigint AS counterparty_account_id
+FROM entries e
+         LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE e.account_id = sqlc.arg(account_id)
+  AND e.created_at >= sqlc.arg(from_time)
+  AND e.created_at < sqlc.arg(to_time)
+ORDER BY e.created_at, e.id;
 
 -- name: UpdateEntry :one
 UPDATE entries
@@ -0,0 +1,15 @@
+-- name: GetFXRate :one
+SELECT *
+FROM fx_rates
+WHERE base_currency = $1
+  AND quote_currency = $2;
+
+-- name: UpsertFXRate :one
+INSERT INTO fx_rates (base_currency,
+                      quote_currency,
+                      rate)
+VALUES ($1, $2, $3)
+ON CONFLICT (base_currency, quote_currency) DO UPDATE
+SET rate = EXCLUDED.rate,
+    updated_at = now()
+RETURNING *;
@@ -0,0 +1,23 @@
+-- name: CreateIdempotencyKey :one
+INSERT INTO idempotency_keys (user_id,
+                              idempotency_key,
+                              request_hash,
+                              expires_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, idempotency_key) DO UPDATE
+SET request_hash = EXCLUDED.request_hash,
+    response = NULL,
+    created_at = now(),
+    expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= now()
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT *
+FROM idempotency_keys
+WHERE user_id = $1 AND idempotency_key = $2;
+
+-- name: UpdateIdempotencyKeyResponse :exec
+UPDATE idempotency_keys
+SET response = $2
+WHERE id = $1;
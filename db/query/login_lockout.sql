@@ -0,0 +1,52 @@
+-- name: CreateLoginFailure :exec
+INSERT INTO login_failures (email,
+                            client_ip)
+VALUES ($1, $2);
+
+-- name: GetLoginFailureStats :one
+-- Failures of the email and of the client IP within the window, along with the latest of each.
+SELECT (SELECT count(*) FROM login_failures f WHERE f.email = sqlc.arg(email) AND f.created_at > sqlc.arg(since))::bigint AS email_failures,
+       coalesce((SELECT max(f.created_at) FROM login_failures f WHERE f.email = sqlc.arg(email) AND f.created_at > sqlc.arg(since)), to_timestamp(0))::timestamptz AS email_last_failure,
+       (SELECT count(*) FROM login_failures f WHERE f.client_ip = sqlc.arg(client_ip) AND f.created_at > sqlc.arg(since))::bigint AS ip_failures,
+       coalesce((SELECT max(f.created_at) FROM login_failures f WHERE f.client_ip = sqlc.arg(client_ip) AND f.created_at > sqlc.arg(since)), to_timestamp(0))::timestamptz AS ip_last_failure;
+
+-- name: ClearLoginFailures :exec
+DELETE
+FROM login_failures
+WHERE email = $1;
+
+-- name: CreateLoginLockout :one
+INSERT INTO login_lockouts (email,
+                            user_id,
+                            client_ip,
+                            failed_attempts,
+                            locked_until)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetActiveLoginLockout :one
+SELECT *
+FROM login_lockouts
+WHERE email = $1
+  AND unlocked_at IS NULL
+  AND locked_until > now()
+ORDER BY locked_until DESC
+LIMIT 1;
+
+-- name: ListLoginLockouts :many
+SELECT *
+FROM login_lockouts
+WHERE (sqlc.narg(user_id)::bigint IS NULL OR user_id = sqlc.narg(user_id))
+  AND (NOT sqlc.arg(active_only)::boolean OR (unlocked_at IS NULL AND locked_until > now()))
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: UnlockUserLogin :execrows
+UPDATE login_lockouts
+SET unlocked_at = now(),
+    unlocked_by = sqlc.arg(unlocked_by)::bigint
+WHERE user_id = sqlc.arg(user_id)::bigint
+  AND unlocked_at IS NULL
+  AND locked_until > now();
@@ -0,0 +1,50 @@
+-- name: CreateNotification :one
+-- a retried task finds the notification of the transfer already there and gets no rows
+INSERT INTO notifications (user_id,
+                           event,
+                           transfer_id,
+                           account_id,
+                           counterparty_account_id,
+                           amount,
+                           currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (user_id, event, transfer_id) DO NOTHING
+RETURNING *;
+
+-- name: ListNotifications :many
+SELECT *
+FROM notifications
+WHERE user_id = sqlc.arg(user_id)
+  AND (NOT sqlc.arg(unread_only)::boolean OR read_at IS NULL)
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountUnreadNotifications :one
+SELECT count(*)
+FROM notifications
+WHERE user_id = $1
+  AND read_at IS NULL;
+
+-- name: MarkNotificationsRead :execrows
+UPDATE notifications
+SET read_at = now()
+WHERE user_id = sqlc.arg(user_id)
+  AND read_at IS NULL
+  AND (sqlc.arg(all_notifications)::boolean OR id = ANY (sqlc.arg(ids)::bigint[]));
+
+-- name: ListNotificationPreferences :many
+SELECT *
+FROM notification_preferences
+WHERE user_id = $1
+ORDER BY event;
+
+-- name: UpsertNotificationPreference :one
+INSERT INTO notification_preferences (user_id, event, in_app, email)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, event) DO UPDATE
+    SET in_app     = EXCLUDED.in_app,
+        email      = EXCLUDED.email,
+        updated_at = now()
+RETURNING *;
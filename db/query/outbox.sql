@@ -0,0 +1,36 @@
+-- name: CreateOutboxTask :one
+INSERT INTO outbox (task_type,
+                    payload,
+                    queue,
+                    max_retry,
+                    timeout,
+                    process_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ClaimOutboxTasks :many
+-- the rows stay locked until the relay transaction ends, other relays skip them
+SELECT *
+FROM outbox
+WHERE sent_at IS NULL
+  AND next_attempt_at <= now()
+ORDER BY next_attempt_at, id
+LIMIT $1 FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxTaskSent :exec
+UPDATE outbox
+SET sent_at  = now(),
+    attempts = attempts + 1
+WHERE id = $1;
+
+-- name: MarkOutboxTaskFailed :exec
+UPDATE outbox
+SET attempts        = attempts + 1,
+    last_error      = sqlc.arg(last_error),
+    next_attempt_at = sqlc.arg(next_attempt_at)
+WHERE id = sqlc.arg(id);
+
+-- name: DeleteSentOutboxTasks :execrows
+DELETE
+FROM outbox
+WHERE sent_at < sqlc.arg(sent_before)::timestamptz;
@@ -0,0 +1,36 @@
+-- name: CreatePasswordReset :one
+INSERT INTO password_resets (user_id,
+                             code_hash,
+                             expired_at)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetPasswordReset :one
+SELECT *
+FROM password_resets
+WHERE id = $1;
+
+-- name: CreatePasswordResetRequest :exec
+INSERT INTO password_reset_requests (user_id)
+VALUES ($1);
+
+-- name: CountPasswordResetRequests :one
+SELECT count(*)
+FROM password_reset_requests
+WHERE user_id = $1
+  AND created_at > sqlc.arg(since);
+
+-- name: UsePasswordReset :one
+UPDATE password_resets
+SET used_at = now()
+WHERE id = $1
+  AND used_at IS NULL
+  AND expired_at > now()
+RETURNING *;
+
+-- name: ExpirePasswordResets :exec
+UPDATE password_resets
+SET expired_at = now()
+WHERE user_id = $1
+  AND used_at IS NULL
+  AND expired_at > now();
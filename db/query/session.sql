@@ -5,11 +5,57 @@ INSERT INTO sessions (id,
                       user_agent,
                       client_ip,
                       is_blocked,
-                      expires_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+                      expires_at,
+                      family_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING *;
 
 -- name: GetSession :one
 SELECT *
 FROM sessions
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: GetSessionForUpdate :one
+SELECT *
+FROM sessions
+WHERE id = $1
+FOR UPDATE;
+
+-- name: RotateSession :one
+UPDATE sessions
+SET rotated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: BlockSessionFamily :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE family_id = $1;
+
+-- name: ListActiveSessions :many
+-- Rotated sessions are replaced by their successors, so only the head of every family is listed,
+-- along with the time of the login that started the family.
+SELECT s.*, f.created_at AS logged_in_at
+FROM sessions s
+         JOIN sessions f ON f.id = s.family_id
+WHERE s.user_id = $1
+  AND NOT s.is_blocked
+  AND s.rotated_at IS NULL
+  AND s.expires_at > now()
+ORDER BY f.created_at DESC;
+
+-- name: BlockUserSessions :execrows
+UPDATE sessions
+SET is_blocked = true
+WHERE user_id = sqlc.arg(user_id)
+  AND NOT is_blocked
+  AND (sqlc.narg(family_id)::uuid IS NULL OR family_id = sqlc.narg(family_id))
+  AND (sqlc.narg(except_family_id)::uuid IS NULL OR family_id <> sqlc.narg(except_family_id));
+
+-- name: GetLoginDeviceStats :one
+-- Counts the sessions of the user, and those opened from the client IP with the user agent.
+SELECT count(*) AS sessions,
+       count(*) FILTER (WHERE client_ip = sqlc.arg(client_ip)
+           AND user_agent = sqlc.arg(user_agent)) AS device_sessions
+FROM sessions
+WHERE user_id = sqlc.arg(user_id);
@@ -0,0 +1,77 @@
+-- name: EnrolUserTOTP :one
+-- A confirmed secret is never replaced, it has to be disabled first.
+INSERT INTO user_totp (user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+    SET secret         = EXCLUDED.secret,
+        last_used_step = 0,
+        created_at     = now()
+WHERE user_totp.confirmed_at IS NULL
+RETURNING *;
+
+-- name: GetUserTOTP :one
+SELECT *
+FROM user_totp
+WHERE user_id = $1;
+
+-- name: ConfirmUserTOTP :one
+UPDATE user_totp
+SET confirmed_at = now()
+WHERE user_id = $1
+  AND confirmed_at IS NULL
+RETURNING *;
+
+-- name: UseTOTPStep :execrows
+UPDATE user_totp
+SET last_used_step = sqlc.arg(step)
+WHERE user_id = sqlc.arg(user_id)
+  AND last_used_step < sqlc.arg(step);
+
+-- name: DeleteUserTOTP :exec
+DELETE
+FROM user_totp
+WHERE user_id = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO totp_recovery_codes (user_id, code_hash)
+VALUES ($1, $2);
+
+-- name: UseRecoveryCode :execrows
+UPDATE totp_recovery_codes
+SET used_at = now()
+WHERE user_id = $1
+  AND code_hash = $2
+  AND used_at IS NULL;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE
+FROM totp_recovery_codes
+WHERE user_id = $1;
+
+-- name: CreateMFAChallenge :one
+INSERT INTO mfa_challenges (id, user_id, expires_at)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetPendingMFAChallenge :one
+SELECT *
+FROM mfa_challenges
+WHERE id = $1
+  AND consumed_at IS NULL
+  AND expires_at > now();
+
+-- name: AttemptMFAChallenge :one
+-- Counts the attempt; no row is returned for an expired, consumed or exhausted challenge.
+UPDATE mfa_challenges
+SET attempts = attempts + 1
+WHERE id = sqlc.arg(id)
+  AND consumed_at IS NULL
+  AND expires_at > now()
+  AND attempts < sqlc.arg(max_attempts)::int
+RETURNING *;
+
+-- name: ConsumeMFAChallenge :execrows
+UPDATE mfa_challenges
+SET consumed_at = now()
+WHERE id = $1
+  AND consumed_at IS NULL;
@@ -10,8 +10,10 @@ CREATE TABLE "transfers"
 -- name: CreateTransfer :one
 INSERT INTO transfers (from_account_id,
                        to_account_id,
-                       amount)
-VALUES ($1, $2, $3)
+                       amount,
+                       to_amount,
+                       exchange_rate)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: GetTransfer :one
@@ -22,8 +24,40 @@ WHERE id = $1;
 -- name: ListTransfers :many
 SELECT *
 FROM transfers
-ORDER BY id DESC
-LIMIT $1 OFFSET $2;
+WHERE (sqlc.narg(min_amount)::bigint IS NULL OR amount >= sqlc.narg(min_amount))
+  AND (sqlc.narg(max_amount)::bigint IS NULL OR amount <= sqlc.narg(max_amount))
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR created_at < sqlc.narg(to_time))
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListAccountTransfers :many
+-- Outgoing and incoming transfers are read by separate branches
+-- so that each one is served by its own from/to account index.
+SELECT t.*
+FROM (SELECT *
+      FROM transfers
+      WHERE transfers.from_account_id = sqlc.arg(account_id)
+        AND sqlc.arg(outgoing)::boolean
+        AND (sqlc.narg(counterparty_account_id)::bigint IS NULL
+          OR transfers.to_account_id = sqlc.narg(counterparty_account_id))
+      UNION ALL
+      SELECT *
+      FROM transfers
+      WHERE transfers.to_account_id = sqlc.arg(account_id)
+        AND sqlc.arg(incoming)::boolean
+        AND (sqlc.narg(counterparty_account_id)::bigint IS NULL
+          OR transfers.from_account_id = sqlc.narg(counterparty_account_id))) AS t
+WHERE (sqlc.narg(min_amount)::bigint IS NULL OR t.amount >= sqlc.narg(min_amount))
+  AND (sqlc.narg(max_amount)::bigint IS NULL OR t.amount <= sqlc.narg(max_amount))
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time))
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+    OR (t.created_at, t.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT sqlc.arg(page_limit);
 
 -- name: UpdateTransfer :one
 UPDATE transfers
@@ -3,8 +3,9 @@ INSERT INTO users (username,
                       role,
                       hashed_password,
                       full_name,
-                      email)
-VALUES ($1, $2, $3, $4, $5)
+                      email,
+                      locale)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING *;
 
 -- name: GetUser :one
@@ -12,6 +13,11 @@ SELECT *
 FROM users
 WHERE id = $1;
 
+-- name: GetUserForUpdate :one
+SELECT *
+FROM users
+WHERE id = $1 FOR NO KEY UPDATE;
+
 -- name: GetUserByEmail :one
 SELECT *
 FROM users
@@ -23,6 +29,14 @@ SET hashed_password = COALESCE(sqlc.narg(hashed_password), hashed_password),
     full_name = COALESCE(sqlc.narg(full_name), full_name),
     email = COALESCE(sqlc.narg(email), email),
     is_verified = COALESCE(sqlc.narg(is_verified), is_verified),
+    locale = COALESCE(sqlc.narg(locale), locale),
     password_changed_at = (CASE WHEN sqlc.narg(hashed_password) IS null THEN password_changed_at ELSE now() END)
 WHERE id = sqlc.arg(id)
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+-- name: VerifyUserEmail :one
+-- the email must still be the one the code was sent to
+UPDATE users
+SET is_verified = true
+WHERE id = $1
+  AND email = $2
+RETURNING *;
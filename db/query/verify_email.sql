@@ -1,11 +1,16 @@
 -- name: CreateVerifyEmail :one
+-- a new code for the same email replaces the pending one and starts its expiry over
 INSERT INTO verify_emails (user_id,
                       email,
                       code,
                       is_used,
                       expired_at)
 VALUES ($1, $2, $3, $4, $5)
-ON CONFLICT (email) DO UPDATE SET code = $3
+ON CONFLICT (email) DO UPDATE SET user_id    = EXCLUDED.user_id,
+                                  code       = EXCLUDED.code,
+                                  is_used    = EXCLUDED.is_used,
+                                  expired_at = EXCLUDED.expired_at,
+                                  created_at = now()
 RETURNING *;
 
 -- name: GetVerifyEmail :one
@@ -17,3 +22,13 @@ WHERE id = $1;
 UPDATE verify_emails
 SET is_used = $1
 WHERE id = $2;
+
+-- name: CreateVerifyEmailRequest :exec
+INSERT INTO verify_email_requests (user_id)
+VALUES ($1);
+
+-- name: CountVerifyEmailRequests :one
+SELECT count(*)
+FROM verify_email_requests
+WHERE user_id = $1
+  AND created_at > sqlc.arg(since);
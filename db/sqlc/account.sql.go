@@ -7,12 +7,14 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const addBalanceToAccount = `-- name: AddBalanceToAccount :one
 UPDATE accounts SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at, user_id
+RETURNING id, owner, balance, currency, created_at, user_id, status, status_reason, status_changed_at
 `
 
 type AddBalanceToAccountParams struct {
@@ -30,6 +32,9 @@ func (q *Queries) AddBalanceToAccount(ctx context.Context, arg AddBalanceToAccou
 		&i.Currency,
 		&i.CreatedAt,
 		&i.UserID,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
 	)
 	return i, err
 }
@@ -40,7 +45,7 @@ INSERT INTO accounts (owner,
                       balance,
                       currency)
 VALUES ($1, $2, $3, $4)
-RETURNING id, owner, balance, currency, created_at, user_id
+RETURNING id, owner, balance, currency, created_at, user_id, status, status_reason, status_changed_at
 `
 
 type CreateAccountParams struct {
@@ -65,21 +70,15 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Currency,
 		&i.CreatedAt,
 		&i.UserID,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
 	)
 	return i, err
 }
 
-const deleteAccount = `-- name: DeleteAccount :exec
-DELETE FROM accounts WHERE id = $1
-`
-
-func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteAccount, id)
-	return err
-}
-
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at, user_id
+SELECT id, owner, balance, currency, created_at, user_id, status, status_reason, status_changed_at
 FROM accounts
 WHERE id = $1
 `
@@ -94,12 +93,15 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Currency,
 		&i.CreatedAt,
 		&i.UserID,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at, user_id
+SELECT id, owner, balance, currency, created_at, user_id, status, status_reason, status_changed_at
 FROM accounts
 WHERE id = $1
 FOR NO KEY UPDATE
@@ -115,12 +117,15 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Currency,
 		&i.CreatedAt,
 		&i.UserID,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
 	)
 	return i, err
 }
 
 const getUserAccount = `-- name: GetUserAccount :one
-SELECT id, owner, balance, currency, created_at, user_id
+SELECT id, owner, balance, currency, created_at, user_id, status, status_reason, status_changed_at
 FROM accounts
 WHERE user_id = $1 and id = $2
 `
@@ -140,26 +145,37 @@ func (q *Queries) GetUserAccount(ctx context.Context, arg GetUserAccountParams)
 		&i.Currency,
 		&i.CreatedAt,
 		&i.UserID,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at, user_id
+SELECT id, owner, balance, currency, created_at, user_id, status, status_reason, status_changed_at
 FROM accounts
 WHERE user_id = $1
-ORDER BY id DESC
-LIMIT $2 OFFSET $3
+  AND ($2::timestamptz IS NULL
+    OR (created_at, id) < ($2::timestamptz, $3::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
 `
 
 type ListAccountsParams struct {
-	UserID int64 `json:"user_id"`
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	UserID          int64              `json:"user_id"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	PageLimit       int32              `json:"page_limit"`
 }
 
 func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
-	rows, err := q.db.Query(ctx, listAccounts, arg.UserID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listAccounts,
+		arg.UserID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -174,6 +190,9 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Currency,
 			&i.CreatedAt,
 			&i.UserID,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -188,7 +207,7 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts SET balance = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at, user_id
+RETURNING id, owner, balance, currency, created_at, user_id, status, status_reason, status_changed_at
 `
 
 type UpdateAccountParams struct {
@@ -206,6 +225,41 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Currency,
 		&i.CreatedAt,
 		&i.UserID,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status            = $1,
+    status_reason     = $2,
+    status_changed_at = now()
+WHERE id = $3
+RETURNING id, owner, balance, currency, created_at, user_id, status, status_reason, status_changed_at
+`
+
+type UpdateAccountStatusParams struct {
+	Status       string `json:"status"`
+	StatusReason string `json:"status_reason"`
+	ID           int64  `json:"id"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountStatus, arg.Status, arg.StatusReason, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.UserID,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
 	)
 	return i, err
 }
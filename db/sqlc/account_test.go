@@ -72,16 +72,21 @@ func TestUpdateAccount(t *testing.T) {
 	require.WithinDuration(t, acc1.CreatedAt, acc2.CreatedAt, time.Second)
 }
 
-func TestDeleteAccount(t *testing.T) {
+func TestUpdateAccountStatus(t *testing.T) {
 	acc1, _ := createRandAccount(t)
-	err := testStore.DeleteAccount(context.Background(), acc1.ID)
+	require.Equal(t, AccountStatusActive, acc1.Status)
 
-	require.NoError(t, err)
-
-	acc2, err := testStore.GetUserAccount(context.Background(), GetUserAccountParams{acc1.UserID, acc1.ID})
+	acc2, err := testStore.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:           acc1.ID,
+		Status:       AccountStatusFrozen,
+		StatusReason: "suspicious activity",
+	})
 
-	require.Empty(t, acc2)
-	require.ErrorContains(t, err, "no rows")
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusFrozen, acc2.Status)
+	require.Equal(t, "suspicious activity", acc2.StatusReason)
+	require.True(t, acc2.StatusChangedAt.After(acc1.StatusChangedAt))
+	require.Equal(t, acc1.Balance, acc2.Balance)
 }
 
 func TestListAccounts(t *testing.T) {
@@ -93,9 +98,8 @@ func TestListAccounts(t *testing.T) {
 	}
 
 	accounts, err := testStore.ListAccounts(context.Background(), ListAccountsParams{
-		UserID: user.ID,
-		Limit:  5,
-		Offset: 0,
+		UserID:    user.ID,
+		PageLimit: 5,
 	})
 
 	require.NoError(t, err)
@@ -104,4 +108,19 @@ func TestListAccounts(t *testing.T) {
 	for _, account := range accounts {
 		require.NotEmpty(t, account)
 	}
+
+	// the cursor of the first row skips it and keeps the descending order
+	first := accounts[0]
+	token, err := DecodePageToken(EncodePageToken(first.CreatedAt, first.ID))
+	require.NoError(t, err)
+
+	rest, err := testStore.ListAccounts(context.Background(), ListAccountsParams{
+		UserID:          user.ID,
+		CursorCreatedAt: token.CursorCreatedAt(),
+		CursorID:        token.CursorID(),
+		PageLimit:       5,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, accounts[1:], rest)
 }
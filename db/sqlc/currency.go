@@ -0,0 +1,29 @@
+package db
+
+import (
+	"bank/utils"
+	"context"
+	"fmt"
+)
+
+// LoadCurrencies replaces the currency registry of utils with the rows of the currencies table.
+func LoadCurrencies(ctx context.Context, querier Querier) error {
+	rows, err := querier.ListCurrencies(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot load currencies: %w", err)
+	}
+
+	currencies := make([]utils.Currency, 0, len(rows))
+	for _, row := range rows {
+		currencies = append(currencies, utils.Currency{
+			Code:        row.Code,
+			NumericCode: row.NumericCode,
+			Exponent:    row.Exponent,
+			Symbol:      row.Symbol,
+			Enabled:     row.Enabled,
+		})
+	}
+	utils.SetCurrencies(currencies)
+
+	return nil
+}
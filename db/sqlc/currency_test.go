@@ -0,0 +1,21 @@
+package db
+
+import (
+	"bank/utils"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCurrencies(t *testing.T) {
+	err := LoadCurrencies(context.Background(), testStore)
+	require.NoError(t, err)
+
+	for _, code := range []string{utils.USD, utils.EUR, utils.UAH} {
+		currency, ok := utils.GetCurrency(code)
+		require.True(t, ok)
+		require.True(t, currency.Enabled)
+		require.Equal(t, int32(2), currency.Exponent)
+	}
+}
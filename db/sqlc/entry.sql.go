@@ -7,28 +7,34 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (account_id,
-                     amount)
-VALUES ($1, $2)
-RETURNING id, account_id, amount, created_at
+                     amount,
+                     transfer_id)
+VALUES ($1, $2, $3)
+RETURNING id, account_id, amount, created_at, transfer_id
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"account_id"`
-	Amount    int64 `json:"amount"`
+	AccountID  int64       `json:"account_id"`
+	Amount     int64       `json:"amount"`
+	TransferID pgtype.Int8 `json:"transfer_id"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRow(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createEntry, arg.AccountID, arg.Amount, arg.TransferID)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
@@ -44,8 +50,27 @@ func (q *Queries) DeleteEntry(ctx context.Context, id int64) error {
 	return err
 }
 
+const getEntriesSumSince = `-- name: GetEntriesSumSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint
+FROM entries
+WHERE account_id = $1
+  AND created_at >= $2
+`
+
+type GetEntriesSumSinceParams struct {
+	AccountID int64     `json:"account_id"`
+	Since     time.Time `json:"since"`
+}
+
+func (q *Queries) GetEntriesSumSince(ctx context.Context, arg GetEntriesSumSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getEntriesSumSince, arg.AccountID, arg.Since)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at
+SELECT id, account_id, amount, created_at, transfer_id
 FROM entries
 WHERE id = $1
 `
@@ -58,24 +83,35 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
 
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at
+SELECT id, account_id, amount, created_at, transfer_id
 FROM entries
-ORDER BY id DESC
-LIMIT $1 OFFSET $2
+WHERE account_id = $1
+  AND ($2::timestamptz IS NULL
+    OR (created_at, id) < ($2::timestamptz, $3::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
 `
 
 type ListEntriesParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	AccountID       int64              `json:"account_id"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	PageLimit       int32              `json:"page_limit"`
 }
 
 func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
-	rows, err := q.db.Query(ctx, listEntries, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listEntries,
+		arg.AccountID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +124,65 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStatementEntries = `-- name: ListStatementEntries :many
+SELECT e.id,
+       e.account_id,
+       e.amount,
+       e.transfer_id,
+       e.created_at,
+       COALESCE(CASE WHEN t.from_account_id = e.account_id THEN t.to_account_id ELSE t.from_account_id END,
+                0)::bigint AS counterparty_account_id
+FROM entries e
+         LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE e.account_id = $1
+  AND e.created_at >= $2
+  AND e.created_at < $3
+ORDER BY e.created_at, e.id
+`
+
+type ListStatementEntriesParams struct {
+	AccountID int64     `json:"account_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+}
+
+type ListStatementEntriesRow struct {
+	ID                    int64       `json:"id"`
+	AccountID             int64       `json:"account_id"`
+	Amount                int64       `json:"amount"`
+	TransferID            pgtype.Int8 `json:"transfer_id"`
+	CreatedAt             time.Time   `json:"created_at"`
+	CounterpartyAccountID int64       `json:"counterparty_account_id"`
+}
+
+func (q *Queries) ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error) {
+	rows, err := q.db.Query(ctx, listStatementEntries, arg.AccountID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStatementEntriesRow{}
+	for rows.Next() {
+		var i ListStatementEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.TransferID,
+			&i.CreatedAt,
+			&i.CounterpartyAccountID,
 		); err != nil {
 			return nil, err
 		}
@@ -103,7 +198,7 @@ const updateEntry = `-- name: UpdateEntry :one
 UPDATE entries
 SET amount = $2
 WHERE id = $1
-RETURNING id, account_id, amount, created_at
+RETURNING id, account_id, amount, created_at, transfer_id
 `
 
 type UpdateEntryParams struct {
@@ -119,6 +214,7 @@ func (q *Queries) UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entry
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -90,15 +91,27 @@ func TestListEntries(t *testing.T) {
 		createNewEntry(t, newCreateEntryParams(acc))
 	}
 
-	entries, err := testStore.ListEntries(context.Background(), ListEntriesParams{
-		Limit:  5,
-		Offset: 5,
+	page1, err := testStore.ListEntries(context.Background(), ListEntriesParams{
+		AccountID: acc.ID,
+		PageLimit: 5,
 	})
 
 	require.NoError(t, err)
-	require.Len(t, entries, 5)
+	require.Len(t, page1, 5)
+
+	last := page1[len(page1)-1]
+	page2, err := testStore.ListEntries(context.Background(), ListEntriesParams{
+		AccountID:       acc.ID,
+		CursorCreatedAt: pgtype.Timestamptz{Time: last.CreatedAt, Valid: true},
+		CursorID:        pgtype.Int8{Int64: last.ID, Valid: true},
+		PageLimit:       5,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, page2, 5)
 
-	for _, entry := range entries {
-		require.NotEmpty(t, entry)
+	for _, entry := range page2 {
+		require.Equal(t, acc.ID, entry.AccountID)
+		require.Less(t, entry.ID, last.ID)
 	}
 }
@@ -0,0 +1,29 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrRecordNotFound is returned by the generated queries when no row matches.
+var ErrRecordNotFound = pgx.ErrNoRows
+
+var (
+	ErrAccountNotFound   = errors.New("account not found")
+	ErrSameAccount       = errors.New("cannot transfer to the same account")
+	ErrCurrencyMismatch  = errors.New("accounts currencies mismatch")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrAmountTooSmall    = errors.New("converted amount is too small")
+	ErrUnknownCurrency   = errors.New("unknown currency exponent")
+	ErrAccountFrozen     = errors.New("account is frozen")
+	ErrAccountClosed     = errors.New("account is closed")
+)
+
+// ErrTooManyRequests is returned when a user reaches the rate limit of an operation.
+var ErrTooManyRequests = errors.New("too many requests")
+
+var (
+	ErrInvalidStatusTransition = errors.New("invalid account status transition")
+	ErrAccountBalanceNotZero   = errors.New("account balance must be zero to close it")
+)
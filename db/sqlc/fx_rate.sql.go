@@ -0,0 +1,63 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: fx_rate.sql
+
+package db
+
+import (
+	"context"
+)
+
+const getFXRate = `-- name: GetFXRate :one
+SELECT base_currency, quote_currency, rate, updated_at
+FROM fx_rates
+WHERE base_currency = $1
+  AND quote_currency = $2
+`
+
+type GetFXRateParams struct {
+	BaseCurrency  string `json:"base_currency"`
+	QuoteCurrency string `json:"quote_currency"`
+}
+
+func (q *Queries) GetFXRate(ctx context.Context, arg GetFXRateParams) (FxRate, error) {
+	row := q.db.QueryRow(ctx, getFXRate, arg.BaseCurrency, arg.QuoteCurrency)
+	var i FxRate
+	err := row.Scan(
+		&i.BaseCurrency,
+		&i.QuoteCurrency,
+		&i.Rate,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertFXRate = `-- name: UpsertFXRate :one
+INSERT INTO fx_rates (base_currency,
+                      quote_currency,
+                      rate)
+VALUES ($1, $2, $3)
+ON CONFLICT (base_currency, quote_currency) DO UPDATE
+SET rate = EXCLUDED.rate,
+    updated_at = now()
+RETURNING base_currency, quote_currency, rate, updated_at
+`
+
+type UpsertFXRateParams struct {
+	BaseCurrency  string  `json:"base_currency"`
+	QuoteCurrency string  `json:"quote_currency"`
+	Rate          float64 `json:"rate"`
+}
+
+func (q *Queries) UpsertFXRate(ctx context.Context, arg UpsertFXRateParams) (FxRate, error) {
+	row := q.db.QueryRow(ctx, upsertFXRate, arg.BaseCurrency, arg.QuoteCurrency, arg.Rate)
+	var i FxRate
+	err := row.Scan(
+		&i.BaseCurrency,
+		&i.QuoteCurrency,
+		&i.Rate,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,95 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: idempotency_key.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const createIdempotencyKey = `-- name: CreateIdempotencyKey :one
+INSERT INTO idempotency_keys (user_id,
+                              idempotency_key,
+                              request_hash,
+                              expires_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, idempotency_key) DO UPDATE
+SET request_hash = EXCLUDED.request_hash,
+    response = NULL,
+    created_at = now(),
+    expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= now()
+RETURNING id, user_id, idempotency_key, request_hash, response, created_at, expires_at
+`
+
+type CreateIdempotencyKeyParams struct {
+	UserID         int64     `json:"user_id"`
+	IdempotencyKey string    `json:"idempotency_key"`
+	RequestHash    string    `json:"request_hash"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, createIdempotencyKey,
+		arg.UserID,
+		arg.IdempotencyKey,
+		arg.RequestHash,
+		arg.ExpiresAt,
+	)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+		&i.Response,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT id, user_id, idempotency_key, request_hash, response, created_at, expires_at
+FROM idempotency_keys
+WHERE user_id = $1 AND idempotency_key = $2
+`
+
+type GetIdempotencyKeyParams struct {
+	UserID         int64  `json:"user_id"`
+	IdempotencyKey string `json:"idempotency_key"`
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, getIdempotencyKey, arg.UserID, arg.IdempotencyKey)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+		&i.Response,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const updateIdempotencyKeyResponse = `-- name: UpdateIdempotencyKeyResponse :exec
+UPDATE idempotency_keys
+SET response = $2
+WHERE id = $1
+`
+
+type UpdateIdempotencyKeyResponseParams struct {
+	ID       int64  `json:"id"`
+	Response []byte `json:"response"`
+}
+
+func (q *Queries) UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) error {
+	_, err := q.db.Exec(ctx, updateIdempotencyKeyResponse, arg.ID, arg.Response)
+	return err
+}
@@ -0,0 +1,218 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: login_lockout.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const clearLoginFailures = `-- name: ClearLoginFailures :exec
+DELETE
+FROM login_failures
+WHERE email = $1
+`
+
+func (q *Queries) ClearLoginFailures(ctx context.Context, email string) error {
+	_, err := q.db.Exec(ctx, clearLoginFailures, email)
+	return err
+}
+
+const createLoginFailure = `-- name: CreateLoginFailure :exec
+INSERT INTO login_failures (email,
+                            client_ip)
+VALUES ($1, $2)
+`
+
+type CreateLoginFailureParams struct {
+	Email    string `json:"email"`
+	ClientIp string `json:"client_ip"`
+}
+
+func (q *Queries) CreateLoginFailure(ctx context.Context, arg CreateLoginFailureParams) error {
+	_, err := q.db.Exec(ctx, createLoginFailure, arg.Email, arg.ClientIp)
+	return err
+}
+
+const createLoginLockout = `-- name: CreateLoginLockout :one
+INSERT INTO login_lockouts (email,
+                            user_id,
+                            client_ip,
+                            failed_attempts,
+                            locked_until)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, email, user_id, client_ip, failed_attempts, locked_until, unlocked_at, unlocked_by, created_at
+`
+
+type CreateLoginLockoutParams struct {
+	Email          string      `json:"email"`
+	UserID         pgtype.Int8 `json:"user_id"`
+	ClientIp       string      `json:"client_ip"`
+	FailedAttempts int32       `json:"failed_attempts"`
+	LockedUntil    time.Time   `json:"locked_until"`
+}
+
+func (q *Queries) CreateLoginLockout(ctx context.Context, arg CreateLoginLockoutParams) (LoginLockout, error) {
+	row := q.db.QueryRow(ctx, createLoginLockout,
+		arg.Email,
+		arg.UserID,
+		arg.ClientIp,
+		arg.FailedAttempts,
+		arg.LockedUntil,
+	)
+	var i LoginLockout
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.UserID,
+		&i.ClientIp,
+		&i.FailedAttempts,
+		&i.LockedUntil,
+		&i.UnlockedAt,
+		&i.UnlockedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getActiveLoginLockout = `-- name: GetActiveLoginLockout :one
+SELECT id, email, user_id, client_ip, failed_attempts, locked_until, unlocked_at, unlocked_by, created_at
+FROM login_lockouts
+WHERE email = $1
+  AND unlocked_at IS NULL
+  AND locked_until > now()
+ORDER BY locked_until DESC
+LIMIT 1
+`
+
+func (q *Queries) GetActiveLoginLockout(ctx context.Context, email string) (LoginLockout, error) {
+	row := q.db.QueryRow(ctx, getActiveLoginLockout, email)
+	var i LoginLockout
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.UserID,
+		&i.ClientIp,
+		&i.FailedAttempts,
+		&i.LockedUntil,
+		&i.UnlockedAt,
+		&i.UnlockedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLoginFailureStats = `-- name: GetLoginFailureStats :one
+SELECT (SELECT count(*) FROM login_failures f WHERE f.email = $1 AND f.created_at > $2)::bigint AS email_failures,
+       coalesce((SELECT max(f.created_at) FROM login_failures f WHERE f.email = $1 AND f.created_at > $2), to_timestamp(0))::timestamptz AS email_last_failure,
+       (SELECT count(*) FROM login_failures f WHERE f.client_ip = $3 AND f.created_at > $2)::bigint AS ip_failures,
+       coalesce((SELECT max(f.created_at) FROM login_failures f WHERE f.client_ip = $3 AND f.created_at > $2), to_timestamp(0))::timestamptz AS ip_last_failure
+`
+
+type GetLoginFailureStatsParams struct {
+	Email    string    `json:"email"`
+	Since    time.Time `json:"since"`
+	ClientIp string    `json:"client_ip"`
+}
+
+type GetLoginFailureStatsRow struct {
+	EmailFailures    int64     `json:"email_failures"`
+	EmailLastFailure time.Time `json:"email_last_failure"`
+	IpFailures       int64     `json:"ip_failures"`
+	IpLastFailure    time.Time `json:"ip_last_failure"`
+}
+
+// Failures of the email and of the client IP within the window, along with the latest of each.
+func (q *Queries) GetLoginFailureStats(ctx context.Context, arg GetLoginFailureStatsParams) (GetLoginFailureStatsRow, error) {
+	row := q.db.QueryRow(ctx, getLoginFailureStats, arg.Email, arg.Since, arg.ClientIp)
+	var i GetLoginFailureStatsRow
+	err := row.Scan(
+		&i.EmailFailures,
+		&i.EmailLastFailure,
+		&i.IpFailures,
+		&i.IpLastFailure,
+	)
+	return i, err
+}
+
+const listLoginLockouts = `-- name: ListLoginLockouts :many
+SELECT id, email, user_id, client_ip, failed_attempts, locked_until, unlocked_at, unlocked_by, created_at
+FROM login_lockouts
+WHERE ($1::bigint IS NULL OR user_id = $1)
+  AND (NOT $2::boolean OR (unlocked_at IS NULL AND locked_until > now()))
+  AND ($3::timestamptz IS NULL
+    OR (created_at, id) < ($3::timestamptz, $4::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT $5
+`
+
+type ListLoginLockoutsParams struct {
+	UserID          pgtype.Int8        `json:"user_id"`
+	ActiveOnly      bool               `json:"active_only"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	PageLimit       int32              `json:"page_limit"`
+}
+
+func (q *Queries) ListLoginLockouts(ctx context.Context, arg ListLoginLockoutsParams) ([]LoginLockout, error) {
+	rows, err := q.db.Query(ctx, listLoginLockouts,
+		arg.UserID,
+		arg.ActiveOnly,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []LoginLockout{}
+	for rows.Next() {
+		var i LoginLockout
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.UserID,
+			&i.ClientIp,
+			&i.FailedAttempts,
+			&i.LockedUntil,
+			&i.UnlockedAt,
+			&i.UnlockedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const unlockUserLogin = `-- name: UnlockUserLogin :execrows
+UPDATE login_lockouts
+SET unlocked_at = now(),
+    unlocked_by = $1::bigint
+WHERE user_id = $2::bigint
+  AND unlocked_at IS NULL
+  AND locked_until > now()
+`
+
+type UnlockUserLoginParams struct {
+	UnlockedBy int64 `json:"unlocked_by"`
+	UserID     int64 `json:"user_id"`
+}
+
+func (q *Queries) UnlockUserLogin(ctx context.Context, arg UnlockUserLoginParams) (int64, error) {
+	result, err := q.db.Exec(ctx, unlockUserLogin, arg.UnlockedBy, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
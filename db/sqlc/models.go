@@ -12,22 +12,161 @@ import (
 )
 
 type Account struct {
-	ID        int64     `json:"id"`
-	Owner     string    `json:"owner"`
-	Balance   int64     `json:"balance"`
-	Currency  string    `json:"currency"`
+	ID              int64     `json:"id"`
+	Owner           string    `json:"owner"`
+	Balance         int64     `json:"balance"`
+	Currency        string    `json:"currency"`
+	CreatedAt       time.Time `json:"created_at"`
+	UserID          int64     `json:"user_id"`
+	Status          string    `json:"status"`
+	StatusReason    string    `json:"status_reason"`
+	StatusChangedAt time.Time `json:"status_changed_at"`
+}
+
+type Currency struct {
+	Code        string `json:"code"`
+	NumericCode int32  `json:"numeric_code"`
+	// number of minor-unit digits, 2 for cents
+	Exponent  int32     `json:"exponent"`
+	Symbol    string    `json:"symbol"`
+	Enabled   bool      `json:"enabled"`
 	CreatedAt time.Time `json:"created_at"`
-	UserID    int64     `json:"user_id"`
 }
 
 type Entry struct {
 	ID        int64 `json:"id"`
 	AccountID int64 `json:"account_id"`
 	// can be negative or positive
-	Amount    int64     `json:"amount"`
+	Amount     int64       `json:"amount"`
+	CreatedAt  time.Time   `json:"created_at"`
+	TransferID pgtype.Int8 `json:"transfer_id"`
+}
+
+type FxRate struct {
+	BaseCurrency  string `json:"base_currency"`
+	QuoteCurrency string `json:"quote_currency"`
+	// units of quote currency per one unit of base currency
+	Rate      float64   `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type IdempotencyKey struct {
+	ID             int64     `json:"id"`
+	UserID         int64     `json:"user_id"`
+	IdempotencyKey string    `json:"idempotency_key"`
+	RequestHash    string    `json:"request_hash"`
+	Response       []byte    `json:"response"`
+	CreatedAt      time.Time `json:"created_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+type LoginFailure struct {
+	ID int64 `json:"id"`
+	// failures are tracked by the email typed in, so that unknown emails behave like the known ones
+	Email     string    `json:"email"`
+	ClientIp  string    `json:"client_ip"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type LoginLockout struct {
+	ID    int64  `json:"id"`
+	Email string `json:"email"`
+	// null when the email does not belong to any user
+	UserID pgtype.Int8 `json:"user_id"`
+	// client of the attempt that triggered the lockout
+	ClientIp       string             `json:"client_ip"`
+	FailedAttempts int32              `json:"failed_attempts"`
+	LockedUntil    time.Time          `json:"locked_until"`
+	UnlockedAt     pgtype.Timestamptz `json:"unlocked_at"`
+	UnlockedBy     pgtype.Int8        `json:"unlocked_by"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+type MfaChallenge struct {
+	ID         uuid.UUID          `json:"id"`
+	UserID     int64              `json:"user_id"`
+	Attempts   int32              `json:"attempts"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	ConsumedAt pgtype.Timestamptz `json:"consumed_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type Notification struct {
+	ID         int64       `json:"id"`
+	UserID     int64       `json:"user_id"`
+	Event      string      `json:"event"`
+	TransferID pgtype.Int8 `json:"transfer_id"`
+	// account of the user the event happened to
+	AccountID             int64 `json:"account_id"`
+	CounterpartyAccountID int64 `json:"counterparty_account_id"`
+	// in the currency of account_id
+	Amount    int64              `json:"amount"`
+	Currency  string             `json:"currency"`
+	ReadAt    pgtype.Timestamptz `json:"read_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// a missing row means the default: in-app only
+type NotificationPreference struct {
+	UserID    int64     `json:"user_id"`
+	Event     string    `json:"event"`
+	InApp     bool      `json:"in_app"`
+	Email     bool      `json:"email"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Outbox struct {
+	ID       int64  `json:"id"`
+	TaskType string `json:"task_type"`
+	Payload  []byte `json:"payload"`
+	// empty for the default asynq queue
+	Queue string `json:"queue"`
+	// null for the asynq default
+	MaxRetry pgtype.Int4 `json:"max_retry"`
+	// task timeout in nanoseconds, null for the asynq default
+	Timeout   pgtype.Int8        `json:"timeout"`
+	ProcessAt pgtype.Timestamptz `json:"process_at"`
+	Attempts  int32              `json:"attempts"`
+	LastError string             `json:"last_error"`
+	// when the relay publishes the task to asynq next
+	NextAttemptAt time.Time          `json:"next_attempt_at"`
+	SentAt        pgtype.Timestamptz `json:"sent_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+type PasswordReset struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+	// sha256 of the emailed code, the code itself is never stored
+	CodeHash  string             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+	ExpiredAt time.Time          `json:"expired_at"`
+}
+
+// password reset requests, recorded before the email is sent and kept for rate limiting
+type PasswordResetRequest struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Permission struct {
+	// resource:action:scope, where scope "own" limits the action to the resources of the user and "any" does not
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type Role struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RolePermission struct {
+	Role       string `json:"role"`
+	Permission string `json:"permission"`
+}
+
 type Session struct {
 	ID           uuid.UUID `json:"id"`
 	UserID       int64     `json:"user_id"`
@@ -37,6 +176,18 @@ type Session struct {
 	IsBlocked    bool      `json:"is_blocked"`
 	ExpiresAt    time.Time `json:"expires_at"`
 	CreatedAt    time.Time `json:"created_at"`
+	// id of the login session every rotated refresh token descends from
+	FamilyID  uuid.UUID          `json:"family_id"`
+	RotatedAt pgtype.Timestamptz `json:"rotated_at"`
+}
+
+type TotpRecoveryCode struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+	// sha256 of the recovery code
+	CodeHash  string             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
 }
 
 type Transfer struct {
@@ -46,6 +197,9 @@ type Transfer struct {
 	// must be positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// amount credited in the currency of the destination account
+	ToAmount     int64   `json:"to_amount"`
+	ExchangeRate float64 `json:"exchange_rate"`
 }
 
 type User struct {
@@ -58,6 +212,17 @@ type User struct {
 	CreatedAt         time.Time   `json:"created_at"`
 	IsVerified        pgtype.Bool `json:"is_verified"`
 	Role              string      `json:"role"`
+	Locale            string      `json:"locale"`
+}
+
+type UserTotp struct {
+	UserID int64  `json:"user_id"`
+	Secret string `json:"secret"`
+	// the second factor is enforced once the user has proven to hold the secret
+	ConfirmedAt pgtype.Timestamptz `json:"confirmed_at"`
+	// time step of the last accepted code, a code is never accepted twice
+	LastUsedStep int64     `json:"last_used_step"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 type VerifyEmail struct {
@@ -69,3 +234,10 @@ type VerifyEmail struct {
 	CreatedAt time.Time `json:"created_at"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
+
+// resends of the verification email, kept for rate limiting
+type VerifyEmailRequest struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
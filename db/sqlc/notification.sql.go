@@ -0,0 +1,222 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: notification.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countUnreadNotifications = `-- name: CountUnreadNotifications :one
+SELECT count(*)
+FROM notifications
+WHERE user_id = $1
+  AND read_at IS NULL
+`
+
+func (q *Queries) CountUnreadNotifications(ctx context.Context, userID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countUnreadNotifications, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createNotification = `-- name: CreateNotification :one
+INSERT INTO notifications (user_id,
+                           event,
+                           transfer_id,
+                           account_id,
+                           counterparty_account_id,
+                           amount,
+                           currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (user_id, event, transfer_id) DO NOTHING
+RETURNING id, user_id, event, transfer_id, account_id, counterparty_account_id, amount, currency, read_at, created_at
+`
+
+type CreateNotificationParams struct {
+	UserID                int64       `json:"user_id"`
+	Event                 string      `json:"event"`
+	TransferID            pgtype.Int8 `json:"transfer_id"`
+	AccountID             int64       `json:"account_id"`
+	CounterpartyAccountID int64       `json:"counterparty_account_id"`
+	Amount                int64       `json:"amount"`
+	Currency              string      `json:"currency"`
+}
+
+// a retried task finds the notification of the transfer already there and gets no rows
+func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error) {
+	row := q.db.QueryRow(ctx, createNotification,
+		arg.UserID,
+		arg.Event,
+		arg.TransferID,
+		arg.AccountID,
+		arg.CounterpartyAccountID,
+		arg.Amount,
+		arg.Currency,
+	)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Event,
+		&i.TransferID,
+		&i.AccountID,
+		&i.CounterpartyAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.ReadAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listNotificationPreferences = `-- name: ListNotificationPreferences :many
+SELECT user_id, event, in_app, email, updated_at
+FROM notification_preferences
+WHERE user_id = $1
+ORDER BY event
+`
+
+func (q *Queries) ListNotificationPreferences(ctx context.Context, userID int64) ([]NotificationPreference, error) {
+	rows, err := q.db.Query(ctx, listNotificationPreferences, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []NotificationPreference{}
+	for rows.Next() {
+		var i NotificationPreference
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Event,
+			&i.InApp,
+			&i.Email,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotifications = `-- name: ListNotifications :many
+SELECT id, user_id, event, transfer_id, account_id, counterparty_account_id, amount, currency, read_at, created_at
+FROM notifications
+WHERE user_id = $1
+  AND (NOT $2::boolean OR read_at IS NULL)
+  AND ($3::timestamptz IS NULL
+    OR (created_at, id) < ($3::timestamptz, $4::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT $5
+`
+
+type ListNotificationsParams struct {
+	UserID          int64              `json:"user_id"`
+	UnreadOnly      bool               `json:"unread_only"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	PageLimit       int32              `json:"page_limit"`
+}
+
+func (q *Queries) ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error) {
+	rows, err := q.db.Query(ctx, listNotifications,
+		arg.UserID,
+		arg.UnreadOnly,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Notification{}
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Event,
+			&i.TransferID,
+			&i.AccountID,
+			&i.CounterpartyAccountID,
+			&i.Amount,
+			&i.Currency,
+			&i.ReadAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markNotificationsRead = `-- name: MarkNotificationsRead :execrows
+UPDATE notifications
+SET read_at = now()
+WHERE user_id = $1
+  AND read_at IS NULL
+  AND ($2::boolean OR id = ANY ($3::bigint[]))
+`
+
+type MarkNotificationsReadParams struct {
+	UserID           int64   `json:"user_id"`
+	AllNotifications bool    `json:"all_notifications"`
+	Ids              []int64 `json:"ids"`
+}
+
+func (q *Queries) MarkNotificationsRead(ctx context.Context, arg MarkNotificationsReadParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markNotificationsRead, arg.UserID, arg.AllNotifications, arg.Ids)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertNotificationPreference = `-- name: UpsertNotificationPreference :one
+INSERT INTO notification_preferences (user_id, event, in_app, email)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, event) DO UPDATE
+    SET in_app     = EXCLUDED.in_app,
+        email      = EXCLUDED.email,
+        updated_at = now()
+RETURNING user_id, event, in_app, email, updated_at
+`
+
+type UpsertNotificationPreferenceParams struct {
+	UserID int64  `json:"user_id"`
+	Event  string `json:"event"`
+	InApp  bool   `json:"in_app"`
+	Email  bool   `json:"email"`
+}
+
+func (q *Queries) UpsertNotificationPreference(ctx context.Context, arg UpsertNotificationPreferenceParams) (NotificationPreference, error) {
+	row := q.db.QueryRow(ctx, upsertNotificationPreference,
+		arg.UserID,
+		arg.Event,
+		arg.InApp,
+		arg.Email,
+	)
+	var i NotificationPreference
+	err := row.Scan(
+		&i.UserID,
+		&i.Event,
+		&i.InApp,
+		&i.Email,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,148 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: outbox.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimOutboxTasks = `-- name: ClaimOutboxTasks :many
+SELECT id, task_type, payload, queue, max_retry, timeout, process_at, attempts, last_error, next_attempt_at, sent_at, created_at
+FROM outbox
+WHERE sent_at IS NULL
+  AND next_attempt_at <= now()
+ORDER BY next_attempt_at, id
+LIMIT $1 FOR UPDATE SKIP LOCKED
+`
+
+// the rows stay locked until the relay transaction ends, other relays skip them
+func (q *Queries) ClaimOutboxTasks(ctx context.Context, limit int32) ([]Outbox, error) {
+	rows, err := q.db.Query(ctx, claimOutboxTasks, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Outbox{}
+	for rows.Next() {
+		var i Outbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskType,
+			&i.Payload,
+			&i.Queue,
+			&i.MaxRetry,
+			&i.Timeout,
+			&i.ProcessAt,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.SentAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createOutboxTask = `-- name: CreateOutboxTask :one
+INSERT INTO outbox (task_type,
+                    payload,
+                    queue,
+                    max_retry,
+                    timeout,
+                    process_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, task_type, payload, queue, max_retry, timeout, process_at, attempts, last_error, next_attempt_at, sent_at, created_at
+`
+
+type CreateOutboxTaskParams struct {
+	TaskType  string             `json:"task_type"`
+	Payload   []byte             `json:"payload"`
+	Queue     string             `json:"queue"`
+	MaxRetry  pgtype.Int4        `json:"max_retry"`
+	Timeout   pgtype.Int8        `json:"timeout"`
+	ProcessAt pgtype.Timestamptz `json:"process_at"`
+}
+
+func (q *Queries) CreateOutboxTask(ctx context.Context, arg CreateOutboxTaskParams) (Outbox, error) {
+	row := q.db.QueryRow(ctx, createOutboxTask,
+		arg.TaskType,
+		arg.Payload,
+		arg.Queue,
+		arg.MaxRetry,
+		arg.Timeout,
+		arg.ProcessAt,
+	)
+	var i Outbox
+	err := row.Scan(
+		&i.ID,
+		&i.TaskType,
+		&i.Payload,
+		&i.Queue,
+		&i.MaxRetry,
+		&i.Timeout,
+		&i.ProcessAt,
+		&i.Attempts,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.SentAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteSentOutboxTasks = `-- name: DeleteSentOutboxTasks :execrows
+DELETE
+FROM outbox
+WHERE sent_at < $1::timestamptz
+`
+
+func (q *Queries) DeleteSentOutboxTasks(ctx context.Context, sentBefore time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSentOutboxTasks, sentBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markOutboxTaskFailed = `-- name: MarkOutboxTaskFailed :exec
+UPDATE outbox
+SET attempts        = attempts + 1,
+    last_error      = $1,
+    next_attempt_at = $2
+WHERE id = $3
+`
+
+type MarkOutboxTaskFailedParams struct {
+	LastError     string    `json:"last_error"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	ID            int64     `json:"id"`
+}
+
+func (q *Queries) MarkOutboxTaskFailed(ctx context.Context, arg MarkOutboxTaskFailedParams) error {
+	_, err := q.db.Exec(ctx, markOutboxTaskFailed, arg.LastError, arg.NextAttemptAt, arg.ID)
+	return err
+}
+
+const markOutboxTaskSent = `-- name: MarkOutboxTaskSent :exec
+UPDATE outbox
+SET sent_at  = now(),
+    attempts = attempts + 1
+WHERE id = $1
+`
+
+func (q *Queries) MarkOutboxTaskSent(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, markOutboxTaskSent, id)
+	return err
+}
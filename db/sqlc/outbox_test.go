@@ -0,0 +1,108 @@
+package db
+
+import (
+	"bank/utils"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createOutboxTaskParams() CreateOutboxTaskParams {
+	return CreateOutboxTaskParams{
+		TaskType: "task:" + utils.RandomString(8),
+		Payload:  []byte(`{"user_id":1}`),
+	}
+}
+
+func TestCreateUserTxWritesOutbox(t *testing.T) {
+	ctx := context.Background()
+	hashedPassword, err := utils.HashedPassword(utils.RandomString(8))
+	require.NoError(t, err)
+
+	newUserParams := func() CreateUserParams {
+		return CreateUserParams{
+			Username:       utils.RandomName(),
+			Email:          utils.RandomEmail(),
+			HashedPassword: hashedPassword,
+			FullName:       fmt.Sprintf("%s %s", utils.RandomName(), utils.RandomName()),
+		}
+	}
+
+	var task Outbox
+	result, err := testStore.CreateUserTX(ctx, CreateUserTxParams{
+		CreateUserParams: newUserParams(),
+		AfterCreate: func(ctx context.Context, user User) error {
+			task, err = QuerierFromContext(ctx, testStore).CreateOutboxTask(ctx, createOutboxTaskParams())
+			return err
+		},
+	})
+	require.NoError(t, err)
+	require.NotZero(t, result.User.ID)
+	require.NotZero(t, task.ID)
+	require.False(t, task.SentAt.Valid)
+
+	// the task is rolled back together with the user
+	failure := errors.New("failure")
+	userParams := newUserParams()
+	_, err = testStore.CreateUserTX(ctx, CreateUserTxParams{
+		CreateUserParams: userParams,
+		AfterCreate: func(ctx context.Context, user User) error {
+			task, err = QuerierFromContext(ctx, testStore).CreateOutboxTask(ctx, createOutboxTaskParams())
+			require.NoError(t, err)
+			return failure
+		},
+	})
+	require.ErrorIs(t, err, failure)
+
+	_, err = testStore.GetUserByEmail(ctx, userParams.Email)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	claimed, err := testStore.ClaimOutboxTasks(ctx, 1000)
+	require.NoError(t, err)
+	for _, claimedTask := range claimed {
+		require.NotEqual(t, task.ID, claimedTask.ID)
+	}
+}
+
+func TestRelayOutboxTx(t *testing.T) {
+	ctx := context.Background()
+	sent, err := testStore.CreateOutboxTask(ctx, createOutboxTaskParams())
+	require.NoError(t, err)
+	failed, err := testStore.CreateOutboxTask(ctx, createOutboxTaskParams())
+	require.NoError(t, err)
+
+	published := map[int64]bool{}
+	result, err := testStore.RelayOutboxTx(ctx, RelayOutboxTxParams{
+		Limit: 1000,
+		Publish: func(task Outbox) error {
+			if task.ID == failed.ID {
+				return errors.New("redis is down")
+			}
+			published[task.ID] = true
+			return nil
+		},
+		Backoff: func(attempts int32) time.Duration {
+			require.Equal(t, int32(1), attempts)
+			return time.Hour
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, published[sent.ID])
+	require.GreaterOrEqual(t, result.Sent, 1)
+	require.Equal(t, 1, result.Failed)
+
+	// neither task is due anymore: one is sent, the other waits for the backoff
+	result, err = testStore.RelayOutboxTx(ctx, RelayOutboxTxParams{
+		Limit: 1000,
+		Publish: func(task Outbox) error {
+			require.NotContains(t, []int64{sent.ID, failed.ID}, task.ID)
+			return nil
+		},
+		Backoff: func(attempts int32) time.Duration { return time.Hour },
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Failed)
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// PageToken is a keyset cursor pointing at the last row of the previous page.
+// Lists are ordered by (created_at, id) descending, so the next page starts right below it.
+type PageToken struct {
+	CreatedAt time.Time `json:"t"`
+	ID        int64     `json:"i"`
+}
+
+// EncodePageToken returns an opaque token clients pass back to fetch the next page.
+func EncodePageToken(createdAt time.Time, id int64) string {
+	tokenJSON, _ := json.Marshal(PageToken{CreatedAt: createdAt, ID: id})
+	return base64.RawURLEncoding.EncodeToString(tokenJSON)
+}
+
+// DecodePageToken parses a token made by EncodePageToken.
+// An empty token stands for the first page and decodes to nil.
+func DecodePageToken(token string) (*PageToken, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	tokenJSON, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	var pageToken PageToken
+	if err = json.Unmarshal(tokenJSON, &pageToken); err != nil || pageToken.ID <= 0 {
+		return nil, ErrInvalidPageToken
+	}
+
+	return &pageToken, nil
+}
+
+// CursorCreatedAt is the created_at bound of the cursor, NULL for the first page.
+func (token *PageToken) CursorCreatedAt() pgtype.Timestamptz {
+	if token == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: token.CreatedAt, Valid: true}
+}
+
+// CursorID is the id bound of the cursor, NULL for the first page.
+func (token *PageToken) CursorID() pgtype.Int8 {
+	if token == nil {
+		return pgtype.Int8{}
+	}
+	return pgtype.Int8{Int64: token.ID, Valid: true}
+}
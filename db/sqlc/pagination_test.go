@@ -0,0 +1,29 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageToken(t *testing.T) {
+	createdAt := time.Now().UTC()
+	token := EncodePageToken(createdAt, 42)
+
+	pageToken, err := DecodePageToken(token)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), pageToken.ID)
+	require.True(t, createdAt.Equal(pageToken.CreatedAt))
+	require.True(t, pageToken.CursorCreatedAt().Valid)
+	require.Equal(t, int64(42), pageToken.CursorID().Int64)
+
+	pageToken, err = DecodePageToken("")
+	require.NoError(t, err)
+	require.Nil(t, pageToken)
+	require.False(t, pageToken.CursorCreatedAt().Valid)
+	require.False(t, pageToken.CursorID().Valid)
+
+	_, err = DecodePageToken("not a token")
+	require.ErrorIs(t, err, ErrInvalidPageToken)
+}
@@ -0,0 +1,124 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: password_reset.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const countPasswordResetRequests = `-- name: CountPasswordResetRequests :one
+SELECT count(*)
+FROM password_reset_requests
+WHERE user_id = $1
+  AND created_at > $2
+`
+
+type CountPasswordResetRequestsParams struct {
+	UserID int64     `json:"user_id"`
+	Since  time.Time `json:"since"`
+}
+
+func (q *Queries) CountPasswordResetRequests(ctx context.Context, arg CountPasswordResetRequestsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countPasswordResetRequests, arg.UserID, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createPasswordReset = `-- name: CreatePasswordReset :one
+INSERT INTO password_resets (user_id,
+                             code_hash,
+                             expired_at)
+VALUES ($1, $2, $3)
+RETURNING id, user_id, code_hash, used_at, created_at, expired_at
+`
+
+type CreatePasswordResetParams struct {
+	UserID    int64     `json:"user_id"`
+	CodeHash  string    `json:"code_hash"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+func (q *Queries) CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error) {
+	row := q.db.QueryRow(ctx, createPasswordReset, arg.UserID, arg.CodeHash, arg.ExpiredAt)
+	var i PasswordReset
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CodeHash,
+		&i.UsedAt,
+		&i.CreatedAt,
+		&i.ExpiredAt,
+	)
+	return i, err
+}
+
+const createPasswordResetRequest = `-- name: CreatePasswordResetRequest :exec
+INSERT INTO password_reset_requests (user_id)
+VALUES ($1)
+`
+
+func (q *Queries) CreatePasswordResetRequest(ctx context.Context, userID int64) error {
+	_, err := q.db.Exec(ctx, createPasswordResetRequest, userID)
+	return err
+}
+
+const expirePasswordResets = `-- name: ExpirePasswordResets :exec
+UPDATE password_resets
+SET expired_at = now()
+WHERE user_id = $1
+  AND used_at IS NULL
+  AND expired_at > now()
+`
+
+func (q *Queries) ExpirePasswordResets(ctx context.Context, userID int64) error {
+	_, err := q.db.Exec(ctx, expirePasswordResets, userID)
+	return err
+}
+
+const getPasswordReset = `-- name: GetPasswordReset :one
+SELECT id, user_id, code_hash, used_at, created_at, expired_at
+FROM password_resets
+WHERE id = $1
+`
+
+func (q *Queries) GetPasswordReset(ctx context.Context, id int64) (PasswordReset, error) {
+	row := q.db.QueryRow(ctx, getPasswordReset, id)
+	var i PasswordReset
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CodeHash,
+		&i.UsedAt,
+		&i.CreatedAt,
+		&i.ExpiredAt,
+	)
+	return i, err
+}
+
+const usePasswordReset = `-- name: UsePasswordReset :one
+UPDATE password_resets
+SET used_at = now()
+WHERE id = $1
+  AND used_at IS NULL
+  AND expired_at > now()
+RETURNING id, user_id, code_hash, used_at, created_at, expired_at
+`
+
+func (q *Queries) UsePasswordReset(ctx context.Context, id int64) (PasswordReset, error) {
+	row := q.db.QueryRow(ctx, usePasswordReset, id)
+	var i PasswordReset
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CodeHash,
+		&i.UsedAt,
+		&i.CreatedAt,
+		&i.ExpiredAt,
+	)
+	return i, err
+}
@@ -0,0 +1,37 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: permission.sql
+
+package db
+
+import (
+	"context"
+)
+
+const listRolePermissions = `-- name: ListRolePermissions :many
+SELECT permission
+FROM role_permissions
+WHERE role = $1
+ORDER BY permission
+`
+
+func (q *Queries) ListRolePermissions(ctx context.Context, role string) ([]string, error) {
+	rows, err := q.db.Query(ctx, listRolePermissions, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		items = append(items, permission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -6,38 +6,107 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
 	AddBalanceToAccount(ctx context.Context, arg AddBalanceToAccountParams) (Account, error)
+	// Counts the attempt; no row is returned for an expired, consumed or exhausted challenge.
+	AttemptMFAChallenge(ctx context.Context, arg AttemptMFAChallengeParams) (MfaChallenge, error)
+	BlockSessionFamily(ctx context.Context, familyID uuid.UUID) error
+	BlockUserSessions(ctx context.Context, arg BlockUserSessionsParams) (int64, error)
+	// the rows stay locked until the relay transaction ends, other relays skip them
+	ClaimOutboxTasks(ctx context.Context, limit int32) ([]Outbox, error)
+	ClearLoginFailures(ctx context.Context, email string) error
+	ConfirmUserTOTP(ctx context.Context, userID int64) (UserTotp, error)
+	ConsumeMFAChallenge(ctx context.Context, id uuid.UUID) (int64, error)
+	CountPasswordResetRequests(ctx context.Context, arg CountPasswordResetRequestsParams) (int64, error)
+	CountUnreadNotifications(ctx context.Context, userID int64) (int64, error)
+	CountVerifyEmailRequests(ctx context.Context, arg CountVerifyEmailRequestsParams) (int64, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateLoginFailure(ctx context.Context, arg CreateLoginFailureParams) error
+	CreateLoginLockout(ctx context.Context, arg CreateLoginLockoutParams) (LoginLockout, error)
+	CreateMFAChallenge(ctx context.Context, arg CreateMFAChallengeParams) (MfaChallenge, error)
+	// a retried task finds the notification of the transfer already there and gets no rows
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
+	CreateOutboxTask(ctx context.Context, arg CreateOutboxTaskParams) (Outbox, error)
+	CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error)
+	CreatePasswordResetRequest(ctx context.Context, userID int64) error
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	// a new code for the same email replaces the pending one and starts its expiry over
 	CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error)
-	DeleteAccount(ctx context.Context, id int64) error
+	CreateVerifyEmailRequest(ctx context.Context, userID int64) error
 	DeleteEntry(ctx context.Context, id int64) error
+	DeleteRecoveryCodes(ctx context.Context, userID int64) error
+	DeleteSentOutboxTasks(ctx context.Context, sentBefore time.Time) (int64, error)
 	DeleteTransfer(ctx context.Context, id int64) error
+	DeleteUserTOTP(ctx context.Context, userID int64) error
+	// A confirmed secret is never replaced, it has to be disabled first.
+	EnrolUserTOTP(ctx context.Context, arg EnrolUserTOTPParams) (UserTotp, error)
+	ExpirePasswordResets(ctx context.Context, userID int64) error
 	GetAccount(ctx context.Context, id int64) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetActiveLoginLockout(ctx context.Context, email string) (LoginLockout, error)
+	GetEntriesSumSince(ctx context.Context, arg GetEntriesSumSinceParams) (int64, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetFXRate(ctx context.Context, arg GetFXRateParams) (FxRate, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	// Counts the sessions of the user, and those opened from the client IP with the user agent.
+	GetLoginDeviceStats(ctx context.Context, arg GetLoginDeviceStatsParams) (GetLoginDeviceStatsRow, error)
+	// Failures of the email and of the client IP within the window, along with the latest of each.
+	GetLoginFailureStats(ctx context.Context, arg GetLoginFailureStatsParams) (GetLoginFailureStatsRow, error)
+	GetPasswordReset(ctx context.Context, id int64) (PasswordReset, error)
+	GetPendingMFAChallenge(ctx context.Context, id uuid.UUID) (MfaChallenge, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetSessionForUpdate(ctx context.Context, id uuid.UUID) (Session, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
 	GetUser(ctx context.Context, id int64) (User, error)
 	GetUserAccount(ctx context.Context, arg GetUserAccountParams) (Account, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserForUpdate(ctx context.Context, id int64) (User, error)
+	GetUserTOTP(ctx context.Context, userID int64) (UserTotp, error)
 	GetVerifyEmail(ctx context.Context, id int64) (VerifyEmail, error)
+	// Outgoing and incoming transfers are read by separate branches
+	// so that each one is served by its own from/to account index.
+	ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfer, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	// Rotated sessions are replaced by their successors, so only the head of every family is listed,
+	// along with the time of the login that started the family.
+	ListActiveSessions(ctx context.Context, userID int64) ([]ListActiveSessionsRow, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListLoginLockouts(ctx context.Context, arg ListLoginLockoutsParams) ([]LoginLockout, error)
+	ListNotificationPreferences(ctx context.Context, userID int64) ([]NotificationPreference, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	ListRolePermissions(ctx context.Context, role string) ([]string, error)
+	ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	MarkNotificationsRead(ctx context.Context, arg MarkNotificationsReadParams) (int64, error)
+	MarkOutboxTaskFailed(ctx context.Context, arg MarkOutboxTaskFailedParams) error
+	MarkOutboxTaskSent(ctx context.Context, id int64) error
+	RotateSession(ctx context.Context, id uuid.UUID) (Session, error)
+	UnlockUserLogin(ctx context.Context, arg UnlockUserLoginParams) (int64, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
 	UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entry, error)
+	UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) error
 	UpdateTransfer(ctx context.Context, arg UpdateTransferParams) (Transfer, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateVerifyEmails(ctx context.Context, arg UpdateVerifyEmailsParams) error
+	UpsertFXRate(ctx context.Context, arg UpsertFXRateParams) (FxRate, error)
+	UpsertNotificationPreference(ctx context.Context, arg UpsertNotificationPreferenceParams) (NotificationPreference, error)
+	UsePasswordReset(ctx context.Context, id int64) (PasswordReset, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error)
+	UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (int64, error)
+	// the email must still be the one the code was sent to
+	VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
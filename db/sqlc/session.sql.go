@@ -10,8 +10,43 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const blockSessionFamily = `-- name: BlockSessionFamily :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE family_id = $1
+`
+
+func (q *Queries) BlockSessionFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, blockSessionFamily, familyID)
+	return err
+}
+
+const blockUserSessions = `-- name: BlockUserSessions :execrows
+UPDATE sessions
+SET is_blocked = true
+WHERE user_id = $1
+  AND NOT is_blocked
+  AND ($2::uuid IS NULL OR family_id = $2)
+  AND ($3::uuid IS NULL OR family_id <> $3)
+`
+
+type BlockUserSessionsParams struct {
+	UserID         int64       `json:"user_id"`
+	FamilyID       pgtype.UUID `json:"family_id"`
+	ExceptFamilyID pgtype.UUID `json:"except_family_id"`
+}
+
+func (q *Queries) BlockUserSessions(ctx context.Context, arg BlockUserSessionsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, blockUserSessions, arg.UserID, arg.FamilyID, arg.ExceptFamilyID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions (id,
                       user_id,
@@ -19,9 +54,10 @@ INSERT INTO sessions (id,
                       user_agent,
                       client_ip,
                       is_blocked,
-                      expires_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, user_id, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at
+                      expires_at,
+                      family_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, user_id, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, rotated_at
 `
 
 type CreateSessionParams struct {
@@ -32,6 +68,7 @@ type CreateSessionParams struct {
 	ClientIp     string    `json:"client_ip"`
 	IsBlocked    bool      `json:"is_blocked"`
 	ExpiresAt    time.Time `json:"expires_at"`
+	FamilyID     uuid.UUID `json:"family_id"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
@@ -43,6 +80,7 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		arg.ClientIp,
 		arg.IsBlocked,
 		arg.ExpiresAt,
+		arg.FamilyID,
 	)
 	var i Session
 	err := row.Scan(
@@ -54,12 +92,41 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.RotatedAt,
 	)
 	return i, err
 }
 
+const getLoginDeviceStats = `-- name: GetLoginDeviceStats :one
+SELECT count(*) AS sessions,
+       count(*) FILTER (WHERE client_ip = $1
+           AND user_agent = $2) AS device_sessions
+FROM sessions
+WHERE user_id = $3
+`
+
+type GetLoginDeviceStatsParams struct {
+	ClientIp  string `json:"client_ip"`
+	UserAgent string `json:"user_agent"`
+	UserID    int64  `json:"user_id"`
+}
+
+type GetLoginDeviceStatsRow struct {
+	Sessions       int64 `json:"sessions"`
+	DeviceSessions int64 `json:"device_sessions"`
+}
+
+// Counts the sessions of the user, and those opened from the client IP with the user agent.
+func (q *Queries) GetLoginDeviceStats(ctx context.Context, arg GetLoginDeviceStatsParams) (GetLoginDeviceStatsRow, error) {
+	row := q.db.QueryRow(ctx, getLoginDeviceStats, arg.ClientIp, arg.UserAgent, arg.UserID)
+	var i GetLoginDeviceStatsRow
+	err := row.Scan(&i.Sessions, &i.DeviceSessions)
+	return i, err
+}
+
 const getSession = `-- name: GetSession :one
-SELECT id, user_id, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at
+SELECT id, user_id, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, rotated_at
 FROM sessions
 WHERE id = $1
 `
@@ -76,6 +143,117 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const getSessionForUpdate = `-- name: GetSessionForUpdate :one
+SELECT id, user_id, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, rotated_at
+FROM sessions
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetSessionForUpdate(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRow(ctx, getSessionForUpdate, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlocked,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const listActiveSessions = `-- name: ListActiveSessions :many
+SELECT s.id, s.user_id, s.refresh_token, s.user_agent, s.client_ip, s.is_blocked, s.expires_at, s.created_at, s.family_id, s.rotated_at, f.created_at AS logged_in_at
+FROM sessions s
+         JOIN sessions f ON f.id = s.family_id
+WHERE s.user_id = $1
+  AND NOT s.is_blocked
+  AND s.rotated_at IS NULL
+  AND s.expires_at > now()
+ORDER BY f.created_at DESC
+`
+
+type ListActiveSessionsRow struct {
+	ID           uuid.UUID          `json:"id"`
+	UserID       int64              `json:"user_id"`
+	RefreshToken string             `json:"refresh_token"`
+	UserAgent    string             `json:"user_agent"`
+	ClientIp     string             `json:"client_ip"`
+	IsBlocked    bool               `json:"is_blocked"`
+	ExpiresAt    time.Time          `json:"expires_at"`
+	CreatedAt    time.Time          `json:"created_at"`
+	FamilyID     uuid.UUID          `json:"family_id"`
+	RotatedAt    pgtype.Timestamptz `json:"rotated_at"`
+	LoggedInAt   time.Time          `json:"logged_in_at"`
+}
+
+// Rotated sessions are replaced by their successors, so only the head of every family is listed,
+// along with the time of the login that started the family.
+func (q *Queries) ListActiveSessions(ctx context.Context, userID int64) ([]ListActiveSessionsRow, error) {
+	rows, err := q.db.Query(ctx, listActiveSessions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListActiveSessionsRow{}
+	for rows.Next() {
+		var i ListActiveSessionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.RefreshToken,
+			&i.UserAgent,
+			&i.ClientIp,
+			&i.IsBlocked,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.FamilyID,
+			&i.RotatedAt,
+			&i.LoggedInAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rotateSession = `-- name: RotateSession :one
+UPDATE sessions
+SET rotated_at = now()
+WHERE id = $1
+RETURNING id, user_id, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, rotated_at
+`
+
+func (q *Queries) RotateSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRow(ctx, rotateSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlocked,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.RotatedAt,
 	)
 	return i, err
 }
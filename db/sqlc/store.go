@@ -4,13 +4,28 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Store interface {
 	Querier
 	TransferTx(context.Context, TransferTxParams) (TransferTxResult, error)
+	CreateAccountTx(context.Context, CreateAccountTxParams) (CreateAccountTxResult, error)
+	GetAccountStatementTx(context.Context, GetAccountStatementTxParams) (GetAccountStatementTxResult, error)
 	CreateUserTX(context.Context, CreateUserTxParams) (CreateUserTxResult, error)
+	UpdateUserTx(context.Context, UpdateUserTxParams) (UpdateUserTxResult, error)
+	UpdateAccountStatusTx(context.Context, UpdateAccountStatusTxParams) (UpdateAccountStatusTxResult, error)
+	RenewSessionTx(context.Context, RenewSessionTxParams) (RenewSessionTxResult, error)
+	CreateLoginSessionTx(context.Context, CreateLoginSessionTxParams) (CreateLoginSessionTxResult, error)
+	EnrolTOTPTx(context.Context, EnrolTOTPTxParams) (EnrolTOTPTxResult, error)
+	DisableTOTPTx(ctx context.Context, userID int64) error
+	RequestPasswordResetTx(context.Context, RequestPasswordResetTxParams) error
+	ResendVerifyEmailTx(context.Context, ResendVerifyEmailTxParams) error
+	ResetPasswordTx(context.Context, ResetPasswordTxParams) (ResetPasswordTxResult, error)
+	LockLoginTx(context.Context, LockLoginTxParams) (LockLoginTxResult, error)
+	UnlockUserLoginTx(context.Context, UnlockUserLoginTxParams) (int64, error)
+	RelayOutboxTx(context.Context, RelayOutboxTxParams) (RelayOutboxTxResult, error)
 }
 
 type DBStore struct {
@@ -26,7 +41,11 @@ func NewDBStore(db *pgxpool.Pool) Store {
 }
 
 func (store *DBStore) execTx(ctx context.Context, fn func(queries *Queries) error) error {
-	tx, err := store.connPool.Begin(ctx)
+	return store.execTxWithOptions(ctx, pgx.TxOptions{}, fn)
+}
+
+func (store *DBStore) execTxWithOptions(ctx context.Context, txOptions pgx.TxOptions, fn func(queries *Queries) error) error {
+	tx, err := store.connPool.BeginTx(ctx, txOptions)
 	if err != nil {
 		return err
 	}
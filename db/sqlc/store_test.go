@@ -1,15 +1,27 @@
 package db
 
 import (
+	"bank/utils"
 	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
-func TestCreateTransferTx(t *testing.T) {
+// createTransferAccounts creates two accounts of different users sharing the same currency.
+func createTransferAccounts(t *testing.T) (Account, Account) {
 	acc1, _ := createRandAccount(t)
-	acc2, _ := createRandAccount(t)
+	user, _ := createRandUser(t)
+	acc2, _ := createAccountForUser(t, user.ID, acc1.Currency)
+	return acc1, acc2
+}
+
+func TestCreateTransferTx(t *testing.T) {
+	acc1, acc2 := createTransferAccounts(t)
 
 	amount := int64(10)
 	cnt := 10
@@ -79,8 +91,7 @@ func TestCreateTransferTx(t *testing.T) {
 }
 
 func TestCreateTransferTxInsufficientBalance(t *testing.T) {
-	acc1, _ := createRandAccount(t)
-	acc2, _ := createRandAccount(t)
+	acc1, acc2 := createTransferAccounts(t)
 
 	amount := int64(1000)
 	cnt := 10
@@ -104,15 +115,63 @@ func TestCreateTransferTxInsufficientBalance(t *testing.T) {
 		res := <-result
 		err := <-errC
 		if err != nil { // error occurres when FromAccount reaches zero balanc
-			require.ErrorContains(t, err, "insufficient")
-			require.Equal(t, int(res.FromAccount.Balance), 0)
+			require.ErrorIs(t, err, ErrInsufficientFunds)
+			require.Empty(t, res)
 		}
 	}
+
+	account, err := testStore.GetAccount(context.Background(), acc1.ID)
+	require.NoError(t, err)
+	require.Zero(t, account.Balance)
+}
+
+func TestCreateTransferTxErrors(t *testing.T) {
+	acc1, acc2 := createTransferAccounts(t)
+	user, _ := createRandUser(t)
+	currency := utils.USD
+	if acc1.Currency == utils.USD {
+		currency = utils.EUR
+	}
+	acc3, _ := createAccountForUser(t, user.ID, currency)
+
+	testCases := []struct {
+		name string
+		arg  TransferTxParams
+		err  error
+	}{
+		{
+			name: "Same account",
+			arg:  TransferTxParams{FromAccountID: acc1.ID, ToAccountID: acc1.ID, Amount: 10},
+			err:  ErrSameAccount,
+		},
+		{
+			name: "Account not found",
+			arg:  TransferTxParams{FromAccountID: acc1.ID, ToAccountID: acc3.ID + 1000, Amount: 10},
+			err:  ErrAccountNotFound,
+		},
+		{
+			name: "Currency mismatch",
+			arg:  TransferTxParams{FromAccountID: acc1.ID, ToAccountID: acc3.ID, Amount: 10},
+			err:  ErrCurrencyMismatch,
+		},
+		{
+			name: "Insufficient funds",
+			arg:  TransferTxParams{FromAccountID: acc2.ID, ToAccountID: acc1.ID, Amount: acc2.Balance + 1},
+			err:  ErrInsufficientFunds,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := testStore.TransferTx(context.Background(), tc.arg)
+			require.ErrorIs(t, err, tc.err)
+			require.Empty(t, res)
+		})
+	}
 }
 
 func TestCreateTransferTxDeadlock(t *testing.T) {
-	acc1, _ := createRandAccount(t)
-	acc2, _ := createRandAccount(t)
+	acc1, acc2 := createTransferAccounts(t)
 
 	amount := int64(10)
 	cnt := 10
@@ -149,3 +208,554 @@ func TestCreateTransferTxDeadlock(t *testing.T) {
 	require.Equal(t, fromAccount.Balance, acc1.Balance)
 	require.Equal(t, toAccount.Balance, acc2.Balance)
 }
+
+func TestTransferTxIdempotency(t *testing.T) {
+	acc1, acc2 := createTransferAccounts(t)
+
+	idempotency := &IdempotencyParams{
+		UserID:    acc1.UserID,
+		Key:       utils.RandomString(16),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	arg := TransferTxParams{
+		FromAccountID: acc1.ID,
+		ToAccountID:   acc2.ID,
+		Amount:        10,
+		Idempotency:   idempotency,
+	}
+
+	res1, err := testStore.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	res2, err := testStore.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, res1.Transfer.ID, res2.Transfer.ID)
+	require.Equal(t, res1.FromAccount.Balance, res2.FromAccount.Balance)
+
+	account, err := testStore.GetAccount(context.Background(), acc1.ID)
+	require.NoError(t, err)
+	require.Equal(t, acc1.Balance-10, account.Balance)
+
+	arg.Amount = 20
+	_, err = testStore.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrIdempotencyKeyReused)
+}
+
+func TestTransferTxBeforeTransfer(t *testing.T) {
+	acc1, acc2 := createTransferAccounts(t)
+
+	hookErr := errors.New("second factor rejected")
+	calls := 0
+	arg := TransferTxParams{
+		FromAccountID: acc1.ID,
+		ToAccountID:   acc2.ID,
+		Amount:        10,
+		Idempotency: &IdempotencyParams{
+			UserID:    acc1.UserID,
+			Key:       utils.RandomString(16),
+			ExpiresAt: time.Now().Add(time.Hour),
+		},
+		BeforeTransfer: func(ctx context.Context) error {
+			calls++
+			return hookErr
+		},
+	}
+
+	_, err := testStore.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, hookErr)
+
+	account, err := testStore.GetAccount(context.Background(), acc1.ID)
+	require.NoError(t, err)
+	require.Equal(t, acc1.Balance, account.Balance)
+
+	// the failed attempt rolled back its idempotency key, so the retry runs the hook again
+	hookErr = nil
+	res1, err := testStore.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+
+	// a replay returns the stored result without asking the hook
+	res2, err := testStore.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, res1.Transfer.ID, res2.Transfer.ID)
+	require.Equal(t, 2, calls)
+}
+
+func TestTransferTxAfterTransfer(t *testing.T) {
+	acc1, acc2 := createTransferAccounts(t)
+
+	hookErr := errors.New("outbox is down")
+	arg := TransferTxParams{
+		FromAccountID: acc1.ID,
+		ToAccountID:   acc2.ID,
+		Amount:        10,
+		AfterTransfer: func(ctx context.Context, transfer Transfer) error {
+			return hookErr
+		},
+	}
+
+	// the failing hook takes the transfer down with it
+	_, err := testStore.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, hookErr)
+
+	account, err := testStore.GetAccount(context.Background(), acc1.ID)
+	require.NoError(t, err)
+	require.Equal(t, acc1.Balance, account.Balance)
+
+	var seen Transfer
+	arg.AfterTransfer = func(ctx context.Context, transfer Transfer) error {
+		seen = transfer
+		// the transaction isn't committed yet, so the transfer is visible through the queries of ctx only
+		_, err := QuerierFromContext(ctx, testStore).GetTransfer(ctx, transfer.ID)
+		return err
+	}
+	result, err := testStore.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, result.Transfer.ID, seen.ID)
+}
+
+func TestGetAccountStatementTx(t *testing.T) {
+	acc1, acc2 := createTransferAccounts(t)
+	from := time.Now().Add(-time.Minute)
+
+	amounts := []int64{10, 20, 30}
+	for _, amount := range amounts {
+		_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: acc1.ID,
+			ToAccountID:   acc2.ID,
+			Amount:        amount,
+		})
+		require.NoError(t, err)
+	}
+
+	statement, err := testStore.GetAccountStatementTx(context.Background(), GetAccountStatementTxParams{
+		AccountID: acc1.ID,
+		From:      from,
+		To:        time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Equal(t, acc1.Balance, statement.OpeningBalance)
+	require.Equal(t, acc1.Balance-60, statement.ClosingBalance)
+	require.Len(t, statement.Lines, len(amounts))
+
+	balance := acc1.Balance
+	for i, line := range statement.Lines {
+		balance -= amounts[i]
+		require.Equal(t, -amounts[i], line.Amount)
+		require.Equal(t, balance, line.Balance)
+		require.True(t, line.TransferID.Valid)
+		require.Equal(t, acc2.ID, line.CounterpartyAccountID)
+	}
+
+	_, err = testStore.GetAccountStatementTx(context.Background(), GetAccountStatementTxParams{
+		AccountID: acc2.ID + 1000,
+		From:      from,
+		To:        time.Now(),
+	})
+	require.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func TestTransferTxCrossCurrency(t *testing.T) {
+	acc1, _ := createRandAccount(t)
+	user, _ := createRandUser(t)
+	currency := utils.USD
+	if acc1.Currency == utils.USD {
+		currency = utils.EUR
+	}
+	acc2, _ := createAccountForUser(t, user.ID, currency)
+
+	res, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: acc1.ID,
+		ToAccountID:   acc2.ID,
+		Amount:        10,
+		ExchangeRate:  1.5,
+		Exponents:     map[string]int32{acc1.Currency: 2, acc2.Currency: 2},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(10), res.Transfer.Amount)
+	require.Equal(t, int64(15), res.Transfer.ToAmount)
+	require.Equal(t, 1.5, res.Transfer.ExchangeRate)
+	require.Equal(t, int64(-10), res.FromEntry.Amount)
+	require.Equal(t, int64(15), res.ToEntry.Amount)
+	require.Equal(t, acc1.Balance-10, res.FromAccount.Balance)
+	require.Equal(t, acc2.Balance+15, res.ToAccount.Balance)
+
+	// without the exponents the converted amount could be off by powers of ten
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: acc1.ID,
+		ToAccountID:   acc2.ID,
+		Amount:        10,
+		ExchangeRate:  1.5,
+	})
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestUpdateAccountStatusTx(t *testing.T) {
+	acc1, acc2 := createTransferAccounts(t)
+
+	res, err := testStore.UpdateAccountStatusTx(context.Background(), UpdateAccountStatusTxParams{
+		AccountID: acc1.ID,
+		Status:    AccountStatusFrozen,
+		Reason:    "suspicious activity",
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusFrozen, res.Account.Status)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{FromAccountID: acc1.ID, ToAccountID: acc2.ID, Amount: 1})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{FromAccountID: acc2.ID, ToAccountID: acc1.ID, Amount: 1})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	closeParams := UpdateAccountStatusTxParams{
+		AccountID: acc1.ID,
+		Status:    AccountStatusClosed,
+		Reason:    "customer request",
+	}
+	_, err = testStore.UpdateAccount(context.Background(), UpdateAccountParams{ID: acc1.ID, Balance: 1})
+	require.NoError(t, err)
+	_, err = testStore.UpdateAccountStatusTx(context.Background(), closeParams)
+	require.ErrorIs(t, err, ErrAccountBalanceNotZero)
+
+	_, err = testStore.UpdateAccount(context.Background(), UpdateAccountParams{ID: acc1.ID, Balance: 0})
+	require.NoError(t, err)
+	res, err = testStore.UpdateAccountStatusTx(context.Background(), closeParams)
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusClosed, res.Account.Status)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{FromAccountID: acc2.ID, ToAccountID: acc1.ID, Amount: 1})
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	_, err = testStore.UpdateAccountStatusTx(context.Background(), UpdateAccountStatusTxParams{
+		AccountID: acc1.ID,
+		Status:    AccountStatusActive,
+		Reason:    "reopen",
+	})
+	require.ErrorIs(t, err, ErrInvalidStatusTransition)
+
+	// closed accounts stay readable for history
+	account, err := testStore.GetAccount(context.Background(), acc1.ID)
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusClosed, account.Status)
+}
+
+func createRandSession(t *testing.T, user User, familyID uuid.UUID) Session {
+	id := uuid.New()
+	if familyID == uuid.Nil {
+		familyID = id
+	}
+
+	session, err := testStore.CreateSession(context.Background(), CreateSessionParams{
+		ID:           id,
+		UserID:       user.ID,
+		RefreshToken: utils.RandomString(32),
+		UserAgent:    "test",
+		ClientIp:     "127.0.0.1",
+		ExpiresAt:    time.Now().Add(time.Hour),
+		FamilyID:     familyID,
+	})
+	require.NoError(t, err)
+	return session
+}
+
+func TestCreateLoginSessionTx(t *testing.T) {
+	user, _ := createRandUser(t)
+
+	var notified []uuid.UUID
+	login := func(clientIP, userAgent string, afterNewDevice func(ctx context.Context, session Session) error) (CreateLoginSessionTxResult, error) {
+		id := uuid.New()
+		return testStore.CreateLoginSessionTx(context.Background(), CreateLoginSessionTxParams{
+			CreateSessionParams: CreateSessionParams{
+				ID:           id,
+				UserID:       user.ID,
+				RefreshToken: utils.RandomString(32),
+				UserAgent:    userAgent,
+				ClientIp:     clientIP,
+				ExpiresAt:    time.Now().Add(time.Hour),
+				FamilyID:     id,
+			},
+			AfterNewDevice: afterNewDevice,
+		})
+	}
+	notify := func(ctx context.Context, session Session) error {
+		notified = append(notified, session.ID)
+		return nil
+	}
+
+	// nothing to compare the first login with
+	result, err := login("10.0.0.1", "curl", notify)
+	require.NoError(t, err)
+	require.False(t, result.NewDevice)
+
+	result, err = login("10.0.0.1", "curl", notify)
+	require.NoError(t, err)
+	require.False(t, result.NewDevice)
+
+	result, err = login("10.0.0.2", "curl", notify)
+	require.NoError(t, err)
+	require.True(t, result.NewDevice)
+	require.Equal(t, []uuid.UUID{result.Session.ID}, notified)
+
+	// the session is rolled back along with the notification
+	errOutboxDown := errors.New("outbox is down")
+	_, err = login("10.0.0.3", "curl", func(ctx context.Context, session Session) error {
+		return errOutboxDown
+	})
+	require.ErrorIs(t, err, errOutboxDown)
+
+	stats, err := testStore.GetLoginDeviceStats(context.Background(), GetLoginDeviceStatsParams{
+		UserID:    user.ID,
+		ClientIp:  "10.0.0.3",
+		UserAgent: "curl",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), stats.Sessions)
+	require.Zero(t, stats.DeviceSessions)
+}
+
+func TestRenewSessionTx(t *testing.T) {
+	user, _ := createRandUser(t)
+	session := createRandSession(t, user, uuid.Nil)
+
+	renew := func(old Session) (RenewSessionTxResult, error) {
+		return testStore.RenewSessionTx(context.Background(), RenewSessionTxParams{
+			SessionID:    old.ID,
+			UserID:       user.ID,
+			RefreshToken: old.RefreshToken,
+			NewSession: CreateSessionParams{
+				ID:           uuid.New(),
+				UserID:       user.ID,
+				RefreshToken: utils.RandomString(32),
+				UserAgent:    "test",
+				ClientIp:     "127.0.0.1",
+				ExpiresAt:    time.Now().Add(time.Hour),
+			},
+		})
+	}
+
+	res, err := renew(session)
+	require.NoError(t, err)
+	require.Equal(t, session.ID, res.Session.FamilyID)
+	require.False(t, res.Session.IsBlocked)
+
+	// the spent token is presented again: the whole chain gets blocked
+	_, err = renew(session)
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	latest, err := testStore.GetSession(context.Background(), res.Session.ID)
+	require.NoError(t, err)
+	require.True(t, latest.IsBlocked)
+
+	_, err = renew(latest)
+	require.ErrorIs(t, err, ErrSessionBlocked)
+}
+
+func TestBlockUserSessions(t *testing.T) {
+	user, _ := createRandUser(t)
+	session1 := createRandSession(t, user, uuid.Nil)
+	session2 := createRandSession(t, user, uuid.Nil)
+	session3 := createRandSession(t, user, uuid.Nil)
+
+	sessions, err := testStore.ListActiveSessions(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 3)
+
+	blocked, err := testStore.BlockUserSessions(context.Background(), BlockUserSessionsParams{
+		UserID:   user.ID,
+		FamilyID: pgtype.UUID{Bytes: session1.FamilyID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), blocked)
+
+	blocked, err = testStore.BlockUserSessions(context.Background(), BlockUserSessionsParams{
+		UserID:         user.ID,
+		ExceptFamilyID: pgtype.UUID{Bytes: session3.FamilyID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), blocked)
+
+	sessions, err = testStore.ListActiveSessions(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, session3.ID, sessions[0].ID)
+
+	_, err = testStore.RenewSessionTx(context.Background(), RenewSessionTxParams{
+		SessionID:    session2.ID,
+		UserID:       user.ID,
+		RefreshToken: session2.RefreshToken,
+	})
+	require.ErrorIs(t, err, ErrSessionBlocked)
+}
+
+func TestResetPasswordTx(t *testing.T) {
+	user, _ := createRandUser(t)
+	createRandSession(t, user, uuid.Nil)
+
+	create := func() PasswordReset {
+		passwordReset, err := testStore.CreatePasswordReset(context.Background(), CreatePasswordResetParams{
+			UserID:    user.ID,
+			CodeHash:  utils.HashCode(utils.RandomString(32)),
+			ExpiredAt: time.Now().Add(time.Minute),
+		})
+		require.NoError(t, err)
+		return passwordReset
+	}
+	passwordReset := create()
+	other := create()
+
+	hashedPassword, err := utils.HashedPassword(utils.RandomString(8))
+	require.NoError(t, err)
+
+	result, err := testStore.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+		PasswordResetID: passwordReset.ID,
+		HashedPassword:  hashedPassword,
+	})
+	require.NoError(t, err)
+	require.Equal(t, hashedPassword, result.User.HashedPassword)
+	require.True(t, result.User.PasswordChangedAt.After(user.PasswordChangedAt))
+
+	sessions, err := testStore.ListActiveSessions(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+
+	// the code is single-use and the other pending resets are expired
+	for _, id := range []int64{passwordReset.ID, other.ID} {
+		_, err = testStore.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+			PasswordResetID: id,
+			HashedPassword:  hashedPassword,
+		})
+		require.ErrorIs(t, err, ErrPasswordResetInvalid)
+	}
+}
+
+func TestRequestPasswordResetTx(t *testing.T) {
+	user, _ := createRandUser(t)
+
+	arg := RequestPasswordResetTxParams{
+		UserID:       user.ID,
+		Limit:        2,
+		Since:        time.Now().Add(-time.Minute),
+		AfterRequest: func(ctx context.Context) error { return nil },
+	}
+
+	// concurrent requests wait for each other on the user row, so no more than Limit get through
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			errs <- testStore.RequestPasswordResetTx(context.Background(), arg)
+		}()
+	}
+
+	passed := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			passed++
+			continue
+		}
+		require.ErrorIs(t, err, ErrTooManyRequests)
+	}
+	require.Equal(t, int(arg.Limit), passed)
+
+	requested, err := testStore.CountPasswordResetRequests(context.Background(), CountPasswordResetRequestsParams{
+		UserID: user.ID,
+		Since:  arg.Since,
+	})
+	require.NoError(t, err)
+	require.Equal(t, arg.Limit, requested)
+}
+
+func TestResendVerifyEmailTx(t *testing.T) {
+	user, _ := createRandUser(t)
+
+	arg := ResendVerifyEmailTxParams{
+		UserID:       user.ID,
+		Limit:        2,
+		Since:        time.Now().Add(-time.Minute),
+		AfterRequest: func(ctx context.Context) error { return nil },
+	}
+
+	// concurrent requests wait for each other on the user row, so no more than Limit get through
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			errs <- testStore.ResendVerifyEmailTx(context.Background(), arg)
+		}()
+	}
+
+	passed := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			passed++
+			continue
+		}
+		require.ErrorIs(t, err, ErrTooManyRequests)
+	}
+	require.Equal(t, int(arg.Limit), passed)
+
+	requested, err := testStore.CountVerifyEmailRequests(context.Background(), CountVerifyEmailRequestsParams{
+		UserID: user.ID,
+		Since:  arg.Since,
+	})
+	require.NoError(t, err)
+	require.Equal(t, arg.Limit, requested)
+}
+
+func TestLoginLockoutTx(t *testing.T) {
+	user, _ := createRandUser(t)
+	banker, _ := createRandUser(t)
+	clientIP := "10.0.0.1"
+
+	for i := 0; i < 3; i++ {
+		err := testStore.CreateLoginFailure(context.Background(), CreateLoginFailureParams{Email: user.Email, ClientIp: clientIP})
+		require.NoError(t, err)
+	}
+
+	statsArg := GetLoginFailureStatsParams{Email: user.Email, ClientIp: clientIP, Since: time.Now().Add(-time.Minute)}
+	stats, err := testStore.GetLoginFailureStats(context.Background(), statsArg)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), stats.EmailFailures)
+	require.GreaterOrEqual(t, stats.IpFailures, int64(3))
+	require.WithinDuration(t, time.Now(), stats.EmailLastFailure, time.Second)
+
+	result, err := testStore.LockLoginTx(context.Background(), LockLoginTxParams{CreateLoginLockoutParams{
+		Email:          user.Email,
+		UserID:         pgtype.Int8{Int64: user.ID, Valid: true},
+		ClientIp:       clientIP,
+		FailedAttempts: int32(stats.EmailFailures),
+		LockedUntil:    time.Now().Add(time.Minute),
+	}})
+	require.NoError(t, err)
+	require.Equal(t, user.Email, result.Lockout.Email)
+
+	// the failures start over after a lockout
+	stats, err = testStore.GetLoginFailureStats(context.Background(), statsArg)
+	require.NoError(t, err)
+	require.Zero(t, stats.EmailFailures)
+
+	lockout, err := testStore.GetActiveLoginLockout(context.Background(), user.Email)
+	require.NoError(t, err)
+	require.Equal(t, result.Lockout.ID, lockout.ID)
+
+	unlocked, err := testStore.UnlockUserLoginTx(context.Background(), UnlockUserLoginTxParams{
+		UserID:     user.ID,
+		Email:      user.Email,
+		UnlockedBy: banker.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), unlocked)
+
+	_, err = testStore.GetActiveLoginLockout(context.Background(), user.Email)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	lockouts, err := testStore.ListLoginLockouts(context.Background(), ListLoginLockoutsParams{
+		UserID:    pgtype.Int8{Int64: user.ID, Valid: true},
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, lockouts, 1)
+	require.Equal(t, banker.ID, lockouts[0].UnlockedBy.Int64)
+}
@@ -0,0 +1,254 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: totp.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const attemptMFAChallenge = `-- name: AttemptMFAChallenge :one
+UPDATE mfa_challenges
+SET attempts = attempts + 1
+WHERE id = $1
+  AND consumed_at IS NULL
+  AND expires_at > now()
+  AND attempts < $2::int
+RETURNING id, user_id, attempts, expires_at, consumed_at, created_at
+`
+
+type AttemptMFAChallengeParams struct {
+	ID          uuid.UUID `json:"id"`
+	MaxAttempts int32     `json:"max_attempts"`
+}
+
+// Counts the attempt; no row is returned for an expired, consumed or exhausted challenge.
+func (q *Queries) AttemptMFAChallenge(ctx context.Context, arg AttemptMFAChallengeParams) (MfaChallenge, error) {
+	row := q.db.QueryRow(ctx, attemptMFAChallenge, arg.ID, arg.MaxAttempts)
+	var i MfaChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Attempts,
+		&i.ExpiresAt,
+		&i.ConsumedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const confirmUserTOTP = `-- name: ConfirmUserTOTP :one
+UPDATE user_totp
+SET confirmed_at = now()
+WHERE user_id = $1
+  AND confirmed_at IS NULL
+RETURNING user_id, secret, confirmed_at, last_used_step, created_at
+`
+
+func (q *Queries) ConfirmUserTOTP(ctx context.Context, userID int64) (UserTotp, error) {
+	row := q.db.QueryRow(ctx, confirmUserTOTP, userID)
+	var i UserTotp
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const consumeMFAChallenge = `-- name: ConsumeMFAChallenge :execrows
+UPDATE mfa_challenges
+SET consumed_at = now()
+WHERE id = $1
+  AND consumed_at IS NULL
+`
+
+func (q *Queries) ConsumeMFAChallenge(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, consumeMFAChallenge, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createMFAChallenge = `-- name: CreateMFAChallenge :one
+INSERT INTO mfa_challenges (id, user_id, expires_at)
+VALUES ($1, $2, $3)
+RETURNING id, user_id, attempts, expires_at, consumed_at, created_at
+`
+
+type CreateMFAChallengeParams struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    int64     `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateMFAChallenge(ctx context.Context, arg CreateMFAChallengeParams) (MfaChallenge, error) {
+	row := q.db.QueryRow(ctx, createMFAChallenge, arg.ID, arg.UserID, arg.ExpiresAt)
+	var i MfaChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Attempts,
+		&i.ExpiresAt,
+		&i.ConsumedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createRecoveryCode = `-- name: CreateRecoveryCode :exec
+INSERT INTO totp_recovery_codes (user_id, code_hash)
+VALUES ($1, $2)
+`
+
+type CreateRecoveryCodeParams struct {
+	UserID   int64  `json:"user_id"`
+	CodeHash string `json:"code_hash"`
+}
+
+func (q *Queries) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error {
+	_, err := q.db.Exec(ctx, createRecoveryCode, arg.UserID, arg.CodeHash)
+	return err
+}
+
+const deleteRecoveryCodes = `-- name: DeleteRecoveryCodes :exec
+DELETE
+FROM totp_recovery_codes
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteRecoveryCodes(ctx context.Context, userID int64) error {
+	_, err := q.db.Exec(ctx, deleteRecoveryCodes, userID)
+	return err
+}
+
+const deleteUserTOTP = `-- name: DeleteUserTOTP :exec
+DELETE
+FROM user_totp
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserTOTP(ctx context.Context, userID int64) error {
+	_, err := q.db.Exec(ctx, deleteUserTOTP, userID)
+	return err
+}
+
+const enrolUserTOTP = `-- name: EnrolUserTOTP :one
+INSERT INTO user_totp (user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+    SET secret         = EXCLUDED.secret,
+        last_used_step = 0,
+        created_at     = now()
+WHERE user_totp.confirmed_at IS NULL
+RETURNING user_id, secret, confirmed_at, last_used_step, created_at
+`
+
+type EnrolUserTOTPParams struct {
+	UserID int64  `json:"user_id"`
+	Secret string `json:"secret"`
+}
+
+// A confirmed secret is never replaced, it has to be disabled first.
+func (q *Queries) EnrolUserTOTP(ctx context.Context, arg EnrolUserTOTPParams) (UserTotp, error) {
+	row := q.db.QueryRow(ctx, enrolUserTOTP, arg.UserID, arg.Secret)
+	var i UserTotp
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPendingMFAChallenge = `-- name: GetPendingMFAChallenge :one
+SELECT id, user_id, attempts, expires_at, consumed_at, created_at
+FROM mfa_challenges
+WHERE id = $1
+  AND consumed_at IS NULL
+  AND expires_at > now()
+`
+
+func (q *Queries) GetPendingMFAChallenge(ctx context.Context, id uuid.UUID) (MfaChallenge, error) {
+	row := q.db.QueryRow(ctx, getPendingMFAChallenge, id)
+	var i MfaChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Attempts,
+		&i.ExpiresAt,
+		&i.ConsumedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserTOTP = `-- name: GetUserTOTP :one
+SELECT user_id, secret, confirmed_at, last_used_step, created_at
+FROM user_totp
+WHERE user_id = $1
+`
+
+func (q *Queries) GetUserTOTP(ctx context.Context, userID int64) (UserTotp, error) {
+	row := q.db.QueryRow(ctx, getUserTOTP, userID)
+	var i UserTotp
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useRecoveryCode = `-- name: UseRecoveryCode :execrows
+UPDATE totp_recovery_codes
+SET used_at = now()
+WHERE user_id = $1
+  AND code_hash = $2
+  AND used_at IS NULL
+`
+
+type UseRecoveryCodeParams struct {
+	UserID   int64  `json:"user_id"`
+	CodeHash string `json:"code_hash"`
+}
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useRecoveryCode, arg.UserID, arg.CodeHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const useTOTPStep = `-- name: UseTOTPStep :execrows
+UPDATE user_totp
+SET last_used_step = $1
+WHERE user_id = $2
+  AND last_used_step < $1
+`
+
+type UseTOTPStepParams struct {
+	Step   int64 `json:"step"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useTOTPStep, arg.Step, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -7,24 +7,36 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (from_account_id,
                        to_account_id,
-                       amount)
-VALUES ($1, $2, $3)
-RETURNING id, from_account_id, to_account_id, amount, created_at
+                       amount,
+                       to_amount,
+                       exchange_rate)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64   `json:"from_account_id"`
+	ToAccountID   int64   `json:"to_account_id"`
+	Amount        int64   `json:"amount"`
+	ToAmount      int64   `json:"to_amount"`
+	ExchangeRate  float64 `json:"exchange_rate"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRow(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ToAmount,
+		arg.ExchangeRate,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -32,6 +44,8 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
 	)
 	return i, err
 }
@@ -48,7 +62,7 @@ func (q *Queries) DeleteTransfer(ctx context.Context, id int64) error {
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate
 FROM transfers
 WHERE id = $1
 `
@@ -62,24 +76,126 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
 	)
 	return i, err
 }
 
+const listAccountTransfers = `-- name: ListAccountTransfers :many
+SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, t.to_amount, t.exchange_rate
+FROM (SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate
+      FROM transfers
+      WHERE transfers.from_account_id = $1
+        AND $2::boolean
+        AND ($3::bigint IS NULL
+          OR transfers.to_account_id = $3)
+      UNION ALL
+      SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate
+      FROM transfers
+      WHERE transfers.to_account_id = $1
+        AND $4::boolean
+        AND ($3::bigint IS NULL
+          OR transfers.from_account_id = $3)) AS t
+WHERE ($5::bigint IS NULL OR t.amount >= $5)
+  AND ($6::bigint IS NULL OR t.amount <= $6)
+  AND ($7::timestamptz IS NULL OR t.created_at >= $7)
+  AND ($8::timestamptz IS NULL OR t.created_at < $8)
+  AND ($9::timestamptz IS NULL
+    OR (t.created_at, t.id) < ($9::timestamptz, $10::bigint))
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT $11
+`
+
+type ListAccountTransfersParams struct {
+	AccountID             int64              `json:"account_id"`
+	Outgoing              bool               `json:"outgoing"`
+	CounterpartyAccountID pgtype.Int8        `json:"counterparty_account_id"`
+	Incoming              bool               `json:"incoming"`
+	MinAmount             pgtype.Int8        `json:"min_amount"`
+	MaxAmount             pgtype.Int8        `json:"max_amount"`
+	FromTime              pgtype.Timestamptz `json:"from_time"`
+	ToTime                pgtype.Timestamptz `json:"to_time"`
+	CursorCreatedAt       pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID              pgtype.Int8        `json:"cursor_id"`
+	PageLimit             int32              `json:"page_limit"`
+}
+
+// Outgoing and incoming transfers are read by separate branches
+// so that each one is served by its own from/to account index.
+func (q *Queries) ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfer, error) {
+	rows, err := q.db.Query(ctx, listAccountTransfers,
+		arg.AccountID,
+		arg.Outgoing,
+		arg.CounterpartyAccountID,
+		arg.Incoming,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.FromTime,
+		arg.ToTime,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate
 FROM transfers
-ORDER BY id DESC
-LIMIT $1 OFFSET $2
+WHERE ($1::bigint IS NULL OR amount >= $1)
+  AND ($2::bigint IS NULL OR amount <= $2)
+  AND ($3::timestamptz IS NULL OR created_at >= $3)
+  AND ($4::timestamptz IS NULL OR created_at < $4)
+  AND ($5::timestamptz IS NULL
+    OR (created_at, id) < ($5::timestamptz, $6::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT $7
 `
 
 type ListTransfersParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	MinAmount       pgtype.Int8        `json:"min_amount"`
+	MaxAmount       pgtype.Int8        `json:"max_amount"`
+	FromTime        pgtype.Timestamptz `json:"from_time"`
+	ToTime          pgtype.Timestamptz `json:"to_time"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	PageLimit       int32              `json:"page_limit"`
 }
 
 func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
-	rows, err := q.db.Query(ctx, listTransfers, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listTransfers,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.FromTime,
+		arg.ToTime,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -93,6 +209,8 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
 		); err != nil {
 			return nil, err
 		}
@@ -108,7 +226,7 @@ const updateTransfer = `-- name: UpdateTransfer :one
 UPDATE transfers
 SET amount = $2
 WHERE id = $1
-RETURNING id, from_account_id, to_account_id, amount, created_at
+RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate
 `
 
 type UpdateTransferParams struct {
@@ -125,6 +243,8 @@ func (q *Queries) UpdateTransfer(ctx context.Context, arg UpdateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
 	)
 	return i, err
 }
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAccountTransfers(t *testing.T) {
+	acc1, acc2 := createTransferAccounts(t)
+
+	amounts := []int64{10, 20, 30}
+	for _, amount := range amounts {
+		_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: acc1.ID,
+			ToAccountID:   acc2.ID,
+			Amount:        amount,
+		})
+		require.NoError(t, err)
+	}
+	_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: acc2.ID,
+		ToAccountID:   acc1.ID,
+		Amount:        5,
+	})
+	require.NoError(t, err)
+
+	all, err := testStore.ListAccountTransfers(context.Background(), ListAccountTransfersParams{
+		AccountID: acc1.ID,
+		Outgoing:  true,
+		Incoming:  true,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+	require.Equal(t, acc2.ID, all[0].FromAccountID)
+
+	outgoing, err := testStore.ListAccountTransfers(context.Background(), ListAccountTransfersParams{
+		AccountID:             acc1.ID,
+		Outgoing:              true,
+		CounterpartyAccountID: pgtype.Int8{Int64: acc2.ID, Valid: true},
+		MinAmount:             pgtype.Int8{Int64: 15, Valid: true},
+		MaxAmount:             pgtype.Int8{Int64: 30, Valid: true},
+		PageLimit:             10,
+	})
+	require.NoError(t, err)
+	require.Len(t, outgoing, 2)
+	for _, transfer := range outgoing {
+		require.Equal(t, acc1.ID, transfer.FromAccountID)
+		require.GreaterOrEqual(t, transfer.Amount, int64(15))
+	}
+
+	last := outgoing[0]
+	rest, err := testStore.ListAccountTransfers(context.Background(), ListAccountTransfersParams{
+		AccountID:       acc1.ID,
+		Outgoing:        true,
+		CursorCreatedAt: pgtype.Timestamptz{Time: last.CreatedAt, Valid: true},
+		CursorID:        pgtype.Int8{Int64: last.ID, Valid: true},
+		PageLimit:       10,
+	})
+	require.NoError(t, err)
+	require.Len(t, rest, 2)
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type GetAccountStatementTxParams struct {
+	AccountID int64     `json:"account_id"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+}
+
+type StatementLine struct {
+	ListStatementEntriesRow
+	// Balance is the account balance right after the entry
+	Balance int64 `json:"balance"`
+}
+
+type GetAccountStatementTxResult struct {
+	Account        Account         `json:"account"`
+	OpeningBalance int64           `json:"opening_balance"`
+	Lines          []StatementLine `json:"lines"`
+	ClosingBalance int64           `json:"closing_balance"`
+}
+
+// GetAccountStatementTx returns the account entries within [From, To) with a running balance.
+// The opening balance is derived from the current balance minus everything booked since From,
+// all read from a single snapshot so that concurrent transfers don't skew the totals.
+func (store *DBStore) GetAccountStatementTx(ctx context.Context, arg GetAccountStatementTxParams) (GetAccountStatementTxResult, error) {
+	var result GetAccountStatementTxResult
+
+	txOptions := pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}
+	err := store.execTxWithOptions(ctx, txOptions, func(queries *Queries) error {
+		var err error
+		result.Account, err = queries.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrAccountNotFound
+			}
+			return err
+		}
+
+		sinceFrom, err := queries.GetEntriesSumSince(ctx, GetEntriesSumSinceParams{
+			AccountID: arg.AccountID,
+			Since:     arg.From,
+		})
+		if err != nil {
+			return err
+		}
+
+		entries, err := queries.ListStatementEntries(ctx, ListStatementEntriesParams{
+			AccountID: arg.AccountID,
+			FromTime:  arg.From,
+			ToTime:    arg.To,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.OpeningBalance = result.Account.Balance - sinceFrom
+		result.ClosingBalance = result.OpeningBalance
+		result.Lines = make([]StatementLine, 0, len(entries))
+		for _, entry := range entries {
+			result.ClosingBalance += entry.Amount
+			result.Lines = append(result.Lines, StatementLine{
+				ListStatementEntriesRow: entry,
+				Balance:                 result.ClosingBalance,
+			})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return GetAccountStatementTxResult{}, err
+	}
+
+	return result, nil
+}
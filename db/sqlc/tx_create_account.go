@@ -0,0 +1,24 @@
+package db
+
+import "context"
+
+type CreateAccountTxParams struct {
+	CreateAccountParams
+	Idempotency *IdempotencyParams `json:"-"`
+}
+
+type CreateAccountTxResult struct {
+	Account Account `json:"account"`
+}
+
+func (store *DBStore) CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (CreateAccountTxResult, error) {
+	var result CreateAccountTxResult
+
+	err := store.execIdempotentTx(ctx, arg.Idempotency, "create_account", arg, &result, func(queries *Queries) error {
+		var err error
+		result.Account, err = queries.CreateAccount(ctx, arg.CreateAccountParams)
+		return err
+	})
+
+	return result, err
+}
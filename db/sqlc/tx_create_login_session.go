@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+)
+
+type CreateLoginSessionTxParams struct {
+	CreateSessionParams
+	// AfterNewDevice runs inside the transaction when the user already had sessions, none of them
+	// from the client IP with the user agent. Its ctx carries the transaction, see QuerierFromContext.
+	AfterNewDevice func(ctx context.Context, session Session) error
+}
+
+type CreateLoginSessionTxResult struct {
+	Session   Session `json:"session"`
+	NewDevice bool    `json:"new_device"`
+}
+
+// CreateLoginSessionTx starts the session of a login and tells whether the login came from a new device.
+// The first login of the user is not from a new device, there is nothing to compare it with.
+func (store *DBStore) CreateLoginSessionTx(ctx context.Context, arg CreateLoginSessionTxParams) (CreateLoginSessionTxResult, error) {
+	var result CreateLoginSessionTxResult
+	err := store.execTx(ctx, func(queries *Queries) error {
+		stats, err := queries.GetLoginDeviceStats(ctx, GetLoginDeviceStatsParams{
+			UserID:    arg.UserID,
+			ClientIp:  arg.ClientIp,
+			UserAgent: arg.UserAgent,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Session, err = queries.CreateSession(ctx, arg.CreateSessionParams)
+		if err != nil {
+			return err
+		}
+
+		result.NewDevice = stats.Sessions > 0 && stats.DeviceSessions == 0
+		if !result.NewDevice {
+			return nil
+		}
+		return arg.AfterNewDevice(withTxQueries(ctx, queries), result.Session)
+	})
+
+	return result, err
+}
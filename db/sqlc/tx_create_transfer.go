@@ -2,13 +2,34 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	// Amount is debited in the currency of the source account.
+	Amount int64 `json:"amount"`
+	// ExchangeRate is the price of one major unit of the source currency in the destination one.
+	// It is required only when the currencies differ and is left out of the
+	// idempotency hash, so a retry is not rejected after the rate has moved.
+	ExchangeRate float64 `json:"-"`
+	// Exponents maps currency codes to their exponents, see utils.Currency.
+	// Like ExchangeRate, the exponents of both accounts are required only when the currencies differ.
+	Exponents map[string]int32 `json:"-"`
+	// Idempotency is optional; when set, a retry returns the original result.
+	Idempotency *IdempotencyParams `json:"-"`
+	// BeforeTransfer is optional. It runs inside the transaction once the transfer is known to be possible,
+	// right before the money moves, and is skipped on a replay. Its ctx carries the transaction, see QuerierFromContext,
+	// so whatever it writes is rolled back along with a failed transfer.
+	BeforeTransfer func(ctx context.Context) error `json:"-"`
+	// AfterTransfer is optional. It runs inside the transaction once the money has moved and is skipped on a replay.
+	// Its ctx carries the transaction, so e.g. an outbox task commits along with the transfer.
+	AfterTransfer func(ctx context.Context, transfer Transfer) error `json:"-"`
 }
 
 type TransferTxResult struct {
@@ -19,64 +40,128 @@ type TransferTxResult struct {
 	ToEntry     Entry    `json:"to_entry"`
 }
 
+// TransferTx moves money between two accounts. When their currencies differ,
+// the destination account is credited with Amount converted by ExchangeRate.
+// It returns ErrSameAccount, ErrAccountNotFound, ErrAccountFrozen, ErrAccountClosed, ErrCurrencyMismatch,
+// ErrUnknownCurrency, ErrAmountTooSmall or ErrInsufficientFunds when the transfer is not possible, the errors of the hooks,
+// and an empty result on any error.
 func (store *DBStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
-	var result TransferTxResult
-
-	err := store.execTx(ctx, func(queries *Queries) error {
-		var err error
+	if arg.FromAccountID == arg.ToAccountID {
+		return TransferTxResult{}, ErrSameAccount
+	}
 
-		swapAccountsToPreventDeadlock(&arg)
+	var result TransferTxResult
 
-		fromAccount, err := queries.GetAccountForUpdate(ctx, arg.FromAccountID)
+	err := store.execIdempotentTx(ctx, arg.Idempotency, "transfer", arg, &result, func(queries *Queries) error {
+		fromAccount, toAccount, err := lockAccounts(ctx, queries, arg.FromAccountID, arg.ToAccountID)
 		if err != nil {
 			return err
 		}
-		fmt.Println("fromacc.balance = ", fromAccount.Balance)
+
+		if err = checkAccountStatus(fromAccount); err != nil {
+			return err
+		}
+		if err = checkAccountStatus(toAccount); err != nil {
+			return err
+		}
+
+		rate, scale := 1.0, 1.0
+		if fromAccount.Currency != toAccount.Currency {
+			if arg.ExchangeRate <= 0 {
+				return ErrCurrencyMismatch
+			}
+			rate = arg.ExchangeRate
+			if scale, err = minorUnitScale(arg.Exponents, fromAccount.Currency, toAccount.Currency); err != nil {
+				return err
+			}
+		}
+
+		toAmount := int64(math.Round(float64(arg.Amount) * rate * scale))
+		if toAmount <= 0 {
+			return ErrAmountTooSmall
+		}
+
 		if fromAccount.Balance < arg.Amount {
-			result.FromAccount = fromAccount
-			return fmt.Errorf("insufficient funds")
+			return ErrInsufficientFunds
+		}
+
+		if arg.BeforeTransfer != nil {
+			if err = arg.BeforeTransfer(withTxQueries(ctx, queries)); err != nil {
+				return err
+			}
 		}
-		result.Transfer, err = queries.CreateTransfer(ctx, CreateTransferParams(arg))
+
+		result.Transfer, err = queries.CreateTransfer(ctx, CreateTransferParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   arg.ToAccountID,
+			Amount:        arg.Amount,
+			ToAmount:      toAmount,
+			ExchangeRate:  rate,
+		})
 		if err != nil {
 			return err
 		}
 
 		result.FromEntry, err = queries.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
+			AccountID:  arg.FromAccountID,
+			Amount:     -arg.Amount,
+			TransferID: pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
 		})
 		if err != nil {
 			return err
 		}
 
 		result.ToEntry, err = queries.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
+			AccountID:  arg.ToAccountID,
+			Amount:     toAmount,
+			TransferID: pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
 		})
 		if err != nil {
 			return err
 		}
 
-		result.FromAccount, result.ToAccount, err = addMoney(queries, ctx, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
+		result.FromAccount, result.ToAccount, err = addMoney(queries, ctx, arg.FromAccountID, -arg.Amount, arg.ToAccountID, toAmount)
 		if err != nil {
 			return err
 		}
 
+		if arg.AfterTransfer != nil {
+			return arg.AfterTransfer(withTxQueries(ctx, queries), result.Transfer)
+		}
 		return nil
 	})
 
 	if err != nil {
-		fmt.Printf("tx error: %v", err)
+		return TransferTxResult{}, err
+	}
+
+	return result, nil
+}
+
+// lockAccounts locks both accounts in the order of their IDs to prevent a deadlock
+// between two concurrent transfers going in opposite directions.
+func lockAccounts(ctx context.Context, queries *Queries, fromAccountID, toAccountID int64) (fromAccount Account, toAccount Account, err error) {
+	if fromAccountID < toAccountID {
+		if fromAccount, err = lockAccount(ctx, queries, fromAccountID); err != nil {
+			return
+		}
+		toAccount, err = lockAccount(ctx, queries, toAccountID)
+		return
 	}
 
-	return result, err
+	if toAccount, err = lockAccount(ctx, queries, toAccountID); err != nil {
+		return
+	}
+	fromAccount, err = lockAccount(ctx, queries, fromAccountID)
+	return
 }
 
-func swapAccountsToPreventDeadlock(arg *TransferTxParams) {
-	if arg.FromAccountID > arg.ToAccountID {
-		arg.FromAccountID, arg.ToAccountID = arg.ToAccountID, arg.FromAccountID
-		arg.Amount = -arg.Amount
+func lockAccount(ctx context.Context, queries *Queries, accountID int64) (Account, error) {
+	account, err := queries.GetAccountForUpdate(ctx, accountID)
+	if errors.Is(err, ErrRecordNotFound) {
+		return account, ErrAccountNotFound
 	}
+	return account, err
 }
 
 func addMoney(
@@ -103,3 +188,18 @@ func addMoney(
 
 	return
 }
+
+// minorUnitScale converts minor units of one currency into minor units of another
+// when their exponents differ, e.g. it is 0.01 from cents (USD) to yen (JPY).
+// It returns ErrUnknownCurrency when the exponent of either currency is missing.
+func minorUnitScale(exponents map[string]int32, from, to string) (float64, error) {
+	fromExponent, ok := exponents[from]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownCurrency, from)
+	}
+	toExponent, ok := exponents[to]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownCurrency, to)
+	}
+	return math.Pow10(int(toExponent - fromExponent)), nil
+}
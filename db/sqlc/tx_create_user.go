@@ -11,7 +11,8 @@ var ErrUserAlreadyExists = errors.New("user already exists")
 
 type CreateUserTxParams struct {
 	CreateUserParams
-	AfterCreate func(user User) error
+	// AfterCreate runs inside the transaction. Its ctx carries the transaction, see QuerierFromContext.
+	AfterCreate func(ctx context.Context, user User) error
 }
 
 type CreateUserTxResult struct {
@@ -31,7 +32,7 @@ func (store *DBStore) CreateUserTX(ctx context.Context, arg CreateUserTxParams)
 		}
 
 		result.User = user
-		return arg.AfterCreate(user)
+		return arg.AfterCreate(withTxQueries(ctx, queries), user)
 	})
 
 	if err != nil {
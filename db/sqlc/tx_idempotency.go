@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrIdempotencyKeyReused = errors.New("idempotency key is already used for another request")
+
+// IdempotencyParams identifies a client retry scope: the same user sending the same key
+// gets the stored response back instead of running the operation again.
+type IdempotencyParams struct {
+	UserID    int64
+	Key       string
+	ExpiresAt time.Time
+}
+
+// execIdempotentTx runs fn within a transaction guarded by the idempotency key.
+// On a replay the stored response is decoded into response and fn is skipped.
+// A concurrent request with the same key waits on the unique index until the first one commits.
+func (store *DBStore) execIdempotentTx(
+	ctx context.Context,
+	idempotency *IdempotencyParams,
+	operation string,
+	request any,
+	response any,
+	fn func(queries *Queries) error,
+) error {
+	if idempotency == nil {
+		return store.execTx(ctx, fn)
+	}
+
+	requestHash, err := hashRequest(operation, request)
+	if err != nil {
+		return err
+	}
+
+	return store.execTx(ctx, func(queries *Queries) error {
+		key, err := queries.CreateIdempotencyKey(ctx, CreateIdempotencyKeyParams{
+			UserID:         idempotency.UserID,
+			IdempotencyKey: idempotency.Key,
+			RequestHash:    requestHash,
+			ExpiresAt:      idempotency.ExpiresAt,
+		})
+		if errors.Is(err, ErrRecordNotFound) {
+			return replayIdempotencyKey(ctx, queries, idempotency, requestHash, response)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = fn(queries); err != nil {
+			return err
+		}
+
+		responseJSON, err := json.Marshal(response)
+		if err != nil {
+			return fmt.Errorf("couldn't marshal idempotent response: %w", err)
+		}
+
+		return queries.UpdateIdempotencyKeyResponse(ctx, UpdateIdempotencyKeyResponseParams{
+			ID:       key.ID,
+			Response: responseJSON,
+		})
+	})
+}
+
+func replayIdempotencyKey(
+	ctx context.Context,
+	queries *Queries,
+	idempotency *IdempotencyParams,
+	requestHash string,
+	response any,
+) error {
+	key, err := queries.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{
+		UserID:         idempotency.UserID,
+		IdempotencyKey: idempotency.Key,
+	})
+	if err != nil {
+		return err
+	}
+
+	if key.RequestHash != requestHash || key.Response == nil {
+		return ErrIdempotencyKeyReused
+	}
+
+	return json.Unmarshal(key.Response, response)
+}
+
+func hashRequest(operation string, request any) (string, error) {
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("couldn't marshal idempotent request: %w", err)
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(operation))
+	hash.Write(requestJSON)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+)
+
+type LockLoginTxParams struct {
+	CreateLoginLockoutParams
+}
+
+type LockLoginTxResult struct {
+	Lockout LoginLockout `json:"lockout"`
+}
+
+// LockLoginTx records a lockout of the email and clears its failures,
+// so that counting starts over once the lockout ends.
+func (store *DBStore) LockLoginTx(ctx context.Context, arg LockLoginTxParams) (LockLoginTxResult, error) {
+	var result LockLoginTxResult
+
+	err := store.execTx(ctx, func(queries *Queries) error {
+		var err error
+		result.Lockout, err = queries.CreateLoginLockout(ctx, arg.CreateLoginLockoutParams)
+		if err != nil {
+			return err
+		}
+		return queries.ClearLoginFailures(ctx, arg.Email)
+	})
+
+	if err != nil {
+		return LockLoginTxResult{}, err
+	}
+
+	return result, nil
+}
+
+type UnlockUserLoginTxParams struct {
+	UserID     int64  `json:"user_id"`
+	Email      string `json:"email"`
+	UnlockedBy int64  `json:"unlocked_by"`
+}
+
+// UnlockUserLoginTx lifts the active lockouts of the user and clears the failures of the email.
+// It returns how many lockouts were lifted.
+func (store *DBStore) UnlockUserLoginTx(ctx context.Context, arg UnlockUserLoginTxParams) (int64, error) {
+	var unlocked int64
+
+	err := store.execTx(ctx, func(queries *Queries) error {
+		var err error
+		unlocked, err = queries.UnlockUserLogin(ctx, UnlockUserLoginParams{
+			UserID:     arg.UserID,
+			UnlockedBy: arg.UnlockedBy,
+		})
+		if err != nil {
+			return err
+		}
+		return queries.ClearLoginFailures(ctx, arg.Email)
+	})
+
+	return unlocked, err
+}
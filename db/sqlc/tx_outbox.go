@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type txQueriesKey struct{}
+
+// withTxQueries returns a copy of ctx carrying the queries of a running transaction.
+func withTxQueries(ctx context.Context, queries *Queries) context.Context {
+	return context.WithValue(ctx, txQueriesKey{}, queries)
+}
+
+// QuerierFromContext returns the queries of the transaction ctx was handed out by,
+// so that writes made with it commit or roll back together with the transaction.
+// Outside of a transaction it returns fallback.
+func QuerierFromContext(ctx context.Context, fallback Querier) Querier {
+	if queries, ok := ctx.Value(txQueriesKey{}).(*Queries); ok {
+		return queries
+	}
+	return fallback
+}
+
+type RelayOutboxTxParams struct {
+	Limit int32 `json:"limit"`
+	// Publish hands the task over to the queue.
+	Publish func(task Outbox) error
+	// Backoff tells how long to wait before the next attempt after the given number of failed ones.
+	Backoff func(attempts int32) time.Duration
+}
+
+type RelayOutboxTxResult struct {
+	Sent   int `json:"sent"`
+	Failed int `json:"failed"`
+}
+
+// RelayOutboxTx publishes a batch of due outbox tasks and records the outcome of each.
+// The claimed rows stay locked until the transaction ends, so concurrent relays never publish the same task.
+func (store *DBStore) RelayOutboxTx(ctx context.Context, arg RelayOutboxTxParams) (RelayOutboxTxResult, error) {
+	var result RelayOutboxTxResult
+
+	err := store.execTx(ctx, func(queries *Queries) error {
+		tasks, err := queries.ClaimOutboxTasks(ctx, arg.Limit)
+		if err != nil {
+			return err
+		}
+
+		for _, task := range tasks {
+			if publishErr := arg.Publish(task); publishErr != nil {
+				result.Failed++
+				err = queries.MarkOutboxTaskFailed(ctx, MarkOutboxTaskFailedParams{
+					ID:            task.ID,
+					LastError:     publishErr.Error(),
+					NextAttemptAt: time.Now().Add(arg.Backoff(task.Attempts + 1)),
+				})
+			} else {
+				result.Sent++
+				err = queries.MarkOutboxTaskSent(ctx, task.ID)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return RelayOutboxTxResult{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionBlocked      = errors.New("session is blocked")
+	ErrSessionExpired      = errors.New("session is expired")
+	ErrRefreshTokenReused  = errors.New("refresh token is already used")
+	ErrRefreshTokenInvalid = errors.New("refresh token does not match the session")
+)
+
+type RenewSessionTxParams struct {
+	// SessionID and RefreshToken identify the session the client renews.
+	SessionID    uuid.UUID
+	UserID       int64
+	RefreshToken string
+	// NewSession replaces it; its family is inherited from the renewed session.
+	NewSession CreateSessionParams
+}
+
+type RenewSessionTxResult struct {
+	Session Session `json:"session"`
+}
+
+// RenewSessionTx rotates a refresh token: the renewed session is marked as rotated
+// and a new session of the same family is created.
+// Presenting a rotated token again means it has leaked, so the whole family is blocked
+// and ErrRefreshTokenReused is returned.
+func (store *DBStore) RenewSessionTx(ctx context.Context, arg RenewSessionTxParams) (RenewSessionTxResult, error) {
+	var result RenewSessionTxResult
+	reused := false
+
+	err := store.execTx(ctx, func(queries *Queries) error {
+		session, err := queries.GetSessionForUpdate(ctx, arg.SessionID)
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrSessionNotFound
+			}
+			return err
+		}
+
+		if session.UserID != arg.UserID || session.RefreshToken != arg.RefreshToken {
+			return ErrRefreshTokenInvalid
+		}
+		if session.IsBlocked {
+			return ErrSessionBlocked
+		}
+		if session.RotatedAt.Valid {
+			// the block must be committed, so the error is returned after the transaction
+			reused = true
+			return queries.BlockSessionFamily(ctx, session.FamilyID)
+		}
+		if time.Now().After(session.ExpiresAt) {
+			return ErrSessionExpired
+		}
+
+		if _, err = queries.RotateSession(ctx, session.ID); err != nil {
+			return err
+		}
+
+		newSession := arg.NewSession
+		newSession.FamilyID = session.FamilyID
+		result.Session, err = queries.CreateSession(ctx, newSession)
+		return err
+	})
+
+	if err != nil {
+		return RenewSessionTxResult{}, err
+	}
+	if reused {
+		return RenewSessionTxResult{}, ErrRefreshTokenReused
+	}
+
+	return result, nil
+}
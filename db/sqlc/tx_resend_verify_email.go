@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type ResendVerifyEmailTxParams struct {
+	UserID int64 `json:"user_id"`
+	// Limit is how many verification emails the user may request since Since.
+	Limit int64     `json:"limit"`
+	Since time.Time `json:"since"`
+	// AfterRequest runs inside the transaction once the request is recorded. Its ctx carries the transaction, see QuerierFromContext.
+	AfterRequest func(ctx context.Context) error `json:"-"`
+}
+
+// ResendVerifyEmailTx records a verification email request of the user, or returns ErrTooManyRequests
+// if the user has reached the limit. The user row stays locked until the end of the transaction,
+// so that concurrent requests are counted one after another.
+func (store *DBStore) ResendVerifyEmailTx(ctx context.Context, arg ResendVerifyEmailTxParams) error {
+	return store.execTx(ctx, func(queries *Queries) error {
+		if _, err := queries.GetUserForUpdate(ctx, arg.UserID); err != nil {
+			return err
+		}
+
+		requested, err := queries.CountVerifyEmailRequests(ctx, CountVerifyEmailRequestsParams{
+			UserID: arg.UserID,
+			Since:  arg.Since,
+		})
+		if err != nil {
+			return err
+		}
+		if requested >= arg.Limit {
+			return ErrTooManyRequests
+		}
+
+		if err = queries.CreateVerifyEmailRequest(ctx, arg.UserID); err != nil {
+			return err
+		}
+
+		return arg.AfterRequest(withTxQueries(ctx, queries))
+	})
+}
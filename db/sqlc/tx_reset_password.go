@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrPasswordResetInvalid = errors.New("password reset code is invalid or expired")
+
+type ResetPasswordTxParams struct {
+	PasswordResetID int64  `json:"password_reset_id"`
+	HashedPassword  string `json:"-"`
+}
+
+type ResetPasswordTxResult struct {
+	User User `json:"user"`
+}
+
+// ResetPasswordTx uses up the password reset, sets the new password and blocks every session of the user.
+// The other pending resets of the user are expired as well.
+// It returns ErrPasswordResetInvalid if the reset is already used or expired.
+func (store *DBStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error) {
+	var result ResetPasswordTxResult
+
+	err := store.execTx(ctx, func(queries *Queries) error {
+		reset, err := queries.UsePasswordReset(ctx, arg.PasswordResetID)
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrPasswordResetInvalid
+			}
+			return err
+		}
+
+		result.User, err = queries.UpdateUser(ctx, UpdateUserParams{
+			ID:             reset.UserID,
+			HashedPassword: pgtype.Text{String: arg.HashedPassword, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = queries.ExpirePasswordResets(ctx, reset.UserID); err != nil {
+			return err
+		}
+
+		_, err = queries.BlockUserSessions(ctx, BlockUserSessionsParams{UserID: reset.UserID})
+		return err
+	})
+
+	if err != nil {
+		return ResetPasswordTxResult{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrTOTPAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+
+type EnrolTOTPTxParams struct {
+	UserID int64  `json:"user_id"`
+	Secret string `json:"-"`
+	// RecoveryCodeHashes replace the recovery codes of any earlier, unconfirmed enrolment.
+	RecoveryCodeHashes []string `json:"-"`
+}
+
+type EnrolTOTPTxResult struct {
+	TOTP UserTotp `json:"totp"`
+}
+
+// EnrolTOTPTx stores a new, not yet confirmed TOTP secret along with the recovery codes.
+// It returns ErrTOTPAlreadyEnabled if the user has a confirmed secret.
+func (store *DBStore) EnrolTOTPTx(ctx context.Context, arg EnrolTOTPTxParams) (EnrolTOTPTxResult, error) {
+	var result EnrolTOTPTxResult
+
+	err := store.execTx(ctx, func(queries *Queries) error {
+		var err error
+		result.TOTP, err = queries.EnrolUserTOTP(ctx, EnrolUserTOTPParams{
+			UserID: arg.UserID,
+			Secret: arg.Secret,
+		})
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrTOTPAlreadyEnabled
+			}
+			return err
+		}
+
+		if err = queries.DeleteRecoveryCodes(ctx, arg.UserID); err != nil {
+			return err
+		}
+
+		for _, codeHash := range arg.RecoveryCodeHashes {
+			err = queries.CreateRecoveryCode(ctx, CreateRecoveryCodeParams{
+				UserID:   arg.UserID,
+				CodeHash: codeHash,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return EnrolTOTPTxResult{}, err
+	}
+
+	return result, nil
+}
+
+// DisableTOTPTx removes the TOTP secret of the user along with the recovery codes.
+func (store *DBStore) DisableTOTPTx(ctx context.Context, userID int64) error {
+	return store.execTx(ctx, func(queries *Queries) error {
+		if err := queries.DeleteRecoveryCodes(ctx, userID); err != nil {
+			return err
+		}
+		return queries.DeleteUserTOTP(ctx, userID)
+	})
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	AccountStatusActive = "active"
+	AccountStatusFrozen = "frozen"
+	AccountStatusClosed = "closed"
+)
+
+// accountStatusTransitions lists the statuses an account may move to from each status.
+// A closed account is final; it stays readable for history but never changes again.
+var accountStatusTransitions = map[string][]string{
+	AccountStatusActive: {AccountStatusFrozen, AccountStatusClosed},
+	AccountStatusFrozen: {AccountStatusActive, AccountStatusClosed},
+}
+
+type UpdateAccountStatusTxParams struct {
+	AccountID int64  `json:"account_id"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason"`
+}
+
+type UpdateAccountStatusTxResult struct {
+	Account Account `json:"account"`
+}
+
+// UpdateAccountStatusTx freezes, unfreezes or closes an account.
+// It returns ErrAccountNotFound, ErrInvalidStatusTransition or ErrAccountBalanceNotZero
+// when the change is not possible.
+func (store *DBStore) UpdateAccountStatusTx(ctx context.Context, arg UpdateAccountStatusTxParams) (UpdateAccountStatusTxResult, error) {
+	var result UpdateAccountStatusTxResult
+
+	err := store.execTx(ctx, func(queries *Queries) error {
+		account, err := lockAccount(ctx, queries, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if !canChangeAccountStatus(account.Status, arg.Status) {
+			return fmt.Errorf("%w: from %s to %s", ErrInvalidStatusTransition, account.Status, arg.Status)
+		}
+
+		if arg.Status == AccountStatusClosed && account.Balance != 0 {
+			return ErrAccountBalanceNotZero
+		}
+
+		result.Account, err = queries.UpdateAccountStatus(ctx, UpdateAccountStatusParams{
+			ID:           arg.AccountID,
+			Status:       arg.Status,
+			StatusReason: arg.Reason,
+		})
+		return err
+	})
+
+	if err != nil {
+		return UpdateAccountStatusTxResult{}, err
+	}
+
+	return result, nil
+}
+
+func canChangeAccountStatus(from, to string) bool {
+	for _, status := range accountStatusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAccountStatus tells whether money can be moved in or out of the account.
+func checkAccountStatus(account Account) error {
+	switch account.Status {
+	case AccountStatusFrozen:
+		return fmt.Errorf("%w: %d", ErrAccountFrozen, account.ID)
+	case AccountStatusClosed:
+		return fmt.Errorf("%w: %d", ErrAccountClosed, account.ID)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type UpdateUserTxParams struct {
+	UpdateUserParams
+	// AfterEmailChange runs inside the transaction when the email differs from the previous one.
+	// Its ctx carries the transaction, see QuerierFromContext.
+	AfterEmailChange func(ctx context.Context, user User) error
+}
+
+type UpdateUserTxResult struct {
+	User         User `json:"user"`
+	EmailChanged bool `json:"email_changed"`
+}
+
+// UpdateUserTx updates the user and drops the verification of a changed email,
+// so that the new email has to be verified again.
+func (store *DBStore) UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error) {
+	var result UpdateUserTxResult
+
+	err := store.execTx(ctx, func(queries *Queries) error {
+		user, err := queries.GetUserForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		params := arg.UpdateUserParams
+		result.EmailChanged = params.Email.Valid && params.Email.String != user.Email
+		if result.EmailChanged {
+			params.IsVerified = pgtype.Bool{Bool: false, Valid: true}
+		}
+
+		result.User, err = queries.UpdateUser(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		if result.EmailChanged && arg.AfterEmailChange != nil {
+			return arg.AfterEmailChange(withTxQueries(ctx, queries), result.User)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return UpdateUserTxResult{}, err
+	}
+
+	return result, nil
+}
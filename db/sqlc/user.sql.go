@@ -16,9 +16,10 @@ INSERT INTO users (username,
                       role,
                       hashed_password,
                       full_name,
-                      email)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, username, hashed_password, full_name, email, password_changed_at, created_at, is_verified, role
+                      email,
+                      locale)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, username, hashed_password, full_name, email, password_changed_at, created_at, is_verified, role, locale
 `
 
 type CreateUserParams struct {
@@ -27,6 +28,7 @@ type CreateUserParams struct {
 	HashedPassword string `json:"hashed_password"`
 	FullName       string `json:"full_name"`
 	Email          string `json:"email"`
+	Locale         string `json:"locale"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -36,6 +38,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.HashedPassword,
 		arg.FullName,
 		arg.Email,
+		arg.Locale,
 	)
 	var i User
 	err := row.Scan(
@@ -48,12 +51,13 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.IsVerified,
 		&i.Role,
+		&i.Locale,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, username, hashed_password, full_name, email, password_changed_at, created_at, is_verified, role
+SELECT id, username, hashed_password, full_name, email, password_changed_at, created_at, is_verified, role, locale
 FROM users
 WHERE id = $1
 `
@@ -71,12 +75,13 @@ func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
 		&i.CreatedAt,
 		&i.IsVerified,
 		&i.Role,
+		&i.Locale,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, username, hashed_password, full_name, email, password_changed_at, created_at, is_verified, role
+SELECT id, username, hashed_password, full_name, email, password_changed_at, created_at, is_verified, role, locale
 FROM users
 WHERE email = $1
 `
@@ -94,6 +99,31 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.CreatedAt,
 		&i.IsVerified,
 		&i.Role,
+		&i.Locale,
+	)
+	return i, err
+}
+
+const getUserForUpdate = `-- name: GetUserForUpdate :one
+SELECT id, username, hashed_password, full_name, email, password_changed_at, created_at, is_verified, role, locale
+FROM users
+WHERE id = $1 FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetUserForUpdate(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRow(ctx, getUserForUpdate, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsVerified,
+		&i.Role,
+		&i.Locale,
 	)
 	return i, err
 }
@@ -104,9 +134,10 @@ SET hashed_password = COALESCE($1, hashed_password),
     full_name = COALESCE($2, full_name),
     email = COALESCE($3, email),
     is_verified = COALESCE($4, is_verified),
+    locale = COALESCE($5, locale),
     password_changed_at = (CASE WHEN $1 IS null THEN password_changed_at ELSE now() END)
-WHERE id = $5
-RETURNING id, username, hashed_password, full_name, email, password_changed_at, created_at, is_verified, role
+WHERE id = $6
+RETURNING id, username, hashed_password, full_name, email, password_changed_at, created_at, is_verified, role, locale
 `
 
 type UpdateUserParams struct {
@@ -114,6 +145,7 @@ type UpdateUserParams struct {
 	FullName       pgtype.Text `json:"full_name"`
 	Email          pgtype.Text `json:"email"`
 	IsVerified     pgtype.Bool `json:"is_verified"`
+	Locale         pgtype.Text `json:"locale"`
 	ID             int64       `json:"id"`
 }
 
@@ -123,6 +155,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.FullName,
 		arg.Email,
 		arg.IsVerified,
+		arg.Locale,
 		arg.ID,
 	)
 	var i User
@@ -136,6 +169,39 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.CreatedAt,
 		&i.IsVerified,
 		&i.Role,
+		&i.Locale,
+	)
+	return i, err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+UPDATE users
+SET is_verified = true
+WHERE id = $1
+  AND email = $2
+RETURNING id, username, hashed_password, full_name, email, password_changed_at, created_at, is_verified, role, locale
+`
+
+type VerifyUserEmailParams struct {
+	ID    int64  `json:"id"`
+	Email string `json:"email"`
+}
+
+// the email must still be the one the code was sent to
+func (q *Queries) VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, verifyUserEmail, arg.ID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsVerified,
+		&i.Role,
+		&i.Locale,
 	)
 	return i, err
 }
@@ -95,3 +95,43 @@ func TestUpdateUserPasswordOnly(t *testing.T) {
 	require.Equal(t, user.FullName, updatedUser.FullName)
 	require.Equal(t, user.Username, updatedUser.Username)
 }
+
+func TestUpdateUserTxEmailChange(t *testing.T) {
+	ctx := context.Background()
+	user, _ := createRandUser(t)
+	user, err := testStore.VerifyUserEmail(ctx, VerifyUserEmailParams{ID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+	require.True(t, user.IsVerified.Bool)
+
+	// the same email keeps the verification
+	afterEmailChangeCalls := 0
+	result, err := testStore.UpdateUserTx(ctx, UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{ID: user.ID, Email: pgtype.Text{String: user.Email, Valid: true}},
+		AfterEmailChange: func(ctx context.Context, user User) error {
+			afterEmailChangeCalls++
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, result.EmailChanged)
+	require.True(t, result.User.IsVerified.Bool)
+	require.Zero(t, afterEmailChangeCalls)
+
+	newEmail := utils.RandomEmail()
+	result, err = testStore.UpdateUserTx(ctx, UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{ID: user.ID, Email: pgtype.Text{String: newEmail, Valid: true}},
+		AfterEmailChange: func(ctx context.Context, user User) error {
+			afterEmailChangeCalls++
+			require.Equal(t, newEmail, user.Email)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, result.EmailChanged)
+	require.False(t, result.User.IsVerified.Bool)
+	require.Equal(t, 1, afterEmailChangeCalls)
+
+	// a code sent to the previous email doesn't verify the new one
+	_, err = testStore.VerifyUserEmail(ctx, VerifyUserEmailParams{ID: user.ID, Email: user.Email})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -10,6 +10,25 @@ import (
 	"time"
 )
 
+const countVerifyEmailRequests = `-- name: CountVerifyEmailRequests :one
+SELECT count(*)
+FROM verify_email_requests
+WHERE user_id = $1
+  AND created_at > $2
+`
+
+type CountVerifyEmailRequestsParams struct {
+	UserID int64     `json:"user_id"`
+	Since  time.Time `json:"since"`
+}
+
+func (q *Queries) CountVerifyEmailRequests(ctx context.Context, arg CountVerifyEmailRequestsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countVerifyEmailRequests, arg.UserID, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createVerifyEmail = `-- name: CreateVerifyEmail :one
 INSERT INTO verify_emails (user_id,
                       email,
@@ -17,7 +36,11 @@ INSERT INTO verify_emails (user_id,
                       is_used,
                       expired_at)
 VALUES ($1, $2, $3, $4, $5)
-ON CONFLICT (email) DO UPDATE SET code = $3
+ON CONFLICT (email) DO UPDATE SET user_id    = EXCLUDED.user_id,
+                                  code       = EXCLUDED.code,
+                                  is_used    = EXCLUDED.is_used,
+                                  expired_at = EXCLUDED.expired_at,
+                                  created_at = now()
 RETURNING id, user_id, email, code, is_used, created_at, expired_at
 `
 
@@ -29,6 +52,7 @@ type CreateVerifyEmailParams struct {
 	ExpiredAt time.Time `json:"expired_at"`
 }
 
+// a new code for the same email replaces the pending one and starts its expiry over
 func (q *Queries) CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error) {
 	row := q.db.QueryRow(ctx, createVerifyEmail,
 		arg.UserID,
@@ -50,6 +74,16 @@ func (q *Queries) CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailPa
 	return i, err
 }
 
+const createVerifyEmailRequest = `-- name: CreateVerifyEmailRequest :exec
+INSERT INTO verify_email_requests (user_id)
+VALUES ($1)
+`
+
+func (q *Queries) CreateVerifyEmailRequest(ctx context.Context, userID int64) error {
+	_, err := q.db.Exec(ctx, createVerifyEmailRequest, userID)
+	return err
+}
+
 const getVerifyEmail = `-- name: GetVerifyEmail :one
 SELECT id, user_id, email, code, is_used, created_at, expired_at
 FROM verify_emails
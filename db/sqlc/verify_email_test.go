@@ -0,0 +1,60 @@
+package db
+
+import (
+	"bank/utils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateVerifyEmailReplacesPending(t *testing.T) {
+	ctx := context.Background()
+	user, _ := createRandUser(t)
+
+	first, err := testStore.CreateVerifyEmail(ctx, CreateVerifyEmailParams{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Code:      utils.RandomString(32),
+		ExpiredAt: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	require.NoError(t, testStore.UpdateVerifyEmails(ctx, UpdateVerifyEmailsParams{ID: first.ID, IsUsed: true}))
+
+	expiredAt := time.Now().Add(15 * time.Minute)
+	second, err := testStore.CreateVerifyEmail(ctx, CreateVerifyEmailParams{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Code:      utils.RandomString(32),
+		ExpiredAt: expiredAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, first.ID, second.ID)
+	require.NotEqual(t, first.Code, second.Code)
+	require.False(t, second.IsUsed)
+	require.WithinDuration(t, expiredAt, second.ExpiredAt, time.Second)
+}
+
+func TestCountVerifyEmailRequests(t *testing.T) {
+	ctx := context.Background()
+	user, _ := createRandUser(t)
+
+	for range 2 {
+		require.NoError(t, testStore.CreateVerifyEmailRequest(ctx, user.ID))
+	}
+
+	count, err := testStore.CountVerifyEmailRequests(ctx, CountVerifyEmailRequestsParams{
+		UserID: user.ID,
+		Since:  time.Now().Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	count, err = testStore.CountVerifyEmailRequests(ctx, CountVerifyEmailRequestsParams{
+		UserID: user.ID,
+		Since:  time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
@@ -0,0 +1,43 @@
+package fx
+
+import (
+	db "bank/db/sqlc"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DBRateProvider reads rates from the fx_rates table.
+type DBRateProvider struct {
+	querier db.Querier
+}
+
+func NewDBRateProvider(querier db.Querier) FXRateProvider {
+	return &DBRateProvider{querier: querier}
+}
+
+// GetRate falls back to the inverse of the opposite pair when the direct one is not stored.
+func (provider *DBRateProvider) GetRate(ctx context.Context, base, quote string) (float64, error) {
+	same, err := checkPair(base, quote)
+	if err != nil || same {
+		return 1, err
+	}
+
+	fxRate, err := provider.querier.GetFXRate(ctx, db.GetFXRateParams{BaseCurrency: base, QuoteCurrency: quote})
+	if err == nil && fxRate.Rate > 0 {
+		return fxRate.Rate, nil
+	}
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	fxRate, err = provider.querier.GetFXRate(ctx, db.GetFXRateParams{BaseCurrency: quote, QuoteCurrency: base})
+	if err == nil && fxRate.Rate > 0 {
+		return 1 / fxRate.Rate, nil
+	}
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("%w: %s/%s", ErrRateNotFound, base, quote)
+}
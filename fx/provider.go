@@ -0,0 +1,44 @@
+package fx
+
+import (
+	db "bank/db/sqlc"
+	"bank/utils"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnsupportedPair = errors.New("unsupported currency pair")
+	ErrRateNotFound    = errors.New("exchange rate not found")
+)
+
+const (
+	SourceStatic = "static"
+	SourceDB     = "db"
+)
+
+// FXRateProvider tells how many units of the quote currency one unit of the base currency buys.
+type FXRateProvider interface {
+	GetRate(ctx context.Context, base, quote string) (float64, error)
+}
+
+// checkPair makes sure both currencies are supported by the bank.
+// The pair of a currency with itself always has the rate of 1.
+func checkPair(base, quote string) (same bool, err error) {
+	if !utils.IsCurrencySupported(base) || !utils.IsCurrencySupported(quote) {
+		return false, fmt.Errorf("%w: %s/%s", ErrUnsupportedPair, base, quote)
+	}
+	return base == quote, nil
+}
+
+// NewRateProvider picks the provider named by config.FXRateSource, the DB table by default.
+func NewRateProvider(config utils.Config, querier db.Querier) (FXRateProvider, error) {
+	switch config.FXRateSource {
+	case SourceStatic:
+		return LoadStaticRateProvider(config.FXRatesFile)
+	case SourceDB, "":
+		return NewDBRateProvider(querier), nil
+	}
+	return nil, fmt.Errorf("unknown fx rate source %q", config.FXRateSource)
+}
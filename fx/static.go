@@ -0,0 +1,50 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// StaticRateProvider serves rates from a fixed table, e.g. loaded from a JSON file.
+type StaticRateProvider struct {
+	rates map[string]map[string]float64
+}
+
+// NewStaticRateProvider creates a provider over rates keyed by base and then by quote currency.
+func NewStaticRateProvider(rates map[string]map[string]float64) FXRateProvider {
+	return &StaticRateProvider{rates: rates}
+}
+
+// LoadStaticRateProvider reads the rates from a JSON file like {"USD": {"EUR": 0.92}}.
+func LoadStaticRateProvider(path string) (FXRateProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read fx rates file: %w", err)
+	}
+
+	var rates map[string]map[string]float64
+	if err = json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("cannot parse fx rates file: %w", err)
+	}
+
+	return NewStaticRateProvider(rates), nil
+}
+
+// GetRate falls back to the inverse of the opposite pair when the direct one is not listed.
+func (provider *StaticRateProvider) GetRate(_ context.Context, base, quote string) (float64, error) {
+	same, err := checkPair(base, quote)
+	if err != nil || same {
+		return 1, err
+	}
+
+	if rate, ok := provider.rates[base][quote]; ok && rate > 0 {
+		return rate, nil
+	}
+	if rate, ok := provider.rates[quote][base]; ok && rate > 0 {
+		return 1 / rate, nil
+	}
+
+	return 0, fmt.Errorf("%w: %s/%s", ErrRateNotFound, base, quote)
+}
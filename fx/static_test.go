@@ -0,0 +1,51 @@
+package fx
+
+import (
+	"bank/utils"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticRateProvider(t *testing.T) {
+	provider := NewStaticRateProvider(map[string]map[string]float64{
+		utils.USD: {utils.EUR: 0.8},
+	})
+
+	rate, err := provider.GetRate(context.Background(), utils.USD, utils.EUR)
+	require.NoError(t, err)
+	require.Equal(t, 0.8, rate)
+
+	rate, err = provider.GetRate(context.Background(), utils.EUR, utils.USD)
+	require.NoError(t, err)
+	require.Equal(t, 1.25, rate)
+
+	rate, err = provider.GetRate(context.Background(), utils.UAH, utils.UAH)
+	require.NoError(t, err)
+	require.Equal(t, 1.0, rate)
+
+	_, err = provider.GetRate(context.Background(), utils.USD, utils.UAH)
+	require.ErrorIs(t, err, ErrRateNotFound)
+
+	_, err = provider.GetRate(context.Background(), utils.USD, "GBP")
+	require.ErrorIs(t, err, ErrUnsupportedPair)
+}
+
+func TestLoadStaticRateProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fx_rates.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"EUR": {"UAH": 45.5}}`), 0o600))
+
+	provider, err := LoadStaticRateProvider(path)
+	require.NoError(t, err)
+
+	rate, err := provider.GetRate(context.Background(), utils.EUR, utils.UAH)
+	require.NoError(t, err)
+	require.Equal(t, 45.5, rate)
+
+	require.NoError(t, os.WriteFile(path, []byte(`not json`), 0o600))
+	_, err = LoadStaticRateProvider(path)
+	require.Error(t, err)
+}
@@ -0,0 +1,50 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/validation"
+	"context"
+	"errors"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// changeAccountStatus backs the FreezeAccount, UnfreezeAccount and CloseAccount RPCs.
+func (server *Server) changeAccountStatus(ctx context.Context, accountID int64, accountStatus, reason string) (*pb.Account, error) {
+	if violations := validateAccountStatusRequest(accountID, reason); violations != nil {
+		return nil, validationError(violations)
+	}
+
+	result, err := server.store.UpdateAccountStatusTx(ctx, db.UpdateAccountStatusTxParams{
+		AccountID: accountID,
+		Status:    accountStatus,
+		Reason:    reason,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrAccountNotFound):
+			return nil, status.Errorf(codes.NotFound, "account %d not found", accountID)
+		case errors.Is(err, db.ErrInvalidStatusTransition), errors.Is(err, db.ErrAccountBalanceNotZero):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to update account status: %s", err.Error())
+	}
+
+	return convertAccount(result.Account), nil
+}
+
+func validateAccountStatusRequest(accountID int64, reason string) (violations []*errdetails.BadRequest_FieldViolation) {
+	if valErr := validation.ValidateID(accountID, "account_id"); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+	if valErr := validation.ValidateStatusReason(reason); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+
+	return violations
+}
@@ -1,18 +1,18 @@
 package gapi
 
 import (
+	"bank/pb"
 	"bank/token"
-	"bank/utils"
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
-var ErrRoleForbidden = errors.New("role forbidden")
-
 const (
 	authHeader                  = "authorization"
 	authBearer                  = "bearer"
@@ -22,7 +22,80 @@ const (
 	msgErrAuthHeaderUnsupported = "unsupported auth scheme"
 )
 
-func (server *Server) authorizeUser(ctx context.Context, allowedRoles []utils.Role) (*token.Payload, error) {
+// publicMethods are reachable without an access token. Every other method is authenticated
+// by the interceptors, so a new RPC is protected unless it is listed here.
+var publicMethods = map[string]bool{
+	pb.Bank_CreateUser_FullMethodName:           true,
+	pb.Bank_LoginUser_FullMethodName:            true,
+	pb.Bank_LoginUserMFA_FullMethodName:         true,
+	pb.Bank_VerifyEmail_FullMethodName:          true,
+	pb.Bank_RenewAccessToken_FullMethodName:     true,
+	pb.Bank_ListCurrencies_FullMethodName:       true,
+	pb.Bank_RequestPasswordReset_FullMethodName: true,
+	pb.Bank_ResetPassword_FullMethodName:        true,
+}
+
+type authPayloadKey struct{}
+
+// AuthInterceptor authenticates and authorizes the caller of a unary method
+// and hands the verified token payload to the handler through the context.
+func (server *Server) AuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := server.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamAuthInterceptor is AuthInterceptor for streaming methods.
+func (server *Server) StreamAuthInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := server.authenticate(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+// authenticatedStream carries the context with the token payload to the stream handler.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authenticatedStream) Context() context.Context {
+	return stream.ctx
+}
+
+// authenticate verifies the caller of a non-public method, checks the permissions of the method
+// and returns the context carrying the token payload. The returned error is a gRPC status.
+func (server *Server) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if publicMethods[method] {
+		return ctx, nil
+	}
+
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err = server.authorizeMethod(ctx, authPayload, method); err != nil {
+		return nil, err
+	}
+
+	return context.WithValue(ctx, authPayloadKey{}, authPayload), nil
+}
+
+// authPayloadFromContext returns the token payload put into the context by the auth interceptors.
+func authPayloadFromContext(ctx context.Context) (*token.Payload, error) {
+	authPayload, isOK := ctx.Value(authPayloadKey{}).(*token.Payload)
+	if !isOK {
+		return nil, status.Errorf(codes.Unauthenticated, "request is not authenticated")
+	}
+	return authPayload, nil
+}
+
+// authorizeUser verifies the access token of the request.
+func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error) {
 	md, isOK := metadata.FromIncomingContext(ctx)
 	if !isOK {
 		return nil, fmt.Errorf(msgErrMetadata)
@@ -47,18 +120,13 @@ func (server *Server) authorizeUser(ctx context.Context, allowedRoles []utils.Ro
 		return nil, fmt.Errorf("failed to auth: %s", err.Error())
 	}
 
-	if !isRoleAllowed(payload.Role, allowedRoles) {
-		return nil, ErrRoleForbidden
+	if err = payload.CheckType(token.TokenTypeAccess); err != nil {
+		return nil, fmt.Errorf("failed to auth: %s", err.Error())
 	}
 
-	return payload, nil
-}
-
-func isRoleAllowed(role utils.Role, allowedRoles []utils.Role) bool {
-	for _, allowedRole := range allowedRoles {
-		if role == allowedRole {
-			return true
-		}
+	if err = server.revocation.Check(ctx, payload); err != nil {
+		return nil, fmt.Errorf("failed to auth: %s", err.Error())
 	}
-	return false
+
+	return payload, nil
 }
@@ -0,0 +1,237 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/token"
+	"bank/utils"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorizeUserRevokedToken(t *testing.T) {
+	user := randomUser("password")
+	sessionID := uuid.New()
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkError func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(db.Session{ID: sessionID, UserID: user.ID}, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Password changed",
+			buildStubs: func(store *mockdb.MockStore) {
+				changed := user
+				changed.PasswordChangedAt = time.Now().Add(time.Second)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(changed, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, token.ErrTokenRevoked.Error())
+			},
+		},
+		{
+			name: "Session blocked",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(db.Session{ID: sessionID, UserID: user.ID, IsBlocked: true}, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, token.ErrTokenRevoked.Error())
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ctrl := gomock.NewController(t)
+
+		store := mockdb.NewMockStore(ctrl)
+
+		tc.buildStubs(store)
+
+		server := newTestServer(t, store, nil)
+		server.revocation = token.NewRevocationChecker(store, time.Minute)
+
+		accessToken, _, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), token.TokenTypeAccess, sessionID, time.Minute)
+		require.NoError(t, err)
+
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.New(map[string]string{authHeader: fmt.Sprintf("%s %s", authBearer, accessToken)}),
+		)
+		_, err = server.authorizeUser(ctx)
+
+		tc.checkError(t, err)
+	}
+}
+
+func TestAuthorizeUserRejectsRefreshToken(t *testing.T) {
+	user := randomUser("password")
+
+	server := newTestServer(t, nil, nil)
+
+	refreshToken, _, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), token.TokenTypeRefresh, newTestSession(user.ID), time.Minute)
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(
+		context.Background(),
+		metadata.New(map[string]string{authHeader: fmt.Sprintf("%s %s", authBearer, refreshToken)}),
+	)
+	_, err = server.authorizeUser(ctx)
+	require.ErrorContains(t, err, token.ErrWrongTokenType.Error())
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	depositor := randomUser("password")
+	banker := randomUser("password")
+	banker.Role = string(utils.Banker)
+
+	testCases := []struct {
+		name          string
+		method        string
+		makeContext   func(server *Server) context.Context
+		checkResponse func(t *testing.T, called bool, err error)
+	}{
+		{
+			name:   "Own permission",
+			method: pb.Bank_CreateAccount_FullMethodName,
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, depositor, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, called bool, err error) {
+				require.NoError(t, err)
+				require.True(t, called)
+			},
+		},
+		{
+			name:   "Any permission",
+			method: pb.Bank_UnlockUser_FullMethodName,
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, banker, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, called bool, err error) {
+				require.NoError(t, err)
+				require.True(t, called)
+			},
+		},
+		{
+			name:   "Permission denied",
+			method: pb.Bank_ListLoginLockouts_FullMethodName,
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, depositor, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, called bool, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+				require.False(t, called)
+			},
+		},
+		{
+			name:   "No authorization",
+			method: pb.Bank_CreateAccount_FullMethodName,
+			makeContext: func(server *Server) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, called bool, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+				require.False(t, called)
+			},
+		},
+		{
+			name:   "Public method",
+			method: pb.Bank_ListCurrencies_FullMethodName,
+			makeContext: func(server *Server) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, called bool, err error) {
+				require.NoError(t, err)
+				require.True(t, called)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store, nil)
+
+			called := false
+			_, err := server.AuthInterceptor(tc.makeContext(server), nil, &grpc.UnaryServerInfo{FullMethod: tc.method},
+				func(ctx context.Context, req any) (any, error) {
+					called = true
+					if !publicMethods[tc.method] {
+						authPayload, err := authPayloadFromContext(ctx)
+						require.NoError(t, err)
+						require.NotEmpty(t, authPayload.UserID)
+					}
+					return nil, nil
+				})
+			tc.checkResponse(t, called, err)
+		})
+	}
+}
+
+func TestStreamAuthInterceptor(t *testing.T) {
+	user := randomUser("password")
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store, nil)
+
+	info := &grpc.StreamServerInfo{FullMethod: pb.Bank_ListSessions_FullMethodName, IsServerStream: true}
+
+	called := false
+	ctx := newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+	err := server.StreamAuthInterceptor(nil, &testServerStream{ctx: ctx}, info, func(srv any, stream grpc.ServerStream) error {
+		called = true
+		authPayload, err := authPayloadFromContext(stream.Context())
+		require.NoError(t, err)
+		require.Equal(t, user.ID, authPayload.UserID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, called)
+
+	called = false
+	err = server.StreamAuthInterceptor(nil, &testServerStream{ctx: context.Background()}, info, func(srv any, stream grpc.ServerStream) error {
+		called = true
+		return nil
+	})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	require.False(t, called)
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *testServerStream) Context() context.Context {
+	return stream.ctx
+}
@@ -2,7 +2,9 @@ package gapi
 
 import (
 	db "bank/db/sqlc"
+	"bank/notify"
 	"bank/pb"
+	"bank/utils"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -15,5 +17,133 @@ func convertUser(user db.User) *pb.User {
 		Email:             user.Email,
 		PasswordChangedAt: timestamppb.New(user.PasswordChangedAt),
 		CreatedAt:         timestamppb.New(user.CreatedAt),
+		Locale:            user.Locale,
+		IsVerified:        user.IsVerified.Bool,
 	}
 }
+
+func convertAccount(account db.Account) *pb.Account {
+	return &pb.Account{
+		Id:               account.ID,
+		Owner:            account.Owner,
+		Balance:          account.Balance,
+		Currency:         account.Currency,
+		CreatedAt:        timestamppb.New(account.CreatedAt),
+		UserId:           account.UserID,
+		FormattedBalance: utils.FormatAmount(account.Balance, account.Currency),
+		Status:           account.Status,
+		StatusReason:     account.StatusReason,
+		StatusChangedAt:  timestamppb.New(account.StatusChangedAt),
+	}
+}
+
+func convertTransfer(result db.TransferTxResult) *pb.Transfer {
+	transfer := convertTransferRecord(result.Transfer)
+	transfer.FromAccountBalance = result.FromAccount.Balance
+	transfer.ToAccountBalance = result.ToAccount.Balance
+	transfer.FromEntryId = result.FromEntry.ID
+	transfer.ToEntryId = result.ToEntry.ID
+	return transfer
+}
+
+func convertTransferRecord(transfer db.Transfer) *pb.Transfer {
+	return &pb.Transfer{
+		Id:            transfer.ID,
+		FromAccountId: transfer.FromAccountID,
+		ToAccountId:   transfer.ToAccountID,
+		Amount:        transfer.Amount,
+		ToAmount:      transfer.ToAmount,
+		ExchangeRate:  transfer.ExchangeRate,
+		CreatedAt:     timestamppb.New(transfer.CreatedAt),
+	}
+}
+
+func convertStatementLine(line db.StatementLine) *pb.StatementLine {
+	return &pb.StatementLine{
+		EntryId:               line.ID,
+		Amount:                line.Amount,
+		Balance:               line.Balance,
+		TransferId:            line.TransferID.Int64,
+		CounterpartyAccountId: line.CounterpartyAccountID,
+		CreatedAt:             timestamppb.New(line.CreatedAt),
+	}
+}
+
+func convertEntry(entry db.Entry) *pb.Entry {
+	return &pb.Entry{
+		Id:         entry.ID,
+		AccountId:  entry.AccountID,
+		Amount:     entry.Amount,
+		TransferId: entry.TransferID.Int64,
+		CreatedAt:  timestamppb.New(entry.CreatedAt),
+	}
+}
+
+func convertCurrency(currency utils.Currency) *pb.Currency {
+	return &pb.Currency{
+		Code:        currency.Code,
+		NumericCode: currency.NumericCode,
+		Exponent:    currency.Exponent,
+		Symbol:      currency.Symbol,
+	}
+}
+
+func convertSession(session db.ListActiveSessionsRow) *pb.Session {
+	return &pb.Session{
+		Id:        session.FamilyID.String(),
+		UserAgent: session.UserAgent,
+		ClientIp:  session.ClientIp,
+		CreatedAt: timestamppb.New(session.LoggedInAt),
+		ExpiresAt: timestamppb.New(session.ExpiresAt),
+	}
+}
+
+func convertLoginLockout(lockout db.LoginLockout) *pb.LoginLockout {
+	converted := &pb.LoginLockout{
+		Id:             lockout.ID,
+		Email:          lockout.Email,
+		ClientIp:       lockout.ClientIp,
+		FailedAttempts: lockout.FailedAttempts,
+		LockedUntil:    timestamppb.New(lockout.LockedUntil),
+		CreatedAt:      timestamppb.New(lockout.CreatedAt),
+	}
+	if lockout.UserID.Valid {
+		converted.UserId = &lockout.UserID.Int64
+	}
+	if lockout.UnlockedAt.Valid {
+		converted.UnlockedAt = timestamppb.New(lockout.UnlockedAt.Time)
+	}
+	if lockout.UnlockedBy.Valid {
+		converted.UnlockedBy = &lockout.UnlockedBy.Int64
+	}
+	return converted
+}
+
+func convertNotification(notification db.Notification) *pb.Notification {
+	converted := &pb.Notification{
+		Id:                    notification.ID,
+		Event:                 notification.Event,
+		TransferId:            notification.TransferID.Int64,
+		AccountId:             notification.AccountID,
+		CounterpartyAccountId: notification.CounterpartyAccountID,
+		Amount:                notification.Amount,
+		Currency:              notification.Currency,
+		CreatedAt:             timestamppb.New(notification.CreatedAt),
+	}
+	if notification.ReadAt.Valid {
+		converted.ReadAt = timestamppb.New(notification.ReadAt.Time)
+	}
+	return converted
+}
+
+func convertNotificationPreferences(preferences []notify.Preference) []*pb.NotificationPreference {
+	converted := make([]*pb.NotificationPreference, 0, len(preferences))
+	for _, preference := range preferences {
+		converted = append(converted, &pb.NotificationPreference{
+			Event: preference.Event,
+			InApp: preference.InApp,
+			Email: preference.Email,
+		})
+	}
+	return converted
+}
@@ -1,6 +1,13 @@
 package gapi
 
 import (
+	db "bank/db/sqlc"
+	"bank/fx"
+	"bank/lockout"
+	"bank/mfa"
+	"errors"
+	"log"
+
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -27,3 +34,68 @@ func validationError(violations []*errdetails.BadRequest_FieldViolation) error {
 func unauthenticatedError(err error) error {
 	return status.Errorf(codes.Unauthenticated, "unauthenticated user: %s", err)
 }
+
+// transferError maps the domain errors of db.TransferTx to gRPC statuses,
+// so that clients can tell their own mistakes from server failures.
+func transferError(err error) error {
+	// the errors of BeforeTransfer are statuses already
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, db.ErrInsufficientFunds), errors.Is(err, db.ErrIdempotencyKeyReused),
+		errors.Is(err, db.ErrAccountFrozen), errors.Is(err, db.ErrAccountClosed):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, db.ErrAccountNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, db.ErrSameAccount):
+		return validationError([]*errdetails.BadRequest_FieldViolation{fieldViolation("to_account_id", err)})
+	case errors.Is(err, db.ErrCurrencyMismatch):
+		return validationError([]*errdetails.BadRequest_FieldViolation{fieldViolation("currency", err)})
+	case errors.Is(err, db.ErrAmountTooSmall):
+		return validationError([]*errdetails.BadRequest_FieldViolation{fieldViolation("amount", err)})
+	}
+
+	log.Println(err)
+	return status.Errorf(codes.Internal, "failed to transfer: %s", err.Error())
+}
+
+// fxRateError maps the errors of fx.FXRateProvider to gRPC statuses.
+func fxRateError(err error) error {
+	switch {
+	case errors.Is(err, fx.ErrUnsupportedPair):
+		return validationError([]*errdetails.BadRequest_FieldViolation{fieldViolation("to_account_id", err)})
+	case errors.Is(err, fx.ErrRateNotFound):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	log.Println(err)
+	return status.Errorf(codes.Internal, "failed to get exchange rate: %s", err.Error())
+}
+
+// mfaError maps the errors of the second factor check to gRPC statuses.
+func mfaError(err error) error {
+	switch {
+	case errors.Is(err, mfa.ErrInvalidCode):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, mfa.ErrNotEnrolled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, db.ErrTOTPAlreadyEnabled):
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+
+	log.Println(err)
+	return status.Errorf(codes.Internal, "failed to check two-factor code: %s", err.Error())
+}
+
+// loginAttemptError maps the errors of lockout.Guard to gRPC statuses.
+func loginAttemptError(err error) error {
+	var attemptErr *lockout.AttemptError
+	if errors.As(err, &attemptErr) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	log.Println(err)
+	return status.Errorf(codes.Internal, "failed to check login attempts: %s", err.Error())
+}
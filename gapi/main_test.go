@@ -3,13 +3,18 @@ package gapi
 import (
 	"bank/async"
 	db "bank/db/sqlc"
+	"bank/rbac"
+	"bank/token"
 	"bank/utils"
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -17,11 +22,76 @@ func newTestServer(t *testing.T, store db.Store, taskDistributor async.TaskDistr
 	srv, err := NewServer(utils.Config{
 		TokenSymmetricKey:   utils.RandomString(32),
 		AccessTokenDuration: time.Minute,
-	}, store, taskDistributor)
+	}, store, taskDistributor, token.NewRevocationChecker(notRevokedStore{}, 0))
 	require.NoError(t, err)
+	srv.authorizer = rbac.NewAuthorizer(testPermissionStore{}, 0)
 	return srv
 }
 
+// testPermissionStore grants the roles the permissions the migrations seed them with.
+type testPermissionStore struct{}
+
+func (testPermissionStore) ListRolePermissions(ctx context.Context, role string) ([]string, error) {
+	own := []string{
+		string(rbac.AccountsCreateOwn), string(rbac.AccountsReadOwn), string(rbac.TransfersCreateOwn),
+		string(rbac.TransfersReadOwn), string(rbac.UsersUpdateOwn), string(rbac.SessionsManageOwn), string(rbac.MFAManageOwn),
+		string(rbac.NotificationsManageOwn),
+	}
+	switch utils.Role(role) {
+	case utils.Depositor:
+		return own, nil
+	case utils.Banker:
+		return append(own,
+			string(rbac.AccountsReadAny), string(rbac.AccountsUpdateAny), string(rbac.TransfersReadAny), string(rbac.UsersUpdateAny),
+			string(rbac.UsersUnlockAny), string(rbac.SessionsRevokeAny), string(rbac.LockoutsReadAny),
+		), nil
+	}
+	return nil, nil
+}
+
+// callAuthorized calls the handler behind AuthInterceptor, the way the gRPC server does.
+func callAuthorized[Req, Res any](
+	ctx context.Context,
+	server *Server,
+	method string,
+	handler func(context.Context, Req) (Res, error),
+	req Req,
+) (Res, error) {
+	res, err := server.AuthInterceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+		return handler(ctx, req.(Req))
+	})
+	if err != nil {
+		var empty Res
+		return empty, err
+	}
+	return res.(Res), nil
+}
+
+// notRevokedStore lets every token bound to a test session pass the revocation check,
+// so that the tests of the handlers only stub the queries of the handlers.
+type notRevokedStore struct{}
+
+// testSessions maps the sessions of the test tokens onto their users.
+var testSessions sync.Map
+
+func newTestSession(userID int64) uuid.UUID {
+	sessionID := uuid.New()
+	testSessions.Store(sessionID, userID)
+	return sessionID
+}
+
+func (notRevokedStore) GetUser(ctx context.Context, id int64) (db.User, error) {
+	return db.User{ID: id}, nil
+}
+
+func (notRevokedStore) GetSession(ctx context.Context, id uuid.UUID) (db.Session, error) {
+	userID, ok := testSessions.Load(id)
+	if !ok {
+		return db.Session{}, db.ErrRecordNotFound
+	}
+	return db.Session{ID: id, UserID: userID.(int64)}, nil
+}
+
 func newContextWithAuthMetadata(
 	t *testing.T,
 	server *Server,
@@ -30,7 +100,7 @@ func newContextWithAuthMetadata(
 	authHeader,
 	authType string,
 ) context.Context {
-	token, _, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), duration)
+	token, _, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), token.TokenTypeAccess, newTestSession(user.ID), duration)
 	require.NoError(t, err)
 	return metadata.NewIncomingContext(
 		context.Background(),
@@ -1,8 +1,14 @@
 package gapi
 
 import (
+	db "bank/db/sqlc"
+	"bank/validation"
 	"context"
+	"net"
+	"strings"
+	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
@@ -16,13 +22,15 @@ const (
 	userAgentHeader     = "grpcgateway-user-agent"
 	userAgentHeaderGRPC = "user-agent"
 	clientIPHeader      = "x-forwarded-for"
+	IdempotencyHeader   = "idempotency-key"
 )
 
 func (server *Server) extractMedadata(ctx context.Context) *Metadata {
 	mtdt := &Metadata{}
+	forwardedFor := ""
 	if meta, ok := metadata.FromIncomingContext(ctx); ok {
 		if clientIPs := meta.Get(clientIPHeader); len(clientIPs) > 0 {
-			mtdt.ClientIP = clientIPs[0]
+			forwardedFor = strings.TrimSpace(strings.Split(clientIPs[0], ",")[0])
 		}
 		if userAgents := meta.Get(userAgentHeader); len(userAgents) > 0 {
 			mtdt.UserAgent = userAgents[0]
@@ -31,9 +39,48 @@ func (server *Server) extractMedadata(ctx context.Context) *Metadata {
 		}
 	}
 
+	mtdt.ClientIP = forwardedFor
 	if p, ok := peer.FromContext(ctx); ok {
 		mtdt.ClientIP = p.Addr.String()
+		// the HTTP gateway calls in over loopback and forwards the address of its client;
+		// the header is not trusted from anybody else
+		if forwardedFor != "" && isLoopback(p.Addr) {
+			mtdt.ClientIP = forwardedFor
+		}
 	}
 
 	return mtdt
 }
+
+func isLoopback(addr net.Addr) bool {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// extractIdempotency reads the optional idempotency key sent by the client.
+// It returns nil params when the key is absent.
+func (server *Server) extractIdempotency(ctx context.Context, userID int64) (*db.IdempotencyParams, error) {
+	meta, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	keys := meta.Get(IdempotencyHeader)
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	if valErr := validation.ValidateIdempotencyKey(keys[0]); valErr != nil {
+		return nil, validationError([]*errdetails.BadRequest_FieldViolation{fieldViolation(valErr.Field, valErr.Error)})
+	}
+
+	return &db.IdempotencyParams{
+		UserID:    userID,
+		Key:       keys[0],
+		ExpiresAt: time.Now().Add(server.config.IdempotencyKeyTTL),
+	}, nil
+}
@@ -0,0 +1,29 @@
+package gapi
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// PasswordResetPagePath serves the page the emailed reset link opens by default, see PASSWORD_RESET_URL.
+const PasswordResetPagePath = "/reset_password"
+
+//go:embed pages/reset_password.html
+var passwordResetPage []byte
+
+// PasswordResetPageHandler serves a page that asks for the new password and posts it
+// to ResetPassword along with the id and the code of the link.
+func PasswordResetPageHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		// the code is in the URL, so it must not be cached or leak to other sites
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Write(passwordResetPage)
+	})
+}
@@ -0,0 +1,25 @@
+package gapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordResetPageHandler(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, PasswordResetPagePath+"?id=1&code=abc", nil)
+	PasswordResetPageHandler().ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+	require.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+	require.Contains(t, recorder.Body.String(), `fetch("/v1/reset_password"`)
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodPost, PasswordResetPagePath, nil)
+	PasswordResetPageHandler().ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
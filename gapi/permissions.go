@@ -0,0 +1,78 @@
+package gapi
+
+import (
+	"bank/pb"
+	"bank/rbac"
+	"bank/token"
+	"context"
+	"errors"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// methodPermissions lists the permissions that let a caller into each method; one of them is enough.
+// The handlers narrow "own" callers down to their own resources. A method missing here
+// is closed to everyone unless it is public, see publicMethods.
+var methodPermissions = map[string][]rbac.Permission{
+	pb.Bank_UpdateUser_FullMethodName:                    {rbac.UsersUpdateOwn, rbac.UsersUpdateAny},
+	pb.Bank_CreateAccount_FullMethodName:                 {rbac.AccountsCreateOwn},
+	pb.Bank_GetAccount_FullMethodName:                    {rbac.AccountsReadOwn, rbac.AccountsReadAny},
+	pb.Bank_ListAccounts_FullMethodName:                  {rbac.AccountsReadOwn, rbac.AccountsReadAny},
+	pb.Bank_CreateTransfer_FullMethodName:                {rbac.TransfersCreateOwn},
+	pb.Bank_GetAccountStatement_FullMethodName:           {rbac.AccountsReadOwn, rbac.AccountsReadAny},
+	pb.Bank_ListEntries_FullMethodName:                   {rbac.AccountsReadOwn, rbac.AccountsReadAny},
+	pb.Bank_ListTransfers_FullMethodName:                 {rbac.TransfersReadOwn, rbac.TransfersReadAny},
+	pb.Bank_FreezeAccount_FullMethodName:                 {rbac.AccountsUpdateAny},
+	pb.Bank_UnfreezeAccount_FullMethodName:               {rbac.AccountsUpdateAny},
+	pb.Bank_CloseAccount_FullMethodName:                  {rbac.AccountsUpdateAny},
+	pb.Bank_ListSessions_FullMethodName:                  {rbac.SessionsManageOwn},
+	pb.Bank_RevokeSession_FullMethodName:                 {rbac.SessionsManageOwn},
+	pb.Bank_RevokeOtherSessions_FullMethodName:           {rbac.SessionsManageOwn},
+	pb.Bank_LogoutUser_FullMethodName:                    {rbac.SessionsManageOwn},
+	pb.Bank_RevokeUserSessions_FullMethodName:            {rbac.SessionsRevokeAny},
+	pb.Bank_EnrolTOTP_FullMethodName:                     {rbac.MFAManageOwn},
+	pb.Bank_ConfirmTOTP_FullMethodName:                   {rbac.MFAManageOwn},
+	pb.Bank_DisableTOTP_FullMethodName:                   {rbac.MFAManageOwn},
+	pb.Bank_ListLoginLockouts_FullMethodName:             {rbac.LockoutsReadAny},
+	pb.Bank_UnlockUser_FullMethodName:                    {rbac.UsersUnlockAny},
+	pb.Bank_ListNotifications_FullMethodName:             {rbac.NotificationsManageOwn},
+	pb.Bank_MarkNotificationsRead_FullMethodName:         {rbac.NotificationsManageOwn},
+	pb.Bank_GetNotificationPreferences_FullMethodName:    {rbac.NotificationsManageOwn},
+	pb.Bank_UpdateNotificationPreferences_FullMethodName: {rbac.NotificationsManageOwn},
+	pb.Bank_ResendVerificationEmail_FullMethodName:       {rbac.UsersUpdateOwn},
+}
+
+// authorizeMethod turns away the callers whose role holds none of the permissions of the method.
+func (server *Server) authorizeMethod(ctx context.Context, authPayload *token.Payload, method string) error {
+	required, isMapped := methodPermissions[method]
+	if !isMapped {
+		return status.Errorf(codes.PermissionDenied, "method %s is not open to any role", method)
+	}
+
+	if err := server.authorizer.RequireAny(ctx, authPayload.Role, required); err != nil {
+		return permissionError(err)
+	}
+	return nil
+}
+
+// permissionError maps the errors of rbac.Authorizer to gRPC statuses.
+func permissionError(err error) error {
+	if errors.Is(err, rbac.ErrPermissionDenied) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	log.Println(err)
+	return status.Errorf(codes.Internal, "failed to check permissions: %s", err.Error())
+}
+
+// hasPermission tells whether the role of the caller holds the permission, typically the "any" scope
+// of an action. The returned error is already a gRPC status.
+func (server *Server) hasPermission(ctx context.Context, authPayload *token.Payload, permission rbac.Permission) (bool, error) {
+	has, err := server.authorizer.Has(ctx, authPayload.Role, permission)
+	if err != nil {
+		return false, permissionError(err)
+	}
+	return has, nil
+}
@@ -0,0 +1,38 @@
+package gapi
+
+import (
+	"bank/pb"
+	"bank/token"
+	"bank/utils"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMethodPermissionsCoverService(t *testing.T) {
+	methodNames := make([]string, 0, len(pb.Bank_ServiceDesc.Methods)+len(pb.Bank_ServiceDesc.Streams))
+	for _, method := range pb.Bank_ServiceDesc.Methods {
+		methodNames = append(methodNames, method.MethodName)
+	}
+	for _, stream := range pb.Bank_ServiceDesc.Streams {
+		methodNames = append(methodNames, stream.StreamName)
+	}
+
+	for _, methodName := range methodNames {
+		fullMethod := "/" + pb.Bank_ServiceDesc.ServiceName + "/" + methodName
+		_, isProtected := methodPermissions[fullMethod]
+		require.True(t, isProtected || publicMethods[fullMethod], "%s is neither protected nor public", fullMethod)
+	}
+}
+
+func TestAuthorizeMethodUnmapped(t *testing.T) {
+	server := newTestServer(t, nil, nil)
+
+	// not even a banker gets into a method nobody has mapped
+	payload := &token.Payload{UserID: 1, Role: utils.Banker}
+	err := server.authorizeMethod(context.Background(), payload, "/"+pb.Bank_ServiceDesc.ServiceName+"/Unmapped")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
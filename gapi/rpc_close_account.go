@@ -0,0 +1,16 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"context"
+)
+
+func (server *Server) CloseAccount(ctx context.Context, r *pb.CloseAccountRequest) (*pb.CloseAccountResponse, error) {
+	account, err := server.changeAccountStatus(ctx, r.GetAccountId(), db.AccountStatusClosed, r.GetReason())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CloseAccountResponse{Account: account}, nil
+}
@@ -0,0 +1,39 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCloseAccount(t *testing.T) {
+	banker := randomUser("password")
+	banker.Role = string(utils.Banker)
+	account := randomAccount(banker.ID)
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		UpdateAccountStatusTx(gomock.Any(), gomock.Eq(db.UpdateAccountStatusTxParams{
+			AccountID: account.ID,
+			Status:    db.AccountStatusClosed,
+			Reason:    "customer request",
+		})).
+		Times(1).
+		Return(db.UpdateAccountStatusTxResult{}, db.ErrAccountBalanceNotZero)
+
+	server := newTestServer(t, store, nil)
+	ctx := newContextWithAuthMetadata(t, server, banker, time.Minute, authHeader, authBearer)
+
+	_, err := callAuthorized(ctx, server, pb.Bank_CloseAccount_FullMethodName, server.CloseAccount, &pb.CloseAccountRequest{AccountId: account.ID, Reason: "customer request"})
+	require.Error(t, err)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -0,0 +1,28 @@
+package gapi
+
+import (
+	"bank/mfa"
+	"bank/pb"
+	"context"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) ConfirmTOTP(ctx context.Context, r *pb.ConfirmTOTPRequest) (*pb.ConfirmTOTPResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.GetCode() == "" {
+		return nil, validationError([]*errdetails.BadRequest_FieldViolation{
+			fieldViolation("code", fmt.Errorf("is required")),
+		})
+	}
+
+	if err = mfa.Confirm(ctx, server.store, authPayload.UserID, r.GetCode()); err != nil {
+		return nil, mfaError(err)
+	}
+
+	return &pb.ConfirmTOTPResponse{}, nil
+}
@@ -0,0 +1,75 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/validation"
+	"context"
+	"errors"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateAccount(ctx context.Context, r *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if violations := validateCreateAccountRequest(r); violations != nil {
+		return nil, validationError(violations)
+	}
+
+	idempotency, err := server.extractIdempotency(ctx, authPayload.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.CreateAccountParams{
+		Owner:    r.GetOwner(),
+		UserID:   authPayload.UserID,
+		Balance:  0,
+		Currency: r.GetCurrency(),
+	}
+
+	var account db.Account
+	if idempotency == nil {
+		account, err = server.store.CreateAccount(ctx, arg)
+	} else {
+		var result db.CreateAccountTxResult
+		result, err = server.store.CreateAccountTx(ctx, db.CreateAccountTxParams{
+			CreateAccountParams: arg,
+			Idempotency:         idempotency,
+		})
+		account = result.Account
+	}
+	if err != nil {
+		if errors.Is(err, db.ErrIdempotencyKeyReused) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == "23505" {
+			return nil, status.Errorf(codes.AlreadyExists, "account in %s already exists", r.GetCurrency())
+		}
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to create account: %s", err.Error())
+	}
+
+	return &pb.CreateAccountResponse{
+		Account: convertAccount(account),
+	}, nil
+}
+
+func validateCreateAccountRequest(r *pb.CreateAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if valErr := validation.ValidateOwner(r.GetOwner()); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+	if valErr := validation.ValidateCurrency(r.GetCurrency()); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+
+	return violations
+}
@@ -0,0 +1,144 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/utils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func randomAccount(userID int64) db.Account {
+	return db.Account{
+		ID:       utils.RandomInt(1, 1000),
+		Owner:    utils.RandomName(),
+		Balance:  utils.RandomMoney(),
+		Currency: utils.RandomCurrency(),
+		UserID:   userID,
+	}
+}
+
+func TestCreateAccount(t *testing.T) {
+	user := randomUser("password")
+	account := randomAccount(user.ID)
+	account.Balance = 0
+
+	testCases := []struct {
+		name          string
+		params        *pb.CreateAccountRequest
+		buildStubs    func(store *mockdb.MockStore)
+		makeContext   func(server *Server) context.Context
+		checkResponse func(t *testing.T, res *pb.CreateAccountResponse, err error)
+	}{
+		{
+			name: "OK",
+			params: &pb.CreateAccountRequest{
+				Owner:    account.Owner,
+				Currency: account.Currency,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				args := db.CreateAccountParams{
+					Owner:    account.Owner,
+					UserID:   user.ID,
+					Balance:  0,
+					Currency: account.Currency,
+				}
+
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Eq(args)).
+					Times(1).
+					Return(account, nil)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account.ID, res.Account.Id)
+				require.Equal(t, user.ID, res.Account.UserId)
+				require.Equal(t, account.Currency, res.Account.Currency)
+				require.Zero(t, res.Account.Balance)
+			},
+		},
+		{
+			name: "Unsupported currency",
+			params: &pb.CreateAccountRequest{
+				Owner:    account.Owner,
+				Currency: "XXX",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "Already exists",
+			params: &pb.CreateAccountRequest{
+				Owner:    account.Owner,
+				Currency: account.Currency,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Account{}, &pgconn.PgError{Code: "23505"})
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.AlreadyExists, status.Code(err))
+			},
+		},
+		{
+			name: "No auth",
+			params: &pb.CreateAccountRequest{
+				Owner:    account.Owner,
+				Currency: account.Currency,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ctrl := gomock.NewController(t)
+
+		store := mockdb.NewMockStore(ctrl)
+
+		tc.buildStubs(store)
+
+		server := newTestServer(t, store, nil)
+
+		res, err := callAuthorized(tc.makeContext(server), server, pb.Bank_CreateAccount_FullMethodName, server.CreateAccount, tc.params)
+
+		tc.checkResponse(t, res, err)
+	}
+}
@@ -0,0 +1,186 @@
+package gapi
+
+import (
+	"bank/async"
+	db "bank/db/sqlc"
+	"bank/mfa"
+	"bank/pb"
+	"bank/utils"
+	"bank/validation"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hibiken/asynq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateTransfer(ctx context.Context, r *pb.CreateTransferRequest) (*pb.CreateTransferResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if violations := validateCreateTransferRequest(r); violations != nil {
+		return nil, validationError(violations)
+	}
+
+	idempotency, err := server.extractIdempotency(ctx, authPayload.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromAccount, err := server.validAccount(ctx, r.GetFromAccountId(), r.GetCurrency(), "from_account_id")
+	if err != nil {
+		return nil, err
+	}
+
+	if fromAccount.UserID != authPayload.UserID {
+		return nil, status.Errorf(codes.PermissionDenied, "account %d doesn't belong to the user", fromAccount.ID)
+	}
+
+	if err = server.checkEmailVerified(ctx, authPayload.UserID); err != nil {
+		return nil, err
+	}
+
+	toAccount, err := server.fetchAccount(ctx, r.GetToAccountId())
+	if err != nil {
+		return nil, err
+	}
+
+	rate, err := server.fxRateProvider.GetRate(ctx, fromAccount.Currency, toAccount.Currency)
+	if err != nil {
+		return nil, fxRateError(err)
+	}
+
+	exponents, err := utils.CurrencyExponents(fromAccount.Currency, toAccount.Currency)
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to get currency: %s", err.Error())
+	}
+
+	result, err := server.store.TransferTx(ctx, db.TransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        r.GetAmount(),
+		ExchangeRate:  rate,
+		Exponents:     exponents,
+		Idempotency:   idempotency,
+		// the code is spent last and within the transaction, so a failed transfer or a replay doesn't burn it
+		BeforeTransfer: func(ctx context.Context) error {
+			return server.checkTransferMFA(ctx, authPayload.UserID, r.GetAmount(), r.TotpCode)
+		},
+		// ctx carries the transaction, so that the outbox distributor writes the task along with the transfer
+		AfterTransfer: func(ctx context.Context, transfer db.Transfer) error {
+			payload := &async.PayloadNotifyTransfer{TransferID: transfer.ID}
+			return server.taskDistributor.DistributeTaskNotifyTransfer(ctx, payload, asynq.MaxRetry(10))
+		},
+	})
+	if err != nil {
+		return nil, transferError(err)
+	}
+
+	return &pb.CreateTransferResponse{
+		Transfer: convertTransfer(result),
+	}, nil
+}
+
+// checkEmailVerified keeps users who haven't verified their email from sending money.
+// The returned error is already a gRPC status.
+func (server *Server) checkEmailVerified(ctx context.Context, userID int64) error {
+	user, err := server.store.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return status.Errorf(codes.NotFound, "user not found")
+		}
+		log.Println(err)
+		return status.Errorf(codes.Internal, "failed to get user: %s", err.Error())
+	}
+
+	if !user.IsVerified.Bool {
+		return status.Errorf(codes.FailedPrecondition, "email must be verified before sending transfers")
+	}
+
+	return nil
+}
+
+// checkTransferMFA asks users with two-factor authentication for a fresh code
+// when the amount reaches the configured threshold. It uses the transaction ctx carries, if any.
+// The returned error is already a gRPC status.
+func (server *Server) checkTransferMFA(ctx context.Context, userID int64, amount int64, code *string) error {
+	threshold := server.config.TransferMFAThreshold
+	if threshold <= 0 || amount < threshold {
+		return nil
+	}
+
+	querier := db.QuerierFromContext(ctx, server.store)
+	enabled, err := mfa.Enabled(ctx, querier, userID)
+	if err != nil {
+		return mfaError(err)
+	}
+	if !enabled {
+		return nil
+	}
+
+	if code == nil {
+		return status.Errorf(codes.PermissionDenied, "two-factor code is required for transfers of %d and more", threshold)
+	}
+
+	if err = mfa.Verify(ctx, querier, userID, *code); err != nil {
+		return mfaError(err)
+	}
+
+	return nil
+}
+
+// validAccount fetches the account and makes sure it holds the given currency.
+// The returned error is already a gRPC status.
+func (server *Server) validAccount(ctx context.Context, accountID int64, currency string, field string) (db.Account, error) {
+	account, err := server.fetchAccount(ctx, accountID)
+	if err != nil {
+		return account, err
+	}
+
+	if account.Currency != currency {
+		err = fmt.Errorf("account %d is in %s, not in %s", accountID, account.Currency, currency)
+		return account, validationError([]*errdetails.BadRequest_FieldViolation{fieldViolation(field, err)})
+	}
+
+	return account, nil
+}
+
+// fetchAccount fetches the account of any currency. The returned error is already a gRPC status.
+func (server *Server) fetchAccount(ctx context.Context, accountID int64) (db.Account, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return account, status.Errorf(codes.NotFound, "account %d not found", accountID)
+		}
+		log.Println(err)
+		return account, status.Errorf(codes.Internal, "failed to get account: %s", err.Error())
+	}
+
+	return account, nil
+}
+
+func validateCreateTransferRequest(r *pb.CreateTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if valErr := validation.ValidateID(r.GetFromAccountId(), "from_account_id"); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+	if valErr := validation.ValidateID(r.GetToAccountId(), "to_account_id"); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+	if r.GetFromAccountId() == r.GetToAccountId() {
+		violations = append(violations, fieldViolation("to_account_id", fmt.Errorf("must differ from from_account_id")))
+	}
+	if valErr := validation.ValidateCurrency(r.GetCurrency()); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+	if valErr := validation.ValidateAmount(r.GetAmount()); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+
+	return violations
+}
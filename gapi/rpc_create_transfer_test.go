@@ -0,0 +1,634 @@
+package gapi
+
+import (
+	"bank/async"
+	mockasync "bank/async/mock"
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/mfa"
+	"bank/pb"
+	"bank/utils"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func withIdempotencyKey(ctx context.Context, key string) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+	md.Set(IdempotencyHeader, key)
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+// eqTransferTxParams matches the tx params by the transfer, as the callbacks can't be compared.
+func eqTransferTxParams(args db.TransferTxParams) gomock.Matcher {
+	return gomock.Cond(func(x any) bool {
+		params, isOk := x.(db.TransferTxParams)
+		params.BeforeTransfer, params.AfterTransfer = nil, nil
+		return isOk && reflect.DeepEqual(params, args)
+	})
+}
+
+// runTransferHooks stands in for the transaction of db.TransferTx and runs its callbacks.
+func runTransferHooks(result db.TransferTxResult) func(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+	return func(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+		if arg.BeforeTransfer != nil {
+			if err := arg.BeforeTransfer(ctx); err != nil {
+				return db.TransferTxResult{}, err
+			}
+		}
+		if arg.AfterTransfer != nil {
+			if err := arg.AfterTransfer(ctx, result.Transfer); err != nil {
+				return db.TransferTxResult{}, err
+			}
+		}
+		return result, nil
+	}
+}
+
+func TestCreateTransfer(t *testing.T) {
+	user1 := randomUser("password")
+	user1.IsVerified = pgtype.Bool{Bool: true, Valid: true}
+	user2 := randomUser("password")
+	user2.ID = user1.ID + 1
+
+	acc1 := randomAccount(user1.ID)
+	acc2 := randomAccount(user2.ID)
+	acc3 := randomAccount(user2.ID)
+	acc2.ID = acc1.ID + 1
+	acc3.ID = acc1.ID + 2
+	acc1.Currency, acc2.Currency, acc3.Currency = utils.USD, utils.USD, utils.EUR
+
+	amount := int64(10)
+	idempotencyKey := utils.RandomString(16)
+
+	testCases := []struct {
+		name          string
+		params        *pb.CreateTransferRequest
+		buildStubs    func(store *mockdb.MockStore)
+		makeContext   func(server *Server) context.Context
+		checkResponse func(t *testing.T, res *pb.CreateTransferResponse, err error)
+	}{
+		{
+			name: "OK",
+			params: &pb.CreateTransferRequest{
+				FromAccountId: acc1.ID,
+				ToAccountId:   acc2.ID,
+				Currency:      utils.USD,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
+
+				args := db.TransferTxParams{
+					FromAccountID: acc1.ID,
+					ToAccountID:   acc2.ID,
+					Amount:        amount,
+					ExchangeRate:  1,
+					Exponents:     map[string]int32{utils.USD: 2},
+				}
+				result := db.TransferTxResult{
+					Transfer:    db.Transfer{ID: 1, FromAccountID: acc1.ID, ToAccountID: acc2.ID, Amount: amount},
+					FromAccount: db.Account{ID: acc1.ID, Balance: acc1.Balance - amount},
+					ToAccount:   db.Account{ID: acc2.ID, Balance: acc2.Balance + amount},
+					FromEntry:   db.Entry{ID: 1, AccountID: acc1.ID, Amount: -amount},
+					ToEntry:     db.Entry{ID: 2, AccountID: acc2.ID, Amount: amount},
+				}
+				store.EXPECT().TransferTx(gomock.Any(), eqTransferTxParams(args)).Times(1).Return(result, nil)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user1, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				transfer := res.GetTransfer()
+				require.Equal(t, acc1.ID, transfer.FromAccountId)
+				require.Equal(t, acc2.ID, transfer.ToAccountId)
+				require.Equal(t, amount, transfer.Amount)
+				require.Equal(t, acc1.Balance-amount, transfer.FromAccountBalance)
+				require.Equal(t, acc2.Balance+amount, transfer.ToAccountBalance)
+				require.Equal(t, int64(1), transfer.FromEntryId)
+				require.Equal(t, int64(2), transfer.ToEntryId)
+			},
+		},
+		{
+			name: "Idempotent retry",
+			params: &pb.CreateTransferRequest{
+				FromAccountId: acc1.ID,
+				ToAccountId:   acc2.ID,
+				Currency:      utils.USD,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
+
+				matcher := func(x any) bool {
+					params, isOk := x.(db.TransferTxParams)
+					return isOk && params.Idempotency != nil &&
+						params.Idempotency.Key == idempotencyKey &&
+						params.Idempotency.UserID == user1.ID
+				}
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Cond(matcher)).
+					Times(1).
+					Return(db.TransferTxResult{Transfer: db.Transfer{ID: 1}}, nil)
+			},
+			makeContext: func(server *Server) context.Context {
+				ctx := newContextWithAuthMetadata(t, server, user1, time.Minute, authHeader, authBearer)
+				return withIdempotencyKey(ctx, idempotencyKey)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(1), res.GetTransfer().Id)
+			},
+		},
+		{
+			name: "Idempotency key reused",
+			params: &pb.CreateTransferRequest{
+				FromAccountId: acc1.ID,
+				ToAccountId:   acc2.ID,
+				Currency:      utils.USD,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, db.ErrIdempotencyKeyReused)
+			},
+			makeContext: func(server *Server) context.Context {
+				ctx := newContextWithAuthMetadata(t, server, user1, time.Minute, authHeader, authBearer)
+				return withIdempotencyKey(ctx, idempotencyKey)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "Foreign source account",
+			params: &pb.CreateTransferRequest{
+				FromAccountId: acc1.ID,
+				ToAccountId:   acc2.ID,
+				Currency:      utils.USD,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user2, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "Cross currency",
+			params: &pb.CreateTransferRequest{
+				FromAccountId: acc1.ID,
+				ToAccountId:   acc3.ID,
+				Currency:      utils.USD,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc3.ID)).Times(1).Return(acc3, nil)
+				store.EXPECT().
+					GetFXRate(gomock.Any(), gomock.Eq(db.GetFXRateParams{BaseCurrency: utils.USD, QuoteCurrency: utils.EUR})).
+					Times(1).
+					Return(db.FxRate{BaseCurrency: utils.USD, QuoteCurrency: utils.EUR, Rate: 0.9}, nil)
+
+				args := db.TransferTxParams{
+					FromAccountID: acc1.ID,
+					ToAccountID:   acc3.ID,
+					Amount:        amount,
+					ExchangeRate:  0.9,
+					Exponents:     map[string]int32{utils.USD: 2, utils.EUR: 2},
+				}
+				result := db.TransferTxResult{
+					Transfer: db.Transfer{
+						ID:            1,
+						FromAccountID: acc1.ID,
+						ToAccountID:   acc3.ID,
+						Amount:        amount,
+						ToAmount:      9,
+						ExchangeRate:  0.9,
+					},
+				}
+				store.EXPECT().TransferTx(gomock.Any(), eqTransferTxParams(args)).Times(1).Return(result, nil)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user1, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				transfer := res.GetTransfer()
+				require.Equal(t, amount, transfer.Amount)
+				require.Equal(t, int64(9), transfer.ToAmount)
+				require.Equal(t, 0.9, transfer.ExchangeRate)
+			},
+		},
+		{
+			name: "Exchange rate not found",
+			params: &pb.CreateTransferRequest{
+				FromAccountId: acc1.ID,
+				ToAccountId:   acc3.ID,
+				Currency:      utils.USD,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc3.ID)).Times(1).Return(acc3, nil)
+				store.EXPECT().
+					GetFXRate(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(db.FxRate{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user1, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "Currency mismatch",
+			params: &pb.CreateTransferRequest{
+				FromAccountId: acc1.ID,
+				ToAccountId:   acc3.ID,
+				Currency:      utils.EUR,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc3.ID)).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user1, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				st := status.Convert(err)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+				require.Len(t, st.Details(), 1)
+				badRequest, isOk := st.Details()[0].(*errdetails.BadRequest)
+				require.True(t, isOk)
+				require.Equal(t, "from_account_id", badRequest.FieldViolations[0].Field)
+			},
+		},
+		{
+			name: "Negative amount",
+			params: &pb.CreateTransferRequest{
+				FromAccountId: acc1.ID,
+				ToAccountId:   acc2.ID,
+				Currency:      utils.USD,
+				Amount:        -amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user1, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "Account not found",
+			params: &pb.CreateTransferRequest{
+				FromAccountId: acc1.ID,
+				ToAccountId:   acc2.ID,
+				Currency:      utils.USD,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user1, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "Account removed during transfer",
+			params: &pb.CreateTransferRequest{
+				FromAccountId: acc1.ID,
+				ToAccountId:   acc2.ID,
+				Currency:      utils.USD,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrAccountNotFound)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user1, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "Insufficient funds",
+			params: &pb.CreateTransferRequest{
+				FromAccountId: acc1.ID,
+				ToAccountId:   acc2.ID,
+				Currency:      utils.USD,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrInsufficientFunds)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user1, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ctrl := gomock.NewController(t)
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user1.ID)).AnyTimes().Return(user1, nil)
+
+		tc.buildStubs(store)
+
+		distributor := mockasync.NewMockTaskDistributor(ctrl)
+		distributor.EXPECT().DistributeTaskNotifyTransfer(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+		server := newTestServer(t, store, distributor)
+
+		res, err := callAuthorized(tc.makeContext(server), server, pb.Bank_CreateTransfer_FullMethodName, server.CreateTransfer, tc.params)
+
+		tc.checkResponse(t, res, err)
+	}
+}
+
+func TestCreateTransferRequiresTOTP(t *testing.T) {
+	user := randomUser("password")
+	user.IsVerified = pgtype.Bool{Bool: true, Valid: true}
+	acc1 := randomAccount(user.ID)
+	acc2 := randomAccount(user.ID + 1)
+	acc2.ID = acc1.ID + 1
+	acc1.Currency, acc2.Currency = utils.USD, utils.USD
+
+	threshold := int64(1000)
+	secret, err := mfa.GenerateSecret()
+	require.NoError(t, err)
+	confirmed := db.UserTotp{UserID: user.ID, Secret: secret, ConfirmedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}
+
+	testCases := []struct {
+		name          string
+		amount        int64
+		makeCode      func(t *testing.T) *string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.CreateTransferResponse, err error)
+	}{
+		{
+			name:     "Below threshold",
+			amount:   threshold - 1,
+			makeCode: func(t *testing.T) *string { return nil },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(runTransferHooks(db.TransferTxResult{}))
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:     "Not enrolled",
+			amount:   threshold,
+			makeCode: func(t *testing.T) *string { return nil },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.UserTotp{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(runTransferHooks(db.TransferTxResult{}))
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:     "Code missing",
+			amount:   threshold,
+			makeCode: func(t *testing.T) *string { return nil },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(confirmed, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(runTransferHooks(db.TransferTxResult{}))
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name:   "Code reused",
+			amount: threshold,
+			makeCode: func(t *testing.T) *string {
+				code, err := mfa.Code(secret, time.Now())
+				require.NoError(t, err)
+				return &code
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(user.ID)).Times(2).Return(confirmed, nil)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(runTransferHooks(db.TransferTxResult{}))
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name:   "Transfer fails",
+			amount: threshold,
+			makeCode: func(t *testing.T) *string {
+				code, err := mfa.Code(secret, time.Now())
+				require.NoError(t, err)
+				return &code
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				// the transaction fails before it asks for the code, so the code is still unused
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name:   "Valid code",
+			amount: threshold,
+			makeCode: func(t *testing.T) *string {
+				code, err := mfa.Code(secret, time.Now())
+				require.NoError(t, err)
+				return &code
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(user.ID)).Times(2).Return(confirmed, nil)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(runTransferHooks(db.TransferTxResult{}))
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ctrl := gomock.NewController(t)
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+		store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+
+		tc.buildStubs(store)
+
+		distributor := mockasync.NewMockTaskDistributor(ctrl)
+		distributor.EXPECT().DistributeTaskNotifyTransfer(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+		server := newTestServer(t, store, distributor)
+		server.config.TransferMFAThreshold = threshold
+
+		ctx := newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+		res, err := callAuthorized(ctx, server, pb.Bank_CreateTransfer_FullMethodName, server.CreateTransfer, &pb.CreateTransferRequest{
+			FromAccountId: acc1.ID,
+			ToAccountId:   acc2.ID,
+			Currency:      utils.USD,
+			Amount:        tc.amount,
+			TotpCode:      tc.makeCode(t),
+		})
+
+		tc.checkResponse(t, res, err)
+	}
+}
+
+func TestCreateTransferNotifies(t *testing.T) {
+	user1 := randomUser("password")
+	user1.IsVerified = pgtype.Bool{Bool: true, Valid: true}
+	user2 := randomUser("password")
+	acc1 := randomAccount(user1.ID)
+	acc2 := randomAccount(user2.ID)
+	acc2.ID = acc1.ID + 1
+	acc1.Currency, acc2.Currency = utils.USD, utils.USD
+	transfer := db.Transfer{ID: utils.RandomInt(1, 1000), FromAccountID: acc1.ID, ToAccountID: acc2.ID, Amount: 10}
+
+	testCases := []struct {
+		name          string
+		distributeErr error
+		checkResponse func(t *testing.T, res *pb.CreateTransferResponse, err error)
+	}{
+		{
+			name: "Enqueued",
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, transfer.ID, res.Transfer.Id)
+			},
+		},
+		{
+			// the task is written within the transfer transaction, so the transfer is rolled back with it
+			name:          "Outbox down",
+			distributeErr: errors.New("outbox is down"),
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc2.ID)).Times(1).Return(acc2, nil)
+			store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(runTransferHooks(db.TransferTxResult{Transfer: transfer}))
+
+			distributor := mockasync.NewMockTaskDistributor(ctrl)
+			distributor.EXPECT().
+				DistributeTaskNotifyTransfer(gomock.Any(), gomock.Eq(&async.PayloadNotifyTransfer{TransferID: transfer.ID}), gomock.Any()).
+				Times(1).
+				Return(tc.distributeErr)
+
+			server := newTestServer(t, store, distributor)
+			ctx := newContextWithAuthMetadata(t, server, user1, time.Minute, authHeader, authBearer)
+			res, err := callAuthorized(ctx, server, pb.Bank_CreateTransfer_FullMethodName, server.CreateTransfer, &pb.CreateTransferRequest{
+				FromAccountId: acc1.ID,
+				ToAccountId:   acc2.ID,
+				Currency:      utils.USD,
+				Amount:        transfer.Amount,
+			})
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestCreateTransferRequiresVerifiedEmail(t *testing.T) {
+	user := randomUser("password")
+	acc1 := randomAccount(user.ID)
+	acc2 := randomAccount(user.ID + 1)
+	acc2.ID = acc1.ID + 1
+	acc1.Currency, acc2.Currency = utils.USD, utils.USD
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc1.ID)).Times(1).Return(acc1, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store, nil)
+	ctx := newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+	res, err := callAuthorized(ctx, server, pb.Bank_CreateTransfer_FullMethodName, server.CreateTransfer, &pb.CreateTransferRequest{
+		FromAccountId: acc1.ID,
+		ToAccountId:   acc2.ID,
+		Currency:      utils.USD,
+		Amount:        10,
+	})
+	require.Nil(t, res)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
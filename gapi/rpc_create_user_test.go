@@ -59,7 +59,7 @@ func TestCreateUser(t *testing.T) {
 						if err := utils.CompareHashAndPassword(params.HashedPassword, password); err != nil {
 							return false
 						}
-						if err := params.AfterCreate(user); err != nil {
+						if err := params.AfterCreate(context.Background(), user); err != nil {
 							return false
 						}
 
@@ -125,7 +125,7 @@ func TestCreateUser(t *testing.T) {
 							return false
 						}
 
-						params.AfterCreate(user)
+						params.AfterCreate(context.Background(), user)
 
 						return params.Username == args.Username &&
 							params.FullName == args.FullName &&
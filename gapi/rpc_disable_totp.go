@@ -0,0 +1,36 @@
+package gapi
+
+import (
+	"bank/mfa"
+	"bank/pb"
+	"context"
+	"fmt"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) DisableTOTP(ctx context.Context, r *pb.DisableTOTPRequest) (*pb.DisableTOTPResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.GetCode() == "" {
+		return nil, validationError([]*errdetails.BadRequest_FieldViolation{
+			fieldViolation("code", fmt.Errorf("is required")),
+		})
+	}
+
+	if err = mfa.Verify(ctx, server.store, authPayload.UserID, r.GetCode()); err != nil {
+		return nil, mfaError(err)
+	}
+
+	if err = server.store.DisableTOTPTx(ctx, authPayload.UserID); err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to disable two-factor authentication: %s", err.Error())
+	}
+
+	return &pb.DisableTOTPResponse{}, nil
+}
@@ -0,0 +1,58 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/mfa"
+	"bank/pb"
+	"context"
+	"errors"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) EnrolTOTP(ctx context.Context, r *pb.EnrolTOTPRequest) (*pb.EnrolTOTPResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := server.store.GetUser(ctx, authPayload.UserID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err.Error())
+	}
+
+	secret, err := mfa.GenerateSecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate secret: %s", err.Error())
+	}
+
+	recoveryCodes, err := mfa.GenerateRecoveryCodes(mfa.RecoveryCodeCount)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate recovery codes: %s", err.Error())
+	}
+	hashes := make([]string, len(recoveryCodes))
+	for i, code := range recoveryCodes {
+		hashes[i] = mfa.HashRecoveryCode(code)
+	}
+
+	_, err = server.store.EnrolTOTPTx(ctx, db.EnrolTOTPTxParams{
+		UserID:             user.ID,
+		Secret:             secret,
+		RecoveryCodeHashes: hashes,
+	})
+	if err != nil {
+		return nil, mfaError(err)
+	}
+
+	return &pb.EnrolTOTPResponse{
+		Secret:          secret,
+		ProvisioningUri: mfa.ProvisioningURI(server.config.TOTPIssuer, user.Email, secret),
+		RecoveryCodes:   recoveryCodes,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/mfa"
+	"bank/pb"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEnrolTOTP(t *testing.T) {
+	user := randomUser("password")
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.EnrolTOTPResponse, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					EnrolTOTPTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.EnrolTOTPTxParams) (db.EnrolTOTPTxResult, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Len(t, arg.RecoveryCodeHashes, mfa.RecoveryCodeCount)
+						return db.EnrolTOTPTxResult{TOTP: db.UserTotp{UserID: user.ID, Secret: arg.Secret}}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.EnrolTOTPResponse, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, res.GetSecret())
+				require.Contains(t, res.GetProvisioningUri(), res.GetSecret())
+				require.Len(t, res.GetRecoveryCodes(), mfa.RecoveryCodeCount)
+			},
+		},
+		{
+			name: "Already enabled",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().EnrolTOTPTx(gomock.Any(), gomock.Any()).Times(1).Return(db.EnrolTOTPTxResult{}, db.ErrTOTPAlreadyEnabled)
+			},
+			checkResponse: func(t *testing.T, res *pb.EnrolTOTPResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.AlreadyExists, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			res, err := callAuthorized(ctx, server, pb.Bank_EnrolTOTP_FullMethodName, server.EnrolTOTP, &pb.EnrolTOTPRequest{})
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"context"
+)
+
+func (server *Server) FreezeAccount(ctx context.Context, r *pb.FreezeAccountRequest) (*pb.FreezeAccountResponse, error) {
+	account, err := server.changeAccountStatus(ctx, r.GetAccountId(), db.AccountStatusFrozen, r.GetReason())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.FreezeAccountResponse{Account: account}, nil
+}
@@ -0,0 +1,158 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/utils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFreezeAccount(t *testing.T) {
+	user := randomUser("password")
+	banker := randomUser("password")
+	banker.Role = string(utils.Banker)
+	account := randomAccount(user.ID)
+	reason := "suspicious activity"
+
+	testCases := []struct {
+		name          string
+		params        *pb.FreezeAccountRequest
+		buildStubs    func(store *mockdb.MockStore)
+		makeContext   func(server *Server) context.Context
+		checkResponse func(t *testing.T, res *pb.FreezeAccountResponse, err error)
+	}{
+		{
+			name:   "OK",
+			params: &pb.FreezeAccountRequest{AccountId: account.ID, Reason: reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateAccountStatusTxParams{
+					AccountID: account.ID,
+					Status:    db.AccountStatusFrozen,
+					Reason:    reason,
+				}
+				frozen := account
+				frozen.Status = db.AccountStatusFrozen
+				frozen.StatusReason = reason
+				store.EXPECT().
+					UpdateAccountStatusTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.UpdateAccountStatusTxResult{Account: frozen}, nil)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, banker, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.FreezeAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account.ID, res.Account.Id)
+				require.Equal(t, db.AccountStatusFrozen, res.Account.Status)
+				require.Equal(t, reason, res.Account.StatusReason)
+			},
+		},
+		{
+			name:   "Depositor",
+			params: &pb.FreezeAccountRequest{AccountId: account.ID, Reason: reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateAccountStatusTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.FreezeAccountResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name:   "Missing reason",
+			params: &pb.FreezeAccountRequest{AccountId: account.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateAccountStatusTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, banker, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.FreezeAccountResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name:   "Not found",
+			params: &pb.FreezeAccountRequest{AccountId: account.ID, Reason: reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateAccountStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateAccountStatusTxResult{}, db.ErrAccountNotFound)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, banker, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.FreezeAccountResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name:   "Already closed",
+			params: &pb.FreezeAccountRequest{AccountId: account.ID, Reason: reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateAccountStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateAccountStatusTxResult{}, db.ErrInvalidStatusTransition)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, banker, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.FreezeAccountResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name:   "Internal error",
+			params: &pb.FreezeAccountRequest{AccountId: account.ID, Reason: reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateAccountStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateAccountStatusTxResult{}, sql.ErrConnDone)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, banker, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.FreezeAccountResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ctrl := gomock.NewController(t)
+
+		store := mockdb.NewMockStore(ctrl)
+
+		tc.buildStubs(store)
+
+		server := newTestServer(t, store, nil)
+
+		res, err := callAuthorized(tc.makeContext(server), server, pb.Bank_FreezeAccount_FullMethodName, server.FreezeAccount, tc.params)
+
+		tc.checkResponse(t, res, err)
+	}
+}
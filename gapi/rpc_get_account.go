@@ -0,0 +1,59 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/rbac"
+	"bank/validation"
+	"context"
+	"errors"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetAccount(ctx context.Context, r *pb.GetAccountRequest) (*pb.GetAccountResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if violations := validateGetAccountRequest(r); violations != nil {
+		return nil, validationError(violations)
+	}
+
+	readAny, err := server.hasPermission(ctx, authPayload, rbac.AccountsReadAny)
+	if err != nil {
+		return nil, err
+	}
+
+	var account db.Account
+	if readAny {
+		account, err = server.store.GetAccount(ctx, r.GetId())
+	} else {
+		account, err = server.store.GetUserAccount(ctx, db.GetUserAccountParams{
+			UserID: authPayload.UserID,
+			ID:     r.GetId(),
+		})
+	}
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err.Error())
+	}
+
+	return &pb.GetAccountResponse{
+		Account: convertAccount(account),
+	}, nil
+}
+
+func validateGetAccountRequest(r *pb.GetAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if valErr := validation.ValidateID(r.GetId(), "id"); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+
+	return violations
+}
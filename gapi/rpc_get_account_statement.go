@@ -0,0 +1,83 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/rbac"
+	"bank/validation"
+	"context"
+	"errors"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetAccountStatement(ctx context.Context, r *pb.GetAccountStatementRequest) (*pb.GetAccountStatementResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if violations := validateGetAccountStatementRequest(r); violations != nil {
+		return nil, validationError(violations)
+	}
+
+	readAny, err := server.hasPermission(ctx, authPayload, rbac.AccountsReadAny)
+	if err != nil {
+		return nil, err
+	}
+	if !readAny {
+		_, err = server.store.GetUserAccount(ctx, db.GetUserAccountParams{
+			UserID: authPayload.UserID,
+			ID:     r.GetAccountId(),
+		})
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return nil, status.Errorf(codes.NotFound, "account not found")
+			}
+			log.Println(err)
+			return nil, status.Errorf(codes.Internal, "failed to get account: %s", err.Error())
+		}
+	}
+
+	statement, err := server.store.GetAccountStatementTx(ctx, db.GetAccountStatementTxParams{
+		AccountID: r.GetAccountId(),
+		From:      r.GetFrom().AsTime(),
+		To:        r.GetTo().AsTime(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrAccountNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to get account statement: %s", err.Error())
+	}
+
+	response := &pb.GetAccountStatementResponse{
+		Account:        convertAccount(statement.Account),
+		From:           r.GetFrom(),
+		To:             r.GetTo(),
+		OpeningBalance: statement.OpeningBalance,
+		Lines:          make([]*pb.StatementLine, 0, len(statement.Lines)),
+		ClosingBalance: statement.ClosingBalance,
+	}
+	for _, line := range statement.Lines {
+		response.Lines = append(response.Lines, convertStatementLine(line))
+	}
+
+	return response, nil
+}
+
+func validateGetAccountStatementRequest(r *pb.GetAccountStatementRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if valErr := validation.ValidateID(r.GetAccountId(), "account_id"); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+	if r.From == nil || r.To == nil {
+		violations = append(violations, fieldViolation("from", errors.New("from and to are required")))
+	} else if valErr := validation.ValidateTimeRange(r.GetFrom().AsTime(), r.GetTo().AsTime()); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+
+	return violations
+}
@@ -0,0 +1,151 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestGetAccountStatement(t *testing.T) {
+	user := randomUser("password")
+	account := randomAccount(user.ID)
+
+	to := time.Now().UTC().Truncate(time.Second)
+	from := to.AddDate(0, -1, 0)
+
+	statement := db.GetAccountStatementTxResult{
+		Account:        account,
+		OpeningBalance: 100,
+		Lines: []db.StatementLine{
+			{
+				ListStatementEntriesRow: db.ListStatementEntriesRow{
+					ID:                    1,
+					AccountID:             account.ID,
+					Amount:                -30,
+					TransferID:            pgtype.Int8{Int64: 7, Valid: true},
+					CounterpartyAccountID: account.ID + 1,
+				},
+				Balance: 70,
+			},
+		},
+		ClosingBalance: 70,
+	}
+
+	testCases := []struct {
+		name          string
+		params        *pb.GetAccountStatementRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.GetAccountStatementResponse, err error)
+	}{
+		{
+			name: "OK",
+			params: &pb.GetAccountStatementRequest{
+				AccountId: account.ID,
+				From:      timestamppb.New(from),
+				To:        timestamppb.New(to),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserAccount(gomock.Any(), gomock.Eq(db.GetUserAccountParams{UserID: user.ID, ID: account.ID})).
+					Times(1).
+					Return(account, nil)
+
+				args := db.GetAccountStatementTxParams{
+					AccountID: account.ID,
+					From:      from,
+					To:        to,
+				}
+				store.EXPECT().
+					GetAccountStatementTx(gomock.Any(), gomock.Eq(args)).
+					Times(1).
+					Return(statement, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetAccountStatementResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(100), res.OpeningBalance)
+				require.Equal(t, int64(70), res.ClosingBalance)
+				require.Len(t, res.Lines, 1)
+				require.Equal(t, int64(70), res.Lines[0].Balance)
+				require.Equal(t, int64(7), res.Lines[0].TransferId)
+				require.Equal(t, account.ID+1, res.Lines[0].CounterpartyAccountId)
+			},
+		},
+		{
+			name: "Foreign account",
+			params: &pb.GetAccountStatementRequest{
+				AccountId: account.ID,
+				From:      timestamppb.New(from),
+				To:        timestamppb.New(to),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserAccount(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					GetAccountStatementTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetAccountStatementResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "Inverted range",
+			params: &pb.GetAccountStatementRequest{
+				AccountId: account.ID,
+				From:      timestamppb.New(to),
+				To:        timestamppb.New(from),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountStatementTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetAccountStatementResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "Missing range",
+			params: &pb.GetAccountStatementRequest{
+				AccountId: account.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountStatementTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetAccountStatementResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ctrl := gomock.NewController(t)
+
+		store := mockdb.NewMockStore(ctrl)
+
+		tc.buildStubs(store)
+
+		server := newTestServer(t, store, nil)
+
+		ctx := newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+		res, err := callAuthorized(ctx, server, pb.Bank_GetAccountStatement_FullMethodName, server.GetAccountStatement, tc.params)
+
+		tc.checkResponse(t, res, err)
+	}
+}
@@ -0,0 +1,135 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/utils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetAccount(t *testing.T) {
+	user := randomUser("password")
+	banker := randomUser("password")
+	banker.Role = string(utils.Banker)
+	other := randomUser("password")
+	account := randomAccount(user.ID)
+
+	testCases := []struct {
+		name          string
+		params        *pb.GetAccountRequest
+		buildStubs    func(store *mockdb.MockStore)
+		makeContext   func(server *Server) context.Context
+		checkResponse func(t *testing.T, res *pb.GetAccountResponse, err error)
+	}{
+		{
+			name:   "OK",
+			params: &pb.GetAccountRequest{Id: account.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserAccount(gomock.Any(), gomock.Eq(db.GetUserAccountParams{UserID: user.ID, ID: account.ID})).
+					Times(1).
+					Return(account, nil)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account.ID, res.Account.Id)
+				require.Equal(t, account.Balance, res.Account.Balance)
+				require.Equal(t, account.Owner, res.Account.Owner)
+			},
+		},
+		{
+			name:   "Banker reads any account",
+			params: &pb.GetAccountRequest{Id: account.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, banker, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account.ID, res.Account.Id)
+				require.Equal(t, user.ID, res.Account.UserId)
+			},
+		},
+		{
+			name:   "Foreign account",
+			params: &pb.GetAccountRequest{Id: account.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserAccount(gomock.Any(), gomock.Eq(db.GetUserAccountParams{UserID: other.ID, ID: account.ID})).
+					Times(1).
+					Return(db.Account{}, db.ErrRecordNotFound)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, other, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetAccountResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name:   "Invalid ID",
+			params: &pb.GetAccountRequest{Id: 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetAccountResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name:   "Internal error",
+			params: &pb.GetAccountRequest{Id: account.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserAccount(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Account{}, sql.ErrConnDone)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetAccountResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ctrl := gomock.NewController(t)
+
+		store := mockdb.NewMockStore(ctrl)
+
+		tc.buildStubs(store)
+
+		server := newTestServer(t, store, nil)
+
+		res, err := callAuthorized(tc.makeContext(server), server, pb.Bank_GetAccount_FullMethodName, server.GetAccount, tc.params)
+
+		tc.checkResponse(t, res, err)
+	}
+}
@@ -0,0 +1,29 @@
+package gapi
+
+import (
+	"bank/notify"
+	"bank/pb"
+	"context"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetNotificationPreferences lists the preferences of the caller for every event, the defaults included.
+func (server *Server) GetNotificationPreferences(ctx context.Context, r *pb.GetNotificationPreferencesRequest) (*pb.GetNotificationPreferencesResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences, err := notify.Preferences(ctx, server.store, authPayload.UserID)
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to get notification preferences: %s", err.Error())
+	}
+
+	return &pb.GetNotificationPreferencesResponse{
+		Preferences: convertNotificationPreferences(preferences),
+	}, nil
+}
@@ -0,0 +1,80 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/rbac"
+	"bank/validation"
+	"context"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListAccounts(ctx context.Context, r *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if violations := validateListAccountsRequest(r); violations != nil {
+		return nil, validationError(violations)
+	}
+
+	userID := authPayload.UserID
+	if r.UserId != nil && r.GetUserId() != authPayload.UserID {
+		readAny, err := server.hasPermission(ctx, authPayload, rbac.AccountsReadAny)
+		if err != nil {
+			return nil, err
+		}
+		if !readAny {
+			return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+		}
+		userID = r.GetUserId()
+	}
+
+	pageToken, err := db.DecodePageToken(r.GetPageToken())
+	if err != nil {
+		return nil, validationError([]*errdetails.BadRequest_FieldViolation{fieldViolation("page_token", err)})
+	}
+
+	// one extra row tells whether there is a next page
+	accounts, err := server.store.ListAccounts(ctx, db.ListAccountsParams{
+		UserID:          userID,
+		CursorCreatedAt: pageToken.CursorCreatedAt(),
+		CursorID:        pageToken.CursorID(),
+		PageLimit:       r.GetPageSize() + 1,
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to list accounts: %s", err.Error())
+	}
+
+	response := &pb.ListAccountsResponse{}
+	if len(accounts) > int(r.GetPageSize()) {
+		accounts = accounts[:r.GetPageSize()]
+		last := accounts[len(accounts)-1]
+		response.NextPageToken = db.EncodePageToken(last.CreatedAt, last.ID)
+	}
+
+	response.Accounts = make([]*pb.Account, 0, len(accounts))
+	for _, account := range accounts {
+		response.Accounts = append(response.Accounts, convertAccount(account))
+	}
+
+	return response, nil
+}
+
+func validateListAccountsRequest(r *pb.ListAccountsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if r.UserId != nil {
+		if valErr := validation.ValidateID(r.GetUserId(), "user_id"); valErr != nil {
+			violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+		}
+	}
+	if valErr := validation.ValidatePageSize(r.GetPageSize()); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+
+	return violations
+}
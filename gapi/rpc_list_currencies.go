@@ -0,0 +1,22 @@
+package gapi
+
+import (
+	"bank/pb"
+	"bank/utils"
+	"context"
+)
+
+// ListCurrencies is public: clients need the currencies before a user signs up.
+// It serves the registry loaded from the currencies table at startup.
+func (server *Server) ListCurrencies(ctx context.Context, r *pb.ListCurrenciesRequest) (*pb.ListCurrenciesResponse, error) {
+	currencies := utils.GetEnabledCurrencies()
+
+	response := &pb.ListCurrenciesResponse{
+		Currencies: make([]*pb.Currency, 0, len(currencies)),
+	}
+	for _, currency := range currencies {
+		response.Currencies = append(response.Currencies, convertCurrency(currency))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,30 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	"bank/pb"
+	"bank/utils"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListCurrencies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store, nil)
+
+	res, err := server.ListCurrencies(context.Background(), &pb.ListCurrenciesRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Currencies, len(utils.GetSupportedCurrencies()))
+
+	for _, currency := range res.Currencies {
+		expected, ok := utils.GetCurrency(currency.Code)
+		require.True(t, ok)
+		require.Equal(t, expected.NumericCode, currency.NumericCode)
+		require.Equal(t, expected.Exponent, currency.Exponent)
+		require.Equal(t, expected.Symbol, currency.Symbol)
+	}
+}
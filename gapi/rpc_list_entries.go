@@ -0,0 +1,85 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/rbac"
+	"bank/validation"
+	"context"
+	"errors"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListEntries(ctx context.Context, r *pb.ListEntriesRequest) (*pb.ListEntriesResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if violations := validateListEntriesRequest(r); violations != nil {
+		return nil, validationError(violations)
+	}
+
+	pageToken, err := db.DecodePageToken(r.GetPageToken())
+	if err != nil {
+		return nil, validationError([]*errdetails.BadRequest_FieldViolation{fieldViolation("page_token", err)})
+	}
+
+	readAny, err := server.hasPermission(ctx, authPayload, rbac.AccountsReadAny)
+	if err != nil {
+		return nil, err
+	}
+	if !readAny {
+		_, err = server.store.GetUserAccount(ctx, db.GetUserAccountParams{
+			UserID: authPayload.UserID,
+			ID:     r.GetAccountId(),
+		})
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return nil, status.Errorf(codes.NotFound, "account not found")
+			}
+			log.Println(err)
+			return nil, status.Errorf(codes.Internal, "failed to get account: %s", err.Error())
+		}
+	}
+
+	// one extra row tells whether there is a next page
+	entries, err := server.store.ListEntries(ctx, db.ListEntriesParams{
+		AccountID:       r.GetAccountId(),
+		CursorCreatedAt: pageToken.CursorCreatedAt(),
+		CursorID:        pageToken.CursorID(),
+		PageLimit:       r.GetPageSize() + 1,
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to list entries: %s", err.Error())
+	}
+
+	response := &pb.ListEntriesResponse{}
+	if len(entries) > int(r.GetPageSize()) {
+		entries = entries[:r.GetPageSize()]
+		last := entries[len(entries)-1]
+		response.NextPageToken = db.EncodePageToken(last.CreatedAt, last.ID)
+	}
+
+	response.Entries = make([]*pb.Entry, 0, len(entries))
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, convertEntry(entry))
+	}
+
+	return response, nil
+}
+
+func validateListEntriesRequest(r *pb.ListEntriesRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if valErr := validation.ValidateID(r.GetAccountId(), "account_id"); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+	if valErr := validation.ValidatePageSize(r.GetPageSize()); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+
+	return violations
+}
@@ -0,0 +1,184 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/utils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListEntries(t *testing.T) {
+	user := randomUser("password")
+	banker := randomUser("password")
+	banker.Role = string(utils.Banker)
+	other := randomUser("password")
+	account := randomAccount(user.ID)
+
+	now := time.Now().UTC()
+	entries := make([]db.Entry, 3)
+	for i := range entries {
+		entries[i] = db.Entry{
+			ID:        int64(10 - i),
+			AccountID: account.ID,
+			Amount:    utils.RandomMoney(),
+			CreatedAt: now.Add(-time.Duration(i) * time.Second),
+		}
+	}
+	pageToken := db.EncodePageToken(now, 20)
+
+	testCases := []struct {
+		name          string
+		params        *pb.ListEntriesRequest
+		buildStubs    func(store *mockdb.MockStore)
+		makeContext   func(server *Server) context.Context
+		checkResponse func(t *testing.T, res *pb.ListEntriesResponse, err error)
+	}{
+		{
+			name:   "OK",
+			params: &pb.ListEntriesRequest{AccountId: account.ID, PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserAccount(gomock.Any(), gomock.Eq(db.GetUserAccountParams{UserID: user.ID, ID: account.ID})).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListEntries(gomock.Any(), gomock.Eq(db.ListEntriesParams{AccountID: account.ID, PageLimit: 6})).
+					Times(1).
+					Return(entries, nil)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.Entries, len(entries))
+				require.Empty(t, res.NextPageToken)
+			},
+		},
+		{
+			name:   "Next page",
+			params: &pb.ListEntriesRequest{AccountId: account.ID, PageSize: 2, PageToken: pageToken},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListEntriesParams{
+					AccountID:       account.ID,
+					CursorCreatedAt: pgtype.Timestamptz{Time: now, Valid: true},
+					CursorID:        pgtype.Int8{Int64: 20, Valid: true},
+					PageLimit:       3,
+				}
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					ListEntries(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entries, nil)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, banker, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.Entries, 2)
+				require.Equal(t, entries[1].ID, res.Entries[1].Id)
+				require.Equal(t, db.EncodePageToken(entries[1].CreatedAt, entries[1].ID), res.NextPageToken)
+			},
+		},
+		{
+			name:   "Foreign account",
+			params: &pb.ListEntriesRequest{AccountId: account.ID, PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserAccount(gomock.Any(), gomock.Eq(db.GetUserAccountParams{UserID: other.ID, ID: account.ID})).
+					Times(1).
+					Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					ListEntries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, other, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name:   "Invalid page token",
+			params: &pb.ListEntriesRequest{AccountId: account.ID, PageSize: 5, PageToken: "bad"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListEntries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name:   "Invalid page size",
+			params: &pb.ListEntriesRequest{AccountId: account.ID, PageSize: 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListEntries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name:   "Internal error",
+			params: &pb.ListEntriesRequest{AccountId: account.ID, PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserAccount(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListEntries(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.Entry{}, sql.ErrConnDone)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ctrl := gomock.NewController(t)
+
+		store := mockdb.NewMockStore(ctrl)
+
+		tc.buildStubs(store)
+
+		server := newTestServer(t, store, nil)
+
+		res, err := callAuthorized(tc.makeContext(server), server, pb.Bank_ListEntries_FullMethodName, server.ListEntries, tc.params)
+
+		tc.checkResponse(t, res, err)
+	}
+}
@@ -0,0 +1,63 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/validation"
+	"context"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListLoginLockouts(ctx context.Context, r *pb.ListLoginLockoutsRequest) (*pb.ListLoginLockoutsResponse, error) {
+	if violations := validateListLoginLockoutsRequest(r); violations != nil {
+		return nil, validationError(violations)
+	}
+
+	pageToken, err := db.DecodePageToken(r.GetPageToken())
+	if err != nil {
+		return nil, validationError([]*errdetails.BadRequest_FieldViolation{fieldViolation("page_token", err)})
+	}
+
+	// one extra row tells whether there is a next page
+	lockouts, err := server.store.ListLoginLockouts(ctx, db.ListLoginLockoutsParams{
+		UserID:          optionalInt8(r.UserId),
+		ActiveOnly:      r.GetActiveOnly(),
+		CursorCreatedAt: pageToken.CursorCreatedAt(),
+		CursorID:        pageToken.CursorID(),
+		PageLimit:       r.GetPageSize() + 1,
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to list login lockouts: %s", err.Error())
+	}
+
+	response := &pb.ListLoginLockoutsResponse{}
+	if len(lockouts) > int(r.GetPageSize()) {
+		lockouts = lockouts[:r.GetPageSize()]
+		last := lockouts[len(lockouts)-1]
+		response.NextPageToken = db.EncodePageToken(last.CreatedAt, last.ID)
+	}
+
+	response.Lockouts = make([]*pb.LoginLockout, 0, len(lockouts))
+	for _, lockout := range lockouts {
+		response.Lockouts = append(response.Lockouts, convertLoginLockout(lockout))
+	}
+
+	return response, nil
+}
+
+func validateListLoginLockoutsRequest(r *pb.ListLoginLockoutsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if r.UserId != nil {
+		if valErr := validation.ValidateID(r.GetUserId(), "user_id"); valErr != nil {
+			violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+		}
+	}
+	if valErr := validation.ValidatePageSize(r.GetPageSize()); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+	return violations
+}
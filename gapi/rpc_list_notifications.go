@@ -0,0 +1,61 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/validation"
+	"context"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListNotifications(ctx context.Context, r *pb.ListNotificationsRequest) (*pb.ListNotificationsResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if valErr := validation.ValidatePageSize(r.GetPageSize()); valErr != nil {
+		return nil, validationError([]*errdetails.BadRequest_FieldViolation{fieldViolation(valErr.Field, valErr.Error)})
+	}
+
+	pageToken, err := db.DecodePageToken(r.GetPageToken())
+	if err != nil {
+		return nil, validationError([]*errdetails.BadRequest_FieldViolation{fieldViolation("page_token", err)})
+	}
+
+	// one extra row tells whether there is a next page
+	notifications, err := server.store.ListNotifications(ctx, db.ListNotificationsParams{
+		UserID:          authPayload.UserID,
+		UnreadOnly:      r.GetUnreadOnly(),
+		CursorCreatedAt: pageToken.CursorCreatedAt(),
+		CursorID:        pageToken.CursorID(),
+		PageLimit:       r.GetPageSize() + 1,
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to list notifications: %s", err.Error())
+	}
+
+	unreadCount, err := server.store.CountUnreadNotifications(ctx, authPayload.UserID)
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to count notifications: %s", err.Error())
+	}
+
+	response := &pb.ListNotificationsResponse{UnreadCount: unreadCount}
+	if len(notifications) > int(r.GetPageSize()) {
+		notifications = notifications[:r.GetPageSize()]
+		last := notifications[len(notifications)-1]
+		response.NextPageToken = db.EncodePageToken(last.CreatedAt, last.ID)
+	}
+
+	response.Notifications = make([]*pb.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		response.Notifications = append(response.Notifications, convertNotification(notification))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,262 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/notify"
+	"bank/pb"
+	"bank/utils"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func randomNotification(userID int64, createdAt time.Time) db.Notification {
+	return db.Notification{
+		ID:                    utils.RandomInt(1, 1000),
+		UserID:                userID,
+		Event:                 notify.EventTransferReceived,
+		TransferID:            pgtype.Int8{Int64: utils.RandomInt(1, 1000), Valid: true},
+		AccountID:             utils.RandomInt(1, 1000),
+		CounterpartyAccountID: utils.RandomInt(1, 1000),
+		Amount:                utils.RandomMoney(),
+		Currency:              utils.USD,
+		CreatedAt:             createdAt,
+	}
+}
+
+func TestListNotifications(t *testing.T) {
+	user := randomUser("password")
+	now := time.Now()
+	notifications := []db.Notification{
+		randomNotification(user.ID, now),
+		randomNotification(user.ID, now.Add(-time.Minute)),
+		randomNotification(user.ID, now.Add(-2*time.Minute)),
+	}
+
+	testCases := []struct {
+		name          string
+		request       *pb.ListNotificationsRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.ListNotificationsResponse, err error)
+	}{
+		{
+			name:    "Next page",
+			request: &pb.ListNotificationsRequest{PageSize: 2, UnreadOnly: true},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListNotifications(gomock.Any(), gomock.Eq(db.ListNotificationsParams{
+						UserID:     user.ID,
+						UnreadOnly: true,
+						PageLimit:  3,
+					})).
+					Times(1).
+					Return(notifications, nil)
+				store.EXPECT().CountUnreadNotifications(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(int64(3), nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListNotificationsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.Notifications, 2)
+				require.Equal(t, notifications[0].ID, res.Notifications[0].Id)
+				require.Equal(t, notifications[0].TransferID.Int64, res.Notifications[0].TransferId)
+				require.Nil(t, res.Notifications[0].ReadAt)
+				require.Equal(t, int64(3), res.UnreadCount)
+				require.Equal(t, db.EncodePageToken(notifications[1].CreatedAt, notifications[1].ID), res.NextPageToken)
+			},
+		},
+		{
+			name:    "Bad page token",
+			request: &pb.ListNotificationsRequest{PageSize: 2, PageToken: "garbage"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListNotificationsResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name:    "DB error",
+			request: &pb.ListNotificationsRequest{PageSize: 2},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("db is down"))
+			},
+			checkResponse: func(t *testing.T, res *pb.ListNotificationsResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			res, err := callAuthorized(ctx, server, pb.Bank_ListNotifications_FullMethodName, server.ListNotifications, tc.request)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestMarkNotificationsRead(t *testing.T) {
+	user := randomUser("password")
+
+	testCases := []struct {
+		name          string
+		request       *pb.MarkNotificationsReadRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.MarkNotificationsReadResponse, err error)
+	}{
+		{
+			name:    "Ids",
+			request: &pb.MarkNotificationsReadRequest{Ids: []int64{1, 2}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarkNotificationsRead(gomock.Any(), gomock.Eq(db.MarkNotificationsReadParams{UserID: user.ID, Ids: []int64{1, 2}})).
+					Times(1).
+					Return(int64(2), nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.MarkNotificationsReadResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(2), res.GetMarked())
+			},
+		},
+		{
+			name:    "All",
+			request: &pb.MarkNotificationsReadRequest{All: true},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarkNotificationsRead(gomock.Any(), gomock.Eq(db.MarkNotificationsReadParams{UserID: user.ID, AllNotifications: true})).
+					Times(1).
+					Return(int64(5), nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.MarkNotificationsReadResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(5), res.GetMarked())
+			},
+		},
+		{
+			name:    "Nothing to mark",
+			request: &pb.MarkNotificationsReadRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkNotificationsRead(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.MarkNotificationsReadResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name:    "Bad id",
+			request: &pb.MarkNotificationsReadRequest{Ids: []int64{1, -1}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkNotificationsRead(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.MarkNotificationsReadResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			res, err := callAuthorized(ctx, server, pb.Bank_MarkNotificationsRead_FullMethodName, server.MarkNotificationsRead, tc.request)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestUpdateNotificationPreferences(t *testing.T) {
+	user := randomUser("password")
+
+	testCases := []struct {
+		name          string
+		request       *pb.UpdateNotificationPreferencesRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.UpdateNotificationPreferencesResponse, err error)
+	}{
+		{
+			name: "OK",
+			request: &pb.UpdateNotificationPreferencesRequest{Preferences: []*pb.NotificationPreference{
+				{Event: notify.EventTransferReceived, InApp: true, Email: true},
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertNotificationPreference(gomock.Any(), gomock.Eq(db.UpsertNotificationPreferenceParams{
+						UserID: user.ID,
+						Event:  notify.EventTransferReceived,
+						InApp:  true,
+						Email:  true,
+					})).
+					Times(1)
+				store.EXPECT().
+					ListNotificationPreferences(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return([]db.NotificationPreference{{UserID: user.ID, Event: notify.EventTransferReceived, InApp: true, Email: true}}, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateNotificationPreferencesResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.Preferences, len(notify.Events))
+				require.Equal(t, notify.EventTransferReceived, res.Preferences[0].Event)
+				require.True(t, res.Preferences[0].Email)
+				require.Equal(t, notify.EventTransferSent, res.Preferences[1].Event)
+				require.True(t, res.Preferences[1].InApp)
+				require.False(t, res.Preferences[1].Email)
+			},
+		},
+		{
+			name: "Unsupported event",
+			request: &pb.UpdateNotificationPreferencesRequest{Preferences: []*pb.NotificationPreference{
+				{Event: "account_closed", Email: true},
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertNotificationPreference(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateNotificationPreferencesResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "Repeated event",
+			request: &pb.UpdateNotificationPreferencesRequest{Preferences: []*pb.NotificationPreference{
+				{Event: notify.EventTransferSent, Email: true},
+				{Event: notify.EventTransferSent, Email: false},
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertNotificationPreference(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateNotificationPreferencesResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			res, err := callAuthorized(ctx, server, pb.Bank_UpdateNotificationPreferences_FullMethodName, server.UpdateNotificationPreferences, tc.request)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
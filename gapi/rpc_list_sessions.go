@@ -0,0 +1,32 @@
+package gapi
+
+import (
+	"bank/pb"
+	"context"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListSessions(ctx context.Context, r *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := server.store.ListActiveSessions(ctx, authPayload.UserID)
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to list sessions: %s", err.Error())
+	}
+
+	response := &pb.ListSessionsResponse{
+		Sessions: make([]*pb.Session, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, convertSession(session))
+	}
+
+	return response, nil
+}
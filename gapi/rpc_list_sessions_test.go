@@ -0,0 +1,103 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListSessions(t *testing.T) {
+	user := randomUser("password")
+	loggedInAt := time.Now().Add(-time.Hour).UTC()
+	session := db.ListActiveSessionsRow{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		UserAgent:  "curl",
+		ClientIp:   "10.0.0.1",
+		ExpiresAt:  time.Now().Add(time.Hour).UTC(),
+		CreatedAt:  time.Now().UTC(),
+		FamilyID:   uuid.New(),
+		LoggedInAt: loggedInAt,
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		makeContext   func(server *Server) context.Context
+		checkResponse func(t *testing.T, res *pb.ListSessionsResponse, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListActiveSessions(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return([]db.ListActiveSessionsRow{session}, nil)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListSessionsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.Sessions, 1)
+				require.Equal(t, session.FamilyID.String(), res.Sessions[0].Id)
+				require.Equal(t, session.UserAgent, res.Sessions[0].UserAgent)
+				require.Equal(t, session.ClientIp, res.Sessions[0].ClientIp)
+				require.Equal(t, loggedInAt, res.Sessions[0].CreatedAt.AsTime())
+			},
+		},
+		{
+			name: "Unauthenticated",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.ListSessionsResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "Internal error",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListActiveSessions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListActiveSessionsRow{}, sql.ErrConnDone)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListSessionsResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ctrl := gomock.NewController(t)
+
+		store := mockdb.NewMockStore(ctrl)
+
+		tc.buildStubs(store)
+
+		server := newTestServer(t, store, nil)
+
+		res, err := callAuthorized(tc.makeContext(server), server, pb.Bank_ListSessions_FullMethodName, server.ListSessions, &pb.ListSessionsRequest{})
+
+		tc.checkResponse(t, res, err)
+	}
+}
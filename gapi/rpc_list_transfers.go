@@ -0,0 +1,154 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/rbac"
+	"bank/validation"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (server *Server) ListTransfers(ctx context.Context, r *pb.ListTransfersRequest) (*pb.ListTransfersResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if violations := validateListTransfersRequest(r); violations != nil {
+		return nil, validationError(violations)
+	}
+
+	pageToken, err := db.DecodePageToken(r.GetPageToken())
+	if err != nil {
+		return nil, validationError([]*errdetails.BadRequest_FieldViolation{fieldViolation("page_token", err)})
+	}
+
+	readAny, err := server.hasPermission(ctx, authPayload, rbac.TransfersReadAny)
+	if err != nil {
+		return nil, err
+	}
+	if r.AccountId == nil && !readAny {
+		return nil, status.Errorf(codes.PermissionDenied, "searching across all accounts requires %s", rbac.TransfersReadAny)
+	}
+	if r.AccountId != nil && !readAny {
+		_, err = server.store.GetUserAccount(ctx, db.GetUserAccountParams{
+			UserID: authPayload.UserID,
+			ID:     r.GetAccountId(),
+		})
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return nil, status.Errorf(codes.NotFound, "account not found")
+			}
+			log.Println(err)
+			return nil, status.Errorf(codes.Internal, "failed to get account: %s", err.Error())
+		}
+	}
+
+	// one extra row tells whether there is a next page
+	var transfers []db.Transfer
+	if r.AccountId == nil {
+		transfers, err = server.store.ListTransfers(ctx, db.ListTransfersParams{
+			MinAmount:       optionalInt8(r.MinAmount),
+			MaxAmount:       optionalInt8(r.MaxAmount),
+			FromTime:        optionalTimestamptz(r.GetFrom()),
+			ToTime:          optionalTimestamptz(r.GetTo()),
+			CursorCreatedAt: pageToken.CursorCreatedAt(),
+			CursorID:        pageToken.CursorID(),
+			PageLimit:       r.GetPageSize() + 1,
+		})
+	} else {
+		transfers, err = server.store.ListAccountTransfers(ctx, db.ListAccountTransfersParams{
+			AccountID:             r.GetAccountId(),
+			Outgoing:              r.GetDirection() != pb.TransferDirection_TRANSFER_DIRECTION_INCOMING,
+			Incoming:              r.GetDirection() != pb.TransferDirection_TRANSFER_DIRECTION_OUTGOING,
+			CounterpartyAccountID: optionalInt8(r.CounterpartyAccountId),
+			MinAmount:             optionalInt8(r.MinAmount),
+			MaxAmount:             optionalInt8(r.MaxAmount),
+			FromTime:              optionalTimestamptz(r.GetFrom()),
+			ToTime:                optionalTimestamptz(r.GetTo()),
+			CursorCreatedAt:       pageToken.CursorCreatedAt(),
+			CursorID:              pageToken.CursorID(),
+			PageLimit:             r.GetPageSize() + 1,
+		})
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to list transfers: %s", err.Error())
+	}
+
+	response := &pb.ListTransfersResponse{}
+	if len(transfers) > int(r.GetPageSize()) {
+		transfers = transfers[:r.GetPageSize()]
+		last := transfers[len(transfers)-1]
+		response.NextPageToken = db.EncodePageToken(last.CreatedAt, last.ID)
+	}
+
+	response.Transfers = make([]*pb.Transfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		response.Transfers = append(response.Transfers, convertTransferRecord(transfer))
+	}
+
+	return response, nil
+}
+
+func validateListTransfersRequest(r *pb.ListTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if r.AccountId != nil {
+		if valErr := validation.ValidateID(r.GetAccountId(), "account_id"); valErr != nil {
+			violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+		}
+	} else if r.CounterpartyAccountId != nil || r.GetDirection() != pb.TransferDirection_TRANSFER_DIRECTION_UNSPECIFIED {
+		violations = append(violations, fieldViolation("account_id", fmt.Errorf("is required to filter by counterparty or direction")))
+	}
+	if r.CounterpartyAccountId != nil {
+		if valErr := validation.ValidateID(r.GetCounterpartyAccountId(), "counterparty_account_id"); valErr != nil {
+			violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+		}
+	}
+	if _, ok := pb.TransferDirection_name[int32(r.GetDirection())]; !ok {
+		violations = append(violations, fieldViolation("direction", fmt.Errorf("unknown direction")))
+	}
+	if r.MinAmount != nil {
+		if valErr := validation.ValidateAmount(r.GetMinAmount()); valErr != nil {
+			violations = append(violations, fieldViolation("min_amount", valErr.Error))
+		}
+	}
+	if r.MaxAmount != nil {
+		if valErr := validation.ValidateAmount(r.GetMaxAmount()); valErr != nil {
+			violations = append(violations, fieldViolation("max_amount", valErr.Error))
+		} else if r.MinAmount != nil && r.GetMaxAmount() < r.GetMinAmount() {
+			violations = append(violations, fieldViolation("max_amount", fmt.Errorf("must not be less than min_amount")))
+		}
+	}
+	if r.From != nil && r.To != nil {
+		if valErr := validation.ValidateTimeRange(r.GetFrom().AsTime(), r.GetTo().AsTime()); valErr != nil {
+			violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+		}
+	}
+	if valErr := validation.ValidatePageSize(r.GetPageSize()); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+
+	return violations
+}
+
+func optionalInt8(value *int64) pgtype.Int8 {
+	if value == nil {
+		return pgtype.Int8{}
+	}
+	return pgtype.Int8{Int64: *value, Valid: true}
+}
+
+func optionalTimestamptz(ts *timestamppb.Timestamp) pgtype.Timestamptz {
+	if ts == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: ts.AsTime(), Valid: true}
+}
@@ -0,0 +1,272 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/utils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestListTransfers(t *testing.T) {
+	user := randomUser("password")
+	banker := randomUser("password")
+	banker.Role = string(utils.Banker)
+	account := randomAccount(user.ID)
+	counterparty := randomAccount(randomUser("password").ID)
+
+	now := time.Now().UTC()
+	transfers := make([]db.Transfer, 3)
+	for i := range transfers {
+		transfers[i] = db.Transfer{
+			ID:            int64(10 - i),
+			FromAccountID: account.ID,
+			ToAccountID:   counterparty.ID,
+			Amount:        utils.RandomMoney(),
+			CreatedAt:     now.Add(-time.Duration(i) * time.Second),
+		}
+	}
+	from := now.Add(-time.Hour)
+
+	testCases := []struct {
+		name          string
+		params        *pb.ListTransfersRequest
+		buildStubs    func(store *mockdb.MockStore)
+		makeContext   func(server *Server) context.Context
+		checkResponse func(t *testing.T, res *pb.ListTransfersResponse, err error)
+	}{
+		{
+			name: "OK",
+			params: &pb.ListTransfersRequest{
+				AccountId:             proto.Int64(account.ID),
+				CounterpartyAccountId: proto.Int64(counterparty.ID),
+				Direction:             pb.TransferDirection_TRANSFER_DIRECTION_OUTGOING,
+				MinAmount:             proto.Int64(10),
+				MaxAmount:             proto.Int64(1000),
+				From:                  timestamppb.New(from),
+				To:                    timestamppb.New(now),
+				PageSize:              2,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserAccount(gomock.Any(), gomock.Eq(db.GetUserAccountParams{UserID: user.ID, ID: account.ID})).
+					Times(1).
+					Return(account, nil)
+				arg := db.ListAccountTransfersParams{
+					AccountID:             account.ID,
+					Outgoing:              true,
+					Incoming:              false,
+					CounterpartyAccountID: pgtype.Int8{Int64: counterparty.ID, Valid: true},
+					MinAmount:             pgtype.Int8{Int64: 10, Valid: true},
+					MaxAmount:             pgtype.Int8{Int64: 1000, Valid: true},
+					FromTime:              pgtype.Timestamptz{Time: from, Valid: true},
+					ToTime:                pgtype.Timestamptz{Time: now, Valid: true},
+					PageLimit:             3,
+				}
+				store.EXPECT().
+					ListAccountTransfers(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(transfers, nil)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.Transfers, 2)
+				require.Equal(t, transfers[0].ID, res.Transfers[0].Id)
+				require.Equal(t, transfers[0].Amount, res.Transfers[0].Amount)
+				require.Equal(t, db.EncodePageToken(transfers[1].CreatedAt, transfers[1].ID), res.NextPageToken)
+			},
+		},
+		{
+			name:   "Banker searches all accounts",
+			params: &pb.ListTransfersRequest{MinAmount: proto.Int64(10), PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+				arg := db.ListTransfersParams{
+					MinAmount: pgtype.Int8{Int64: 10, Valid: true},
+					PageLimit: 6,
+				}
+				store.EXPECT().
+					ListTransfers(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(transfers, nil)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, banker, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.Transfers, len(transfers))
+				require.Empty(t, res.NextPageToken)
+			},
+		},
+		{
+			name:   "Banker searches foreign account",
+			params: &pb.ListTransfersRequest{AccountId: proto.Int64(account.ID), PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					ListAccountTransfers(gomock.Any(), gomock.Eq(db.ListAccountTransfersParams{
+						AccountID: account.ID,
+						Outgoing:  true,
+						Incoming:  true,
+						PageLimit: 6,
+					})).
+					Times(1).
+					Return(transfers, nil)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, banker, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.Transfers, len(transfers))
+			},
+		},
+		{
+			name:   "Depositor searches all accounts",
+			params: &pb.ListTransfersRequest{PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListTransfers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name:   "Foreign account",
+			params: &pb.ListTransfersRequest{AccountId: proto.Int64(counterparty.ID), PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserAccount(gomock.Any(), gomock.Eq(db.GetUserAccountParams{UserID: user.ID, ID: counterparty.ID})).
+					Times(1).
+					Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					ListAccountTransfers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "Invalid amount bounds",
+			params: &pb.ListTransfersRequest{
+				AccountId: proto.Int64(account.ID),
+				MinAmount: proto.Int64(100),
+				MaxAmount: proto.Int64(10),
+				PageSize:  5,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccountTransfers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "Direction without account",
+			params: &pb.ListTransfersRequest{
+				Direction: pb.TransferDirection_TRANSFER_DIRECTION_INCOMING,
+				PageSize:  5,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListTransfers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, banker, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "Invalid time window",
+			params: &pb.ListTransfersRequest{
+				AccountId: proto.Int64(account.ID),
+				From:      timestamppb.New(now),
+				To:        timestamppb.New(from),
+				PageSize:  5,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccountTransfers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name:   "Internal error",
+			params: &pb.ListTransfersRequest{PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListTransfers(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.Transfer{}, sql.ErrConnDone)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, banker, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ctrl := gomock.NewController(t)
+
+		store := mockdb.NewMockStore(ctrl)
+
+		tc.buildStubs(store)
+
+		server := newTestServer(t, store, nil)
+
+		res, err := callAuthorized(tc.makeContext(server), server, pb.Bank_ListTransfers_FullMethodName, server.ListTransfers, tc.params)
+
+		tc.checkResponse(t, res, err)
+	}
+}
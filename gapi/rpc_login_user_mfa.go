@@ -0,0 +1,75 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/mfa"
+	"bank/pb"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) LoginUserMFA(ctx context.Context, r *pb.LoginUserMFARequest) (*pb.LoginUserResponse, error) {
+	challengeID, violations := validateLoginUserMFARequest(r)
+	if violations != nil {
+		return nil, validationError(violations)
+	}
+
+	userID, err := mfa.ChallengeUser(ctx, server.store, challengeID)
+	if err != nil {
+		if errors.Is(err, mfa.ErrInvalidChallenge) {
+			return nil, unauthenticatedError(err)
+		}
+		return nil, mfaError(err)
+	}
+
+	user, err := server.store.GetUser(ctx, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err.Error())
+	}
+
+	// a locked out email gets no codes tried, however many challenges its password opened
+	clientIP := server.extractMedadata(ctx).ClientIP
+	if err = server.loginGuard.Check(ctx, user.Email, clientIP); err != nil {
+		return nil, loginAttemptError(err)
+	}
+
+	_, err = mfa.CompleteChallenge(ctx, server.store, challengeID, r.GetCode())
+	if err != nil {
+		if errors.Is(err, mfa.ErrInvalidCode) {
+			server.failLoginMFA(ctx, user, clientIP)
+		}
+		if errors.Is(err, mfa.ErrInvalidChallenge) || errors.Is(err, mfa.ErrInvalidCode) || errors.Is(err, mfa.ErrNotEnrolled) {
+			return nil, unauthenticatedError(err)
+		}
+		return nil, mfaError(err)
+	}
+
+	return server.createLoginTokens(ctx, user)
+}
+
+// failLoginMFA counts a wrong code as a failed login of the user, so that fresh challenges
+// don't give unlimited tries at the second factor.
+func (server *Server) failLoginMFA(ctx context.Context, user db.User, clientIP string) {
+	if err := server.loginGuard.Fail(ctx, user.Email, clientIP, &user.ID); err != nil {
+		log.Println(err)
+	}
+}
+
+func validateLoginUserMFARequest(r *pb.LoginUserMFARequest) (challengeID uuid.UUID, violations []*errdetails.BadRequest_FieldViolation) {
+	challengeID, err := uuid.Parse(r.GetMfaToken())
+	if err != nil {
+		violations = append(violations, fieldViolation("mfa_token", fmt.Errorf("is invalid")))
+	}
+	if r.GetCode() == "" {
+		violations = append(violations, fieldViolation("code", fmt.Errorf("is required")))
+	}
+	return challengeID, violations
+}
@@ -0,0 +1,162 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/lockout"
+	"bank/mfa"
+	"bank/pb"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoginUserMFA(t *testing.T) {
+	user := randomUser("password")
+	challengeID := uuid.New()
+	challenge := db.MfaChallenge{ID: challengeID, UserID: user.ID, ExpiresAt: time.Now().Add(time.Minute)}
+
+	secret, err := mfa.GenerateSecret()
+	require.NoError(t, err)
+	totp := db.UserTotp{UserID: user.ID, Secret: secret, ConfirmedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}
+
+	validCode := func(t *testing.T) string {
+		code, err := mfa.Code(secret, time.Now())
+		require.NoError(t, err)
+		return code
+	}
+
+	// the guard lets the login through before the code is tried
+	expectPendingChallenge := func(store *mockdb.MockStore) {
+		store.EXPECT().GetPendingMFAChallenge(gomock.Any(), gomock.Eq(challengeID)).Times(1).Return(challenge, nil)
+		store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+		store.EXPECT().GetActiveLoginLockout(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(db.LoginLockout{}, db.ErrRecordNotFound)
+		store.EXPECT().GetLoginFailureStats(gomock.Any(), gomock.Any()).Times(1).Return(db.GetLoginFailureStatsRow{}, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		mfaToken      string
+		makeCode      func(t *testing.T) string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.LoginUserResponse, err error)
+	}{
+		{
+			name:     "OK",
+			mfaToken: challengeID.String(),
+			makeCode: validCode,
+			buildStubs: func(store *mockdb.MockStore) {
+				expectPendingChallenge(store)
+				store.EXPECT().AttemptMFAChallenge(gomock.Any(), gomock.Any()).Times(1).Return(challenge, nil)
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(totp, nil)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().ConsumeMFAChallenge(gomock.Any(), gomock.Eq(challengeID)).Times(1).Return(int64(1), nil)
+				store.EXPECT().ClearLoginFailures(gomock.Any(), gomock.Eq(user.Email)).Times(1)
+				store.EXPECT().CreateLoginSessionTx(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, res.GetAccessToken())
+				require.NotEmpty(t, res.GetRefreshToken())
+				require.False(t, res.GetMfaRequired())
+			},
+		},
+		{
+			name:     "Invalid code",
+			mfaToken: challengeID.String(),
+			makeCode: func(t *testing.T) string { return "recovery-code" },
+			buildStubs: func(store *mockdb.MockStore) {
+				expectPendingChallenge(store)
+				store.EXPECT().AttemptMFAChallenge(gomock.Any(), gomock.Any()).Times(1).Return(challenge, nil)
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(totp, nil)
+				store.EXPECT().UseRecoveryCode(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().ConsumeMFAChallenge(gomock.Any(), gomock.Any()).Times(0)
+				// a wrong code counts like a wrong password
+				store.EXPECT().CreateLoginFailure(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().GetLoginFailureStats(gomock.Any(), gomock.Any()).Times(1).Return(db.GetLoginFailureStatsRow{EmailFailures: 1}, nil)
+				store.EXPECT().ClearLoginFailures(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateLoginSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:     "Locked out",
+			mfaToken: challengeID.String(),
+			makeCode: validCode,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingMFAChallenge(gomock.Any(), gomock.Eq(challengeID)).Times(1).Return(challenge, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetActiveLoginLockout(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(db.LoginLockout{Email: user.Email, LockedUntil: time.Now().Add(time.Hour)}, nil)
+				// even a valid code is not tried once the email is locked out
+				store.EXPECT().AttemptMFAChallenge(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ClearLoginFailures(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateLoginSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.ResourceExhausted, status.Code(err))
+			},
+		},
+		{
+			name:     "Expired challenge",
+			mfaToken: challengeID.String(),
+			makeCode: validCode,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingMFAChallenge(gomock.Any(), gomock.Eq(challengeID)).Times(1).Return(db.MfaChallenge{}, db.ErrRecordNotFound)
+				store.EXPECT().AttemptMFAChallenge(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateLoginSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:     "Invalid mfa token",
+			mfaToken: "not-a-token",
+			makeCode: validCode,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingMFAChallenge(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().AttemptMFAChallenge(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			server.config.LoginMaxFailures = 5
+			server.config.LoginFailureWindow = time.Hour
+			server.loginGuard = lockout.NewGuard(store, *server.config)
+
+			res, err := server.LoginUserMFA(context.Background(), &pb.LoginUserMFARequest{
+				MfaToken: tc.mfaToken,
+				Code:     tc.makeCode(t),
+			})
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package gapi
+
+import (
+	"bank/async"
+	mockasync "bank/async/mock"
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/lockout"
+	"bank/pb"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoginUserLockout(t *testing.T) {
+	password := "password"
+	user := randomUser(password)
+	noFailures := db.GetLoginFailureStatsRow{EmailLastFailure: time.Unix(0, 0), IpLastFailure: time.Unix(0, 0)}
+
+	testCases := []struct {
+		name          string
+		email         string
+		password      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.LoginUserResponse, err error)
+	}{
+		{
+			name:     "OK",
+			email:    user.Email,
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveLoginLockout(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(db.LoginLockout{}, db.ErrRecordNotFound)
+				store.EXPECT().GetLoginFailureStats(gomock.Any(), gomock.Any()).Times(1).Return(noFailures, nil)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ClearLoginFailures(gomock.Any(), gomock.Eq(user.Email)).Times(1)
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.UserTotp{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateLoginSessionTx(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, res.GetAccessToken())
+			},
+		},
+		{
+			name:     "Session not created",
+			email:    user.Email,
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveLoginLockout(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(db.LoginLockout{}, db.ErrRecordNotFound)
+				store.EXPECT().GetLoginFailureStats(gomock.Any(), gomock.Any()).Times(1).Return(noFailures, nil)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ClearLoginFailures(gomock.Any(), gomock.Eq(user.Email)).Times(1)
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.UserTotp{}, db.ErrRecordNotFound)
+				// the tokens would fail the revocation check without their session
+				store.EXPECT().CreateLoginSessionTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CreateLoginSessionTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Nil(t, res)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name:     "Second factor pending",
+			email:    user.Email,
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveLoginLockout(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(db.LoginLockout{}, db.ErrRecordNotFound)
+				store.EXPECT().GetLoginFailureStats(gomock.Any(), gomock.Any()).Times(1).Return(noFailures, nil)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(db.UserTotp{UserID: user.ID, ConfirmedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}, nil)
+				store.EXPECT().CreateMFAChallenge(gomock.Any(), gomock.Any()).Times(1)
+				// the failures are kept until the second factor passes
+				store.EXPECT().ClearLoginFailures(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetMfaRequired())
+			},
+		},
+		{
+			name:     "Wrong password",
+			email:    user.Email,
+			password: "wrong-password",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveLoginLockout(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(db.LoginLockout{}, db.ErrRecordNotFound)
+				store.EXPECT().GetLoginFailureStats(gomock.Any(), gomock.Any()).Times(2).Return(noFailures, nil)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateLoginFailure(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().CreateLoginSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+				require.Equal(t, errInvalidCredentials.Error(), status.Convert(err).Message())
+			},
+		},
+		{
+			name:     "Unknown email",
+			email:    "unknown@example.com",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveLoginLockout(gomock.Any(), gomock.Any()).Times(1).Return(db.LoginLockout{}, db.ErrRecordNotFound)
+				store.EXPECT().GetLoginFailureStats(gomock.Any(), gomock.Any()).Times(2).Return(noFailures, nil)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateLoginFailure(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+				require.Equal(t, errInvalidCredentials.Error(), status.Convert(err).Message())
+			},
+		},
+		{
+			name:     "Failure reaches the limit",
+			email:    user.Email,
+			password: "wrong-password",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveLoginLockout(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(db.LoginLockout{}, db.ErrRecordNotFound)
+				gomock.InOrder(
+					store.EXPECT().GetLoginFailureStats(gomock.Any(), gomock.Any()).Times(1).Return(noFailures, nil),
+					store.EXPECT().GetLoginFailureStats(gomock.Any(), gomock.Any()).Times(1).Return(db.GetLoginFailureStatsRow{EmailFailures: 5}, nil),
+				)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateLoginFailure(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().
+					LockLoginTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.LockLoginTxParams) (db.LockLoginTxResult, error) {
+						require.Equal(t, user.ID, arg.UserID.Int64)
+						return db.LockLoginTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:     "Locked",
+			email:    user.Email,
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetActiveLoginLockout(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(db.LoginLockout{Email: user.Email, LockedUntil: time.Now().Add(time.Minute)}, nil)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.ResourceExhausted, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			server.config.LoginMaxFailures = 5
+			server.config.LoginFailureWindow = time.Hour
+			server.config.LoginLockoutDuration = time.Hour
+			server.loginGuard = lockout.NewGuard(store, *server.config)
+
+			res, err := server.LoginUser(context.Background(), &pb.LoginUserRequest{
+				Email:    tc.email,
+				Password: tc.password,
+			})
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestLoginUserNewDevice(t *testing.T) {
+	password := "password"
+	user := randomUser(password)
+
+	// createLoginSession stands in for the transaction of db.CreateLoginSessionTx
+	// and runs its callback as if the login came from a new device.
+	createLoginSession := func(ctx context.Context, arg db.CreateLoginSessionTxParams) (db.CreateLoginSessionTxResult, error) {
+		session := db.Session{ID: arg.ID, UserID: arg.UserID, ExpiresAt: arg.ExpiresAt}
+		if err := arg.AfterNewDevice(ctx, session); err != nil {
+			return db.CreateLoginSessionTxResult{}, err
+		}
+		return db.CreateLoginSessionTxResult{Session: session, NewDevice: true}, nil
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor)
+		checkResponse func(t *testing.T, res *pb.LoginUserResponse, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				var sessionID uuid.UUID
+				store.EXPECT().
+					CreateLoginSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateLoginSessionTxParams) (db.CreateLoginSessionTxResult, error) {
+						sessionID = arg.ID
+						return createLoginSession(ctx, arg)
+					})
+				distributor.EXPECT().
+					DistributeTaskNotifyNewDeviceLogin(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, payload *async.PayloadNotifyNewDeviceLogin, opts ...asynq.Option) error {
+						require.Equal(t, sessionID, payload.SessionID)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, res.GetAccessToken())
+			},
+		},
+		{
+			name: "Outbox down",
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().CreateLoginSessionTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(createLoginSession)
+				// the session is rolled back along with the alert, so no tokens are issued
+				distributor.EXPECT().
+					DistributeTaskNotifyNewDeviceLogin(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(errors.New("outbox is down"))
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Nil(t, res)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			store := mockdb.NewMockStore(ctrl)
+			distributor := mockasync.NewMockTaskDistributor(ctrl)
+			store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+			store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.UserTotp{}, db.ErrRecordNotFound)
+			tc.buildStubs(store, distributor)
+
+			server := newTestServer(t, store, distributor)
+			res, err := server.LoginUser(context.Background(), &pb.LoginUserRequest{
+				Email:    user.Email,
+				Password: password,
+			})
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
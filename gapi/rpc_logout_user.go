@@ -0,0 +1,24 @@
+package gapi
+
+import (
+	"bank/pb"
+	"context"
+)
+
+func (server *Server) LogoutUser(ctx context.Context, r *pb.LogoutUserRequest) (*pb.LogoutUserResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := server.sessionOfRefreshToken(ctx, r.GetRefreshToken(), authPayload.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = server.blockSessions(ctx, authPayload.UserID, &current.FamilyID, nil); err != nil {
+		return nil, err
+	}
+
+	return &pb.LogoutUserResponse{}, nil
+}
@@ -0,0 +1,99 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/token"
+	"bank/utils"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLogoutUser(t *testing.T) {
+	user := randomUser("password")
+	other := randomUser("password")
+	familyID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		tokenOwner    db.User
+		buildStubs    func(store *mockdb.MockStore, refreshToken string, sessionID uuid.UUID)
+		checkResponse func(t *testing.T, res *pb.LogoutUserResponse, err error)
+	}{
+		{
+			name:       "OK",
+			tokenOwner: user,
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, sessionID uuid.UUID) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(db.Session{ID: sessionID, UserID: user.ID, RefreshToken: refreshToken, FamilyID: familyID}, nil)
+				store.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Eq(db.BlockUserSessionsParams{
+						UserID:   user.ID,
+						FamilyID: pgtype.UUID{Bytes: familyID, Valid: true},
+					})).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.LogoutUserResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:       "Foreign refresh token",
+			tokenOwner: other,
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, sessionID uuid.UUID) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(db.Session{ID: sessionID, UserID: other.ID, RefreshToken: refreshToken, FamilyID: familyID}, nil)
+				store.EXPECT().BlockUserSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LogoutUserResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:       "Session not found",
+			tokenOwner: user,
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, sessionID uuid.UUID) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{}, db.ErrRecordNotFound)
+				store.EXPECT().BlockUserSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LogoutUserResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ctrl := gomock.NewController(t)
+
+		store := mockdb.NewMockStore(ctrl)
+		server := newTestServer(t, store, nil)
+
+		refreshToken, payload, err := server.tokenMaker.CreateToken(tc.tokenOwner.ID, utils.Role(tc.tokenOwner.Role), token.TokenTypeRefresh, uuid.Nil, time.Minute)
+		require.NoError(t, err)
+
+		tc.buildStubs(store, refreshToken, payload.ID)
+
+		ctx := newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+		res, err := callAuthorized(ctx, server, pb.Bank_LogoutUser_FullMethodName, server.LogoutUser, &pb.LogoutUserRequest{RefreshToken: refreshToken})
+
+		tc.checkResponse(t, res, err)
+	}
+}
@@ -0,0 +1,58 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/validation"
+	"context"
+	"fmt"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxMarkedNotifications = 100
+
+// MarkNotificationsRead marks the given notifications of the caller, or all of them, as read.
+// The ids of other users and the notifications read already are skipped.
+func (server *Server) MarkNotificationsRead(ctx context.Context, r *pb.MarkNotificationsReadRequest) (*pb.MarkNotificationsReadResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if violations := validateMarkNotificationsReadRequest(r); violations != nil {
+		return nil, validationError(violations)
+	}
+
+	marked, err := server.store.MarkNotificationsRead(ctx, db.MarkNotificationsReadParams{
+		UserID:           authPayload.UserID,
+		AllNotifications: r.GetAll(),
+		Ids:              r.GetIds(),
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to mark notifications read: %s", err.Error())
+	}
+
+	return &pb.MarkNotificationsReadResponse{Marked: marked}, nil
+}
+
+func validateMarkNotificationsReadRequest(r *pb.MarkNotificationsReadRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if r.GetAll() {
+		return nil
+	}
+	if len(r.GetIds()) == 0 {
+		return append(violations, fieldViolation("ids", fmt.Errorf("are required unless all is set")))
+	}
+	if len(r.GetIds()) > maxMarkedNotifications {
+		return append(violations, fieldViolation("ids", fmt.Errorf("should be of 1-%d ids", maxMarkedNotifications)))
+	}
+	for _, id := range r.GetIds() {
+		if valErr := validation.ValidateID(id, "ids"); valErr != nil {
+			return append(violations, fieldViolation(valErr.Field, valErr.Error))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/token"
+	"bank/utils"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (server *Server) RenewAccessToken(ctx context.Context, r *pb.RenewAccessTokenRequest) (*pb.RenewAccessTokenResponse, error) {
+	if r.GetRefreshToken() == "" {
+		return nil, validationError([]*errdetails.BadRequest_FieldViolation{
+			fieldViolation("refresh_token", fmt.Errorf("is required")),
+		})
+	}
+
+	refreshPayload, err := server.tokenMaker.VerifyToken(r.GetRefreshToken())
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	if err = refreshPayload.CheckType(token.TokenTypeRefresh); err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	user, err := server.store.GetUser(ctx, refreshPayload.UserID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, unauthenticatedError(err)
+		}
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err.Error())
+	}
+
+	newRefreshToken, newRefreshPayload, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), token.TokenTypeRefresh, uuid.Nil, server.config.RefreshTokenDuration)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	meta := server.extractMedadata(ctx)
+	result, err := server.store.RenewSessionTx(ctx, db.RenewSessionTxParams{
+		SessionID:    refreshPayload.ID,
+		UserID:       refreshPayload.UserID,
+		RefreshToken: r.GetRefreshToken(),
+		NewSession: db.CreateSessionParams{
+			ID:           newRefreshPayload.ID,
+			UserID:       user.ID,
+			RefreshToken: newRefreshToken,
+			UserAgent:    meta.UserAgent,
+			ClientIp:     meta.ClientIP,
+			IsBlocked:    false,
+			ExpiresAt:    time.Now().Add(server.config.RefreshTokenDuration),
+		},
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrSessionNotFound), errors.Is(err, db.ErrSessionBlocked),
+			errors.Is(err, db.ErrSessionExpired), errors.Is(err, db.ErrRefreshTokenInvalid):
+			return nil, unauthenticatedError(err)
+		case errors.Is(err, db.ErrRefreshTokenReused):
+			log.Printf("refresh token reuse detected for user %d, session %s", user.ID, refreshPayload.ID)
+			server.revocation.Forget(user.ID)
+			return nil, unauthenticatedError(err)
+		}
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to renew session: %s", err.Error())
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), token.TokenTypeAccess, result.Session.FamilyID, server.config.AccessTokenDuration)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &pb.RenewAccessTokenResponse{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  timestamppb.New(accessPayload.ExpiresAt),
+		RefreshToken:          newRefreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(newRefreshPayload.ExpiresAt),
+	}, nil
+}
@@ -0,0 +1,159 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/token"
+	"bank/utils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRenewAccessToken(t *testing.T) {
+	user := randomUser("password")
+	familyID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		makeToken     func(t *testing.T, server *Server) string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, refreshToken string, res *pb.RenewAccessTokenResponse, err error)
+	}{
+		{
+			name: "OK",
+			makeToken: func(t *testing.T, server *Server) string {
+				token, _, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), token.TokenTypeRefresh, uuid.Nil, time.Minute)
+				require.NoError(t, err)
+				return token
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					RenewSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RenewSessionTxParams) (db.RenewSessionTxResult, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.NotEqual(t, arg.SessionID, arg.NewSession.ID)
+						require.NotEqual(t, arg.RefreshToken, arg.NewSession.RefreshToken)
+						return db.RenewSessionTxResult{Session: db.Session{ID: arg.NewSession.ID, FamilyID: familyID}}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, server *Server, refreshToken string, res *pb.RenewAccessTokenResponse, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, res.AccessToken)
+				require.NotEqual(t, refreshToken, res.RefreshToken)
+
+				payload, err := server.tokenMaker.VerifyToken(res.AccessToken)
+				require.NoError(t, err)
+				require.Equal(t, user.ID, payload.UserID)
+				require.Equal(t, familyID, payload.SessionID)
+				require.Equal(t, token.TokenTypeAccess, payload.Type)
+			},
+		},
+		{
+			name: "Access token",
+			makeToken: func(t *testing.T, server *Server) string {
+				token, _, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), token.TokenTypeAccess, familyID, time.Minute)
+				require.NoError(t, err)
+				return token
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().RenewSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, refreshToken string, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "Reused refresh token",
+			makeToken: func(t *testing.T, server *Server) string {
+				token, _, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), token.TokenTypeRefresh, uuid.Nil, time.Minute)
+				require.NoError(t, err)
+				return token
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					RenewSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RenewSessionTxResult{}, db.ErrRefreshTokenReused)
+			},
+			checkResponse: func(t *testing.T, server *Server, refreshToken string, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "Invalid token",
+			makeToken: func(t *testing.T, server *Server) string {
+				return "invalid"
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().RenewSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, refreshToken string, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "Empty token",
+			makeToken: func(t *testing.T, server *Server) string {
+				return ""
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().RenewSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, refreshToken string, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "Internal error",
+			makeToken: func(t *testing.T, server *Server) string {
+				token, _, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), token.TokenTypeRefresh, uuid.Nil, time.Minute)
+				require.NoError(t, err)
+				return token
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					RenewSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RenewSessionTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, server *Server, refreshToken string, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ctrl := gomock.NewController(t)
+
+		store := mockdb.NewMockStore(ctrl)
+
+		tc.buildStubs(store)
+
+		server := newTestServer(t, store, nil)
+		refreshToken := tc.makeToken(t, server)
+
+		res, err := server.RenewAccessToken(context.Background(), &pb.RenewAccessTokenRequest{RefreshToken: refreshToken})
+
+		tc.checkResponse(t, server, refreshToken, res, err)
+	}
+}
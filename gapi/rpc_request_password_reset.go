@@ -0,0 +1,55 @@
+package gapi
+
+import (
+	"bank/async"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/validation"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RequestPasswordReset emails a reset link to the user. It answers the same way
+// for unknown emails and for emails over the rate limit, so that accounts can't be enumerated.
+func (server *Server) RequestPasswordReset(ctx context.Context, r *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	if valErr := validation.ValidateEmail(r.GetEmail()); valErr != nil {
+		return nil, validationError([]*errdetails.BadRequest_FieldViolation{fieldViolation(valErr.Field, valErr.Error)})
+	}
+
+	user, err := server.store.GetUserByEmail(ctx, r.GetEmail())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return &pb.RequestPasswordResetResponse{}, nil
+		}
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err.Error())
+	}
+
+	// the request is recorded before the task runs, so that a burst of requests is counted in full
+	err = server.store.RequestPasswordResetTx(ctx, db.RequestPasswordResetTxParams{
+		UserID: user.ID,
+		Limit:  server.config.PasswordResetLimit,
+		Since:  time.Now().Add(-server.config.PasswordResetWindow),
+		AfterRequest: func(ctx context.Context) error {
+			payload := &async.PayloadSendPasswordReset{UserID: user.ID}
+			return server.taskDistributor.DistributeTaskPasswordReset(ctx, payload, asynq.MaxRetry(5))
+		},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrTooManyRequests) {
+			log.Printf("password reset rate limit reached for user %d", user.ID)
+			return &pb.RequestPasswordResetResponse{}, nil
+		}
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to request password reset: %s", err.Error())
+	}
+
+	return &pb.RequestPasswordResetResponse{}, nil
+}
@@ -0,0 +1,124 @@
+package gapi
+
+import (
+	mockasync "bank/async/mock"
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestPasswordReset(t *testing.T) {
+	user := randomUser("password")
+	limit := int64(3)
+
+	testCases := []struct {
+		name          string
+		email         string
+		buildStubs    func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor)
+		checkResponse func(t *testing.T, res *pb.RequestPasswordResetResponse, err error)
+	}{
+		{
+			name:  "OK",
+			email: user.Email,
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					RequestPasswordResetTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.RequestPasswordResetTxParams) error {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, limit, arg.Limit)
+						return arg.AfterRequest(ctx)
+					})
+				distributor.EXPECT().DistributeTaskPasswordReset(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+			},
+		},
+		{
+			name:  "Unknown email",
+			email: "unknown@example.com",
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+				distributor.EXPECT().DistributeTaskPasswordReset(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+			},
+		},
+		{
+			name:  "Rate limited",
+			email: user.Email,
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().RequestPasswordResetTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ErrTooManyRequests)
+				distributor.EXPECT().DistributeTaskPasswordReset(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+			},
+		},
+		{
+			name:  "Queue down",
+			email: user.Email,
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					RequestPasswordResetTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.RequestPasswordResetTxParams) error {
+						return arg.AfterRequest(ctx)
+					})
+				distributor.EXPECT().
+					DistributeTaskPasswordReset(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(errors.New("outbox is down"))
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name:  "Invalid email",
+			email: "not-an-email",
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			store := mockdb.NewMockStore(ctrl)
+			distributor := mockasync.NewMockTaskDistributor(ctrl)
+			tc.buildStubs(store, distributor)
+
+			server := newTestServer(t, store, distributor)
+			server.config.PasswordResetLimit = limit
+			server.config.PasswordResetWindow = time.Hour
+
+			res, err := server.RequestPasswordReset(context.Background(), &pb.RequestPasswordResetRequest{Email: tc.email})
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package gapi
+
+import (
+	"bank/async"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ResendVerificationEmail sends a new verification code to the user, replacing the pending one.
+// It is limited to VerifyEmailLimit requests per VerifyEmailWindow.
+func (server *Server) ResendVerificationEmail(ctx context.Context, r *pb.ResendVerificationEmailRequest) (*pb.ResendVerificationEmailResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := server.store.GetUser(ctx, authPayload.UserID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err.Error())
+	}
+	if user.IsVerified.Bool {
+		return nil, status.Errorf(codes.FailedPrecondition, "email is already verified")
+	}
+
+	err = server.store.ResendVerifyEmailTx(ctx, db.ResendVerifyEmailTxParams{
+		UserID: user.ID,
+		Limit:  server.config.VerifyEmailLimit,
+		Since:  time.Now().Add(-server.config.VerifyEmailWindow),
+		AfterRequest: func(ctx context.Context) error {
+			payload := &async.PayloadSendVerifyEmail{UserID: user.ID}
+			return server.taskDistributor.DistributeTaskVerifyEmail(ctx, payload, asynq.MaxRetry(5))
+		},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrTooManyRequests) {
+			return nil, status.Errorf(codes.ResourceExhausted, "too many verification emails requested, try again later")
+		}
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to resend verification email: %s", err.Error())
+	}
+
+	return &pb.ResendVerificationEmailResponse{}, nil
+}
@@ -0,0 +1,127 @@
+package gapi
+
+import (
+	"bank/async"
+	mockasync "bank/async/mock"
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestResendVerificationEmail(t *testing.T) {
+	user := randomUser("password")
+	verifiedUser := user
+	verifiedUser.IsVerified = pgtype.Bool{Bool: true, Valid: true}
+	limit := int64(3)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor)
+		checkResponse func(t *testing.T, res *pb.ResendVerificationEmailResponse, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ResendVerifyEmailTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.ResendVerifyEmailTxParams) error {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, limit, arg.Limit)
+						return arg.AfterRequest(ctx)
+					})
+				distributor.EXPECT().
+					DistributeTaskVerifyEmail(gomock.Any(), gomock.Eq(&async.PayloadSendVerifyEmail{UserID: user.ID}), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResendVerificationEmailResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+			},
+		},
+		{
+			name: "Already verified",
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(verifiedUser, nil)
+				store.EXPECT().ResendVerifyEmailTx(gomock.Any(), gomock.Any()).Times(0)
+				distributor.EXPECT().DistributeTaskVerifyEmail(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResendVerificationEmailResponse, err error) {
+				require.Nil(t, res)
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "Rate limited",
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().ResendVerifyEmailTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ErrTooManyRequests)
+				distributor.EXPECT().DistributeTaskVerifyEmail(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResendVerificationEmailResponse, err error) {
+				require.Nil(t, res)
+				require.Equal(t, codes.ResourceExhausted, status.Code(err))
+			},
+		},
+		{
+			name: "Queue down",
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ResendVerifyEmailTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.ResendVerifyEmailTxParams) error {
+						return arg.AfterRequest(ctx)
+					})
+				distributor.EXPECT().
+					DistributeTaskVerifyEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(errors.New("outbox is down"))
+			},
+			checkResponse: func(t *testing.T, res *pb.ResendVerificationEmailResponse, err error) {
+				require.Nil(t, res)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "User not found",
+			buildStubs: func(store *mockdb.MockStore, distributor *mockasync.MockTaskDistributor) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResendVerificationEmailResponse, err error) {
+				require.Nil(t, res)
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			store := mockdb.NewMockStore(ctrl)
+			distributor := mockasync.NewMockTaskDistributor(ctrl)
+			tc.buildStubs(store, distributor)
+
+			server := newTestServer(t, store, distributor)
+			server.config.VerifyEmailLimit = limit
+			server.config.VerifyEmailWindow = time.Hour
+
+			ctx := newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			res, err := callAuthorized(ctx, server, pb.Bank_ResendVerificationEmail_FullMethodName, server.ResendVerificationEmail, &pb.ResendVerificationEmailRequest{})
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
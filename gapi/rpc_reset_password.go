@@ -0,0 +1,74 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/utils"
+	"bank/validation"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ResetPassword sets a new password with the code of the emailed link.
+// The user is logged out of every session.
+func (server *Server) ResetPassword(ctx context.Context, r *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	if violations := validateResetPasswordRequest(r); violations != nil {
+		return nil, validationError(violations)
+	}
+
+	passwordReset, err := server.store.GetPasswordReset(ctx, r.GetId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.PermissionDenied, db.ErrPasswordResetInvalid.Error())
+		}
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to get password reset: %s", err.Error())
+	}
+
+	codeHash := utils.HashCode(r.GetCode())
+	if subtle.ConstantTimeCompare([]byte(codeHash), []byte(passwordReset.CodeHash)) != 1 ||
+		passwordReset.UsedAt.Valid || time.Now().After(passwordReset.ExpiredAt) {
+		return nil, status.Errorf(codes.PermissionDenied, db.ErrPasswordResetInvalid.Error())
+	}
+
+	hashedPassword, err := utils.HashedPassword(r.GetPassword())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "couldn't hash password")
+	}
+
+	result, err := server.store.ResetPasswordTx(ctx, db.ResetPasswordTxParams{
+		PasswordResetID: passwordReset.ID,
+		HashedPassword:  hashedPassword,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrPasswordResetInvalid) {
+			return nil, status.Errorf(codes.PermissionDenied, err.Error())
+		}
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to reset password: %s", err.Error())
+	}
+	server.revocation.Forget(result.User.ID)
+
+	return &pb.ResetPasswordResponse{}, nil
+}
+
+func validateResetPasswordRequest(r *pb.ResetPasswordRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if valErr := validation.ValidateID(r.GetId(), "id"); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+	if r.GetCode() == "" {
+		violations = append(violations, fieldViolation("code", fmt.Errorf("is required")))
+	}
+	if valErr := validation.ValidatePassword(r.GetPassword()); valErr != nil {
+		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+	}
+	return violations
+}
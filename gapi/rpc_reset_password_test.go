@@ -0,0 +1,131 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/utils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestResetPassword(t *testing.T) {
+	user := randomUser("password")
+	code := utils.RandomString(32)
+	newPassword := utils.RandomString(8)
+
+	passwordReset := db.PasswordReset{
+		ID:        utils.RandomInt(1, 1000),
+		UserID:    user.ID,
+		CodeHash:  utils.HashCode(code),
+		ExpiredAt: time.Now().Add(time.Minute),
+	}
+	used := passwordReset
+	used.UsedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	expired := passwordReset
+	expired.ExpiredAt = time.Now().Add(-time.Minute)
+
+	testCases := []struct {
+		name          string
+		code          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.ResetPasswordResponse, err error)
+	}{
+		{
+			name: "OK",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPasswordReset(gomock.Any(), gomock.Eq(passwordReset.ID)).Times(1).Return(passwordReset, nil)
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.ResetPasswordTxParams) (db.ResetPasswordTxResult, error) {
+						require.Equal(t, passwordReset.ID, arg.PasswordResetID)
+						require.NoError(t, utils.CompareHashAndPassword(arg.HashedPassword, newPassword))
+						return db.ResetPasswordTxResult{User: user}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Wrong code",
+			code: utils.RandomString(32),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPasswordReset(gomock.Any(), gomock.Eq(passwordReset.ID)).Times(1).Return(passwordReset, nil)
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "Used code",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPasswordReset(gomock.Any(), gomock.Eq(passwordReset.ID)).Times(1).Return(used, nil)
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "Expired code",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPasswordReset(gomock.Any(), gomock.Eq(passwordReset.ID)).Times(1).Return(expired, nil)
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "Used concurrently",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPasswordReset(gomock.Any(), gomock.Eq(passwordReset.ID)).Times(1).Return(passwordReset, nil)
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ResetPasswordTxResult{}, db.ErrPasswordResetInvalid)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "Not found",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPasswordReset(gomock.Any(), gomock.Eq(passwordReset.ID)).Times(1).Return(db.PasswordReset{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			res, err := server.ResetPassword(context.Background(), &pb.ResetPasswordRequest{
+				Id:       passwordReset.ID,
+				Code:     tc.code,
+				Password: newPassword,
+			})
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
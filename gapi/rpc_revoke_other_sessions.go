@@ -0,0 +1,25 @@
+package gapi
+
+import (
+	"bank/pb"
+	"context"
+)
+
+func (server *Server) RevokeOtherSessions(ctx context.Context, r *pb.RevokeOtherSessionsRequest) (*pb.RevokeOtherSessionsResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := server.sessionOfRefreshToken(ctx, r.GetRefreshToken(), authPayload.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, err := server.blockSessions(ctx, authPayload.UserID, nil, &current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RevokeOtherSessionsResponse{RevokedCount: blocked}, nil
+}
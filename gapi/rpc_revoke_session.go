@@ -0,0 +1,36 @@
+package gapi
+
+import (
+	"bank/pb"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) RevokeSession(ctx context.Context, r *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	familyID, err := uuid.Parse(r.GetSessionId())
+	if err != nil {
+		return nil, validationError([]*errdetails.BadRequest_FieldViolation{
+			fieldViolation("session_id", fmt.Errorf("must be a valid UUID")),
+		})
+	}
+
+	blocked, err := server.blockSessions(ctx, authPayload.UserID, &familyID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if blocked == 0 {
+		return nil, status.Errorf(codes.NotFound, "session not found")
+	}
+
+	return &pb.RevokeSessionResponse{}, nil
+}
@@ -0,0 +1,22 @@
+package gapi
+
+import (
+	"bank/pb"
+	"bank/validation"
+	"context"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) RevokeUserSessions(ctx context.Context, r *pb.RevokeUserSessionsRequest) (*pb.RevokeUserSessionsResponse, error) {
+	if valErr := validation.ValidateID(r.GetUserId(), "user_id"); valErr != nil {
+		return nil, validationError([]*errdetails.BadRequest_FieldViolation{fieldViolation(valErr.Field, valErr.Error)})
+	}
+
+	blocked, err := server.blockSessions(ctx, r.GetUserId(), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RevokeUserSessionsResponse{RevokedCount: blocked}, nil
+}
@@ -0,0 +1,90 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/utils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRevokeUserSessions(t *testing.T) {
+	user := randomUser("password")
+	banker := randomUser("password")
+	banker.Role = string(utils.Banker)
+
+	testCases := []struct {
+		name          string
+		params        *pb.RevokeUserSessionsRequest
+		buildStubs    func(store *mockdb.MockStore)
+		makeContext   func(server *Server) context.Context
+		checkResponse func(t *testing.T, res *pb.RevokeUserSessionsResponse, err error)
+	}{
+		{
+			name:   "OK",
+			params: &pb.RevokeUserSessionsRequest{UserId: user.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Eq(db.BlockUserSessionsParams{UserID: user.ID})).
+					Times(1).
+					Return(int64(3), nil)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, banker, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.RevokeUserSessionsResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(3), res.RevokedCount)
+			},
+		},
+		{
+			name:   "Depositor",
+			params: &pb.RevokeUserSessionsRequest{UserId: user.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BlockUserSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.RevokeUserSessionsResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name:   "Invalid user ID",
+			params: &pb.RevokeUserSessionsRequest{UserId: 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BlockUserSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, banker, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.RevokeUserSessionsResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ctrl := gomock.NewController(t)
+
+		store := mockdb.NewMockStore(ctrl)
+
+		tc.buildStubs(store)
+
+		server := newTestServer(t, store, nil)
+
+		res, err := callAuthorized(tc.makeContext(server), server, pb.Bank_RevokeUserSessions_FullMethodName, server.RevokeUserSessions, tc.params)
+
+		tc.checkResponse(t, res, err)
+	}
+}
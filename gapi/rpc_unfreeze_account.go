@@ -0,0 +1,16 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"context"
+)
+
+func (server *Server) UnfreezeAccount(ctx context.Context, r *pb.UnfreezeAccountRequest) (*pb.UnfreezeAccountResponse, error) {
+	account, err := server.changeAccountStatus(ctx, r.GetAccountId(), db.AccountStatusActive, r.GetReason())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UnfreezeAccountResponse{Account: account}, nil
+}
@@ -0,0 +1,46 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/validation"
+	"context"
+	"errors"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnlockUser lifts the login lockouts of the user before they run out.
+func (server *Server) UnlockUser(ctx context.Context, r *pb.UnlockUserRequest) (*pb.UnlockUserResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if valErr := validation.ValidateID(r.GetUserId(), "user_id"); valErr != nil {
+		return nil, validationError([]*errdetails.BadRequest_FieldViolation{fieldViolation(valErr.Field, valErr.Error)})
+	}
+
+	user, err := server.store.GetUser(ctx, r.GetUserId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err.Error())
+	}
+
+	unlocked, err := server.store.UnlockUserLoginTx(ctx, db.UnlockUserLoginTxParams{
+		UserID:     user.ID,
+		Email:      user.Email,
+		UnlockedBy: authPayload.UserID,
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to unlock user: %s", err.Error())
+	}
+
+	return &pb.UnlockUserResponse{UnlockedCount: unlocked}, nil
+}
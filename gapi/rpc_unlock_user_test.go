@@ -0,0 +1,83 @@
+package gapi
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/pb"
+	"bank/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnlockUser(t *testing.T) {
+	banker := randomUser("password")
+	banker.Role = string(utils.Banker)
+	user := randomUser("password")
+
+	testCases := []struct {
+		name          string
+		caller        db.User
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.UnlockUserResponse, err error)
+	}{
+		{
+			name:   "OK",
+			caller: banker,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					UnlockUserLoginTx(gomock.Any(), gomock.Eq(db.UnlockUserLoginTxParams{
+						UserID:     user.ID,
+						Email:      user.Email,
+						UnlockedBy: banker.ID,
+					})).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.UnlockUserResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(1), res.GetUnlockedCount())
+			},
+		},
+		{
+			name:   "Not a banker",
+			caller: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UnlockUserLoginTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.UnlockUserResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name:   "User not found",
+			caller: banker,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().UnlockUserLoginTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.UnlockUserResponse, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := newContextWithAuthMetadata(t, server, tc.caller, time.Minute, authHeader, authBearer)
+			res, err := callAuthorized(ctx, server, pb.Bank_UnlockUser_FullMethodName, server.UnlockUser, &pb.UnlockUserRequest{UserId: user.ID})
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/notify"
+	"bank/pb"
+	"context"
+	"fmt"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpdateNotificationPreferences sets the channels of the given events and returns the preferences
+// of the caller for every event.
+func (server *Server) UpdateNotificationPreferences(ctx context.Context, r *pb.UpdateNotificationPreferencesRequest) (*pb.UpdateNotificationPreferencesResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if violations := validateUpdateNotificationPreferencesRequest(r); violations != nil {
+		return nil, validationError(violations)
+	}
+
+	for _, preference := range r.GetPreferences() {
+		_, err = server.store.UpsertNotificationPreference(ctx, db.UpsertNotificationPreferenceParams{
+			UserID: authPayload.UserID,
+			Event:  preference.GetEvent(),
+			InApp:  preference.GetInApp(),
+			Email:  preference.GetEmail(),
+		})
+		if err != nil {
+			log.Println(err)
+			return nil, status.Errorf(codes.Internal, "failed to update notification preferences: %s", err.Error())
+		}
+	}
+
+	preferences, err := notify.Preferences(ctx, server.store, authPayload.UserID)
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to get notification preferences: %s", err.Error())
+	}
+
+	return &pb.UpdateNotificationPreferencesResponse{
+		Preferences: convertNotificationPreferences(preferences),
+	}, nil
+}
+
+func validateUpdateNotificationPreferencesRequest(r *pb.UpdateNotificationPreferencesRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if len(r.GetPreferences()) == 0 {
+		return append(violations, fieldViolation("preferences", fmt.Errorf("are required")))
+	}
+
+	seen := make(map[string]bool, len(r.GetPreferences()))
+	for _, preference := range r.GetPreferences() {
+		event := preference.GetEvent()
+		if !notify.IsEventSupported(event) {
+			violations = append(violations, fieldViolation("preferences.event", fmt.Errorf("unsupported event %q", event)))
+			continue
+		}
+		if seen[event] {
+			violations = append(violations, fieldViolation("preferences.event", fmt.Errorf("event %q is repeated", event)))
+		}
+		seen[event] = true
+	}
+	return violations
+}
@@ -1,6 +1,8 @@
 package gapi
 
 import (
+	"bank/async"
+	mockasync "bank/async/mock"
 	mockdb "bank/db/mock"
 	db "bank/db/sqlc"
 	"bank/pb"
@@ -13,8 +15,18 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// eqUpdateUserTxParams matches the tx params by their update, as the callback can't be compared.
+func eqUpdateUserTxParams(args db.UpdateUserParams) gomock.Matcher {
+	return gomock.Cond(func(x any) bool {
+		params, isOk := x.(db.UpdateUserTxParams)
+		return isOk && params.UpdateUserParams == args
+	})
+}
+
 func TestUpdateUser(t *testing.T) {
 	password := "password"
 	user := randomUser(password)
@@ -23,6 +35,8 @@ func TestUpdateUser(t *testing.T) {
 
 	newName := utils.RandomString(10)
 	newEmail := utils.RandomEmail()
+	newLocale := "ru"
+	badLocale := "klingon"
 
 	testCases := []struct {
 		name          string
@@ -54,9 +68,9 @@ func TestUpdateUser(t *testing.T) {
 				}
 
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Eq(args)).
+					UpdateUserTx(gomock.Any(), eqUpdateUserTxParams(args)).
 					Times(1).
-					Return(updatedUser, nil)
+					Return(db.UpdateUserTxResult{User: updatedUser}, nil)
 			},
 			makeContext: func(server *Server) context.Context {
 				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
@@ -70,6 +84,52 @@ func TestUpdateUser(t *testing.T) {
 				require.Equal(t, newName, gotUser.FullName)
 			},
 		},
+		{
+			name: "Locale",
+			params: pb.UpdateUserRequest{
+				Id:     user.ID,
+				Locale: &newLocale,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				args := db.UpdateUserParams{
+					ID:     user.ID,
+					Locale: pgtype.Text{String: newLocale, Valid: true},
+				}
+
+				updatedUser := user
+				updatedUser.Locale = newLocale
+
+				store.EXPECT().
+					UpdateUserTx(gomock.Any(), eqUpdateUserTxParams(args)).
+					Times(1).
+					Return(db.UpdateUserTxResult{User: updatedUser}, nil)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, newLocale, res.User.Locale)
+			},
+		},
+		{
+			name: "Unsupported locale",
+			params: pb.UpdateUserRequest{
+				Id:     user.ID,
+				Locale: &badLocale,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			makeContext: func(server *Server) context.Context {
+				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
 		{
 			name: "Banker update",
 			params: pb.UpdateUserRequest{
@@ -93,9 +153,9 @@ func TestUpdateUser(t *testing.T) {
 				}
 
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Eq(args)).
+					UpdateUserTx(gomock.Any(), eqUpdateUserTxParams(args)).
 					Times(1).
-					Return(updatedUser, nil)
+					Return(db.UpdateUserTxResult{User: updatedUser}, nil)
 			},
 			makeContext: func(server *Server) context.Context {
 				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
@@ -157,9 +217,9 @@ func TestUpdateUser(t *testing.T) {
 				}
 
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Eq(args)).
+					UpdateUserTx(gomock.Any(), eqUpdateUserTxParams(args)).
 					Times(1).
-					Return(db.User{}, sql.ErrNoRows)
+					Return(db.UpdateUserTxResult{}, db.ErrRecordNotFound)
 			},
 			makeContext: func(server *Server) context.Context {
 				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
@@ -184,9 +244,9 @@ func TestUpdateUser(t *testing.T) {
 				}
 
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Eq(args)).
+					UpdateUserTx(gomock.Any(), eqUpdateUserTxParams(args)).
 					Times(1).
-					Return(db.User{}, sql.ErrConnDone)
+					Return(db.UpdateUserTxResult{}, sql.ErrConnDone)
 			},
 			makeContext: func(server *Server) context.Context {
 				return newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
@@ -209,8 +269,47 @@ func TestUpdateUser(t *testing.T) {
 
 		ctx := tc.makeContext(server)
 
-		res, err := server.UpdateUser(ctx, &tc.params)
+		res, err := callAuthorized(ctx, server, pb.Bank_UpdateUser_FullMethodName, server.UpdateUser, &tc.params)
 
 		tc.checkResponse(t, res, err)
 	}
 }
+
+func TestUpdateUserEmailChange(t *testing.T) {
+	user := randomUser("password")
+	newEmail := utils.RandomEmail()
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	args := db.UpdateUserParams{
+		ID:    user.ID,
+		Email: pgtype.Text{String: newEmail, Valid: true},
+	}
+	updatedUser := user
+	updatedUser.Email = newEmail
+	store.EXPECT().
+		UpdateUserTx(gomock.Any(), eqUpdateUserTxParams(args)).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, params db.UpdateUserTxParams) (db.UpdateUserTxResult, error) {
+			if err := params.AfterEmailChange(ctx, updatedUser); err != nil {
+				return db.UpdateUserTxResult{}, err
+			}
+			return db.UpdateUserTxResult{User: updatedUser, EmailChanged: true}, nil
+		})
+
+	distributor := mockasync.NewMockTaskDistributor(ctrl)
+	distributor.EXPECT().
+		DistributeTaskVerifyEmail(gomock.Any(), gomock.Eq(&async.PayloadSendVerifyEmail{UserID: user.ID}), gomock.Any()).
+		Times(1).
+		Return(nil)
+
+	server := newTestServer(t, store, distributor)
+	ctx := newContextWithAuthMetadata(t, server, user, time.Minute, authHeader, authBearer)
+	res, err := callAuthorized(ctx, server, pb.Bank_UpdateUser_FullMethodName, server.UpdateUser, &pb.UpdateUserRequest{
+		Id:    user.ID,
+		Email: &newEmail,
+	})
+	require.NoError(t, err)
+	require.Equal(t, newEmail, res.User.Email)
+	require.False(t, res.User.IsVerified)
+}
@@ -4,9 +4,9 @@ import (
 	db "bank/db/sqlc"
 	"bank/pb"
 	"context"
+	"errors"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -42,16 +42,16 @@ func (server *Server) VerifyEmail(ctx context.Context, r *pb.VerifyEmailRequest)
 		return nil, status.Errorf(codes.Internal, "something went wrong")
 	}
 
-	user, err := server.store.UpdateUser(ctx, db.UpdateUserParams{
-		ID: verifyEmail.UserID,
-		IsVerified: pgtype.Bool{
-			Bool:  true,
-			Valid: true,
-		},
+	user, err := server.store.VerifyUserEmail(ctx, db.VerifyUserEmailParams{
+		ID:    verifyEmail.UserID,
+		Email: verifyEmail.Email,
 	})
 
 	if err != nil {
-		log.Err(err).Msg("update_user_failed")
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.FailedPrecondition, "verification code is for a previous email")
+		}
+		log.Err(err).Msg("verify_user_email_failed")
 		return nil, status.Errorf(codes.Internal, "something went wrong")
 	}
 
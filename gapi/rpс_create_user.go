@@ -32,11 +32,13 @@ func (server *Server) CreateUser(ctx context.Context, r *pb.CreateUserRequest) (
 		HashedPassword: hashedPassword,
 		FullName:       r.GetFullName(),
 		Email:          r.GetEmail(),
+		Locale:         r.GetLocale(),
 	}
 
 	result, err := server.store.CreateUserTX(ctx, db.CreateUserTxParams{
 		CreateUserParams: arg,
-		AfterCreate: func(user db.User) error {
+		// ctx carries the transaction, so that the outbox distributor writes the task along with the user
+		AfterCreate: func(ctx context.Context, user db.User) error {
 			payload := &async.PayloadSendVerifyEmail{UserID: user.ID}
 			opts := []asynq.Option{
 				asynq.ProcessIn(10 * time.Second),
@@ -77,6 +79,11 @@ func validateCreateUserRequest(r *pb.CreateUserRequest) (violations []*errdetail
 	if valErr := validation.ValidateUsername(r.GetUsername()); valErr != nil {
 		violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
 	}
+	if r.Locale != nil {
+		if valErr := validation.ValidateLocale(r.GetLocale()); valErr != nil {
+			violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+		}
+	}
 
 	return violations
 }
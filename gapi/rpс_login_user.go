@@ -1,30 +1,40 @@
 package gapi
 
 import (
+	"bank/async"
 	db "bank/db/sqlc"
+	"bank/mfa"
 	"bank/pb"
+	"bank/token"
 	"bank/utils"
 	"bank/validation"
 	"context"
-	"database/sql"
 	"errors"
 	"log"
-	"time"
 
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+var errInvalidCredentials = errors.New("invalid email or password")
+
 func (server *Server) LoginUser(ctx context.Context, r *pb.LoginUserRequest) (*pb.LoginUserResponse, error) {
 	if violations := validateLoginUserRequest(r); violations != nil {
 		return nil, validationError(violations)
 	}
+	clientIP := server.extractMedadata(ctx).ClientIP
+	if err := server.loginGuard.Check(ctx, r.GetEmail(), clientIP); err != nil {
+		return nil, loginAttemptError(err)
+	}
+
 	user, err := server.store.GetUserByEmail(ctx, r.GetEmail())
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, status.Errorf(codes.NotFound, "user is not found")
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, server.failLogin(ctx, r.GetEmail(), clientIP, nil)
 		}
 		log.Println(err)
 		return nil, status.Errorf(codes.Internal, err.Error())
@@ -32,29 +42,87 @@ func (server *Server) LoginUser(ctx context.Context, r *pb.LoginUserRequest) (*p
 
 	err = utils.CompareHashAndPassword(user.HashedPassword, r.GetPassword())
 	if err != nil {
-		return nil, status.Errorf(codes.PermissionDenied, "wrong password")
+		return nil, server.failLogin(ctx, r.GetEmail(), clientIP, &user.ID)
+	}
+
+	mfaRequired, err := mfa.Enabled(ctx, server.store, user.ID)
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to check two-factor authentication: %s", err.Error())
+	}
+	if mfaRequired {
+		return server.createMFAChallenge(ctx, user)
+	}
+
+	return server.createLoginTokens(ctx, user)
+}
+
+// failLogin records the failed attempt. An unknown email and a wrong password get the same answer,
+// so that the accounts can't be enumerated.
+func (server *Server) failLogin(ctx context.Context, email, clientIP string, userID *int64) error {
+	if err := server.loginGuard.Fail(ctx, email, clientIP, userID); err != nil {
+		log.Println(err)
+	}
+	return status.Errorf(codes.Unauthenticated, errInvalidCredentials.Error())
+}
+
+// createMFAChallenge starts the second step of the login, finished by LoginUserMFA.
+func (server *Server) createMFAChallenge(ctx context.Context, user db.User) (*pb.LoginUserResponse, error) {
+	challenge, err := mfa.StartChallenge(ctx, server.store, user.ID, server.config.MFAChallengeDuration)
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to create mfa challenge: %s", err.Error())
+	}
+
+	return &pb.LoginUserResponse{
+		MfaRequired:       true,
+		MfaToken:          challenge.ID.String(),
+		MfaTokenExpiresAt: timestamppb.New(challenge.ExpiresAt),
+	}, nil
+}
+
+// createLoginTokens starts a new session of the user and issues its token pair.
+// Only then has the login succeeded, with the second factor if any, and the failures are forgotten.
+// A login from a new device is reported to the user by email.
+func (server *Server) createLoginTokens(ctx context.Context, user db.User) (*pb.LoginUserResponse, error) {
+	if err := server.loginGuard.Succeed(ctx, user.Email); err != nil {
+		log.Println(err)
 	}
 
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), server.config.AccessTokenDuration)
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), token.TokenTypeRefresh, uuid.Nil, server.config.RefreshTokenDuration)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
 
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), server.config.RefreshTokenDuration)
+	// the refresh token starts a new session family, the access token is bound to it
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.ID, utils.Role(user.Role), token.TokenTypeAccess, refreshPayload.ID, server.config.AccessTokenDuration)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
 
+	// the access token authenticates only while its session exists, so the login fails without one
 	meta := server.extractMedadata(ctx)
-	server.store.CreateSession(ctx, db.CreateSessionParams{
-		ID:           refreshPayload.ID,
-		UserID:       user.ID,
-		RefreshToken: refreshToken,
-		UserAgent:    meta.UserAgent,
-		ClientIp:     meta.ClientIP,
-		IsBlocked:    false,
-		ExpiresAt:    time.Now().Add(server.config.RefreshTokenDuration),
+	_, err = server.store.CreateLoginSessionTx(ctx, db.CreateLoginSessionTxParams{
+		CreateSessionParams: db.CreateSessionParams{
+			ID:           refreshPayload.ID,
+			UserID:       user.ID,
+			RefreshToken: refreshToken,
+			UserAgent:    meta.UserAgent,
+			ClientIp:     meta.ClientIP,
+			IsBlocked:    false,
+			ExpiresAt:    refreshPayload.ExpiresAt,
+			FamilyID:     refreshPayload.ID,
+		},
+		// ctx carries the transaction, so that the alert is written to the outbox along with the session
+		AfterNewDevice: func(ctx context.Context, session db.Session) error {
+			payload := &async.PayloadNotifyNewDeviceLogin{SessionID: session.ID}
+			return server.taskDistributor.DistributeTaskNotifyNewDeviceLogin(ctx, payload, asynq.MaxRetry(10))
+		},
 	})
+	if err != nil {
+		log.Println(err)
+		return nil, status.Errorf(codes.Internal, "failed to create session: %s", err.Error())
+	}
 
 	return &pb.LoginUserResponse{
 		AccessToken:           accessToken,
@@ -1,15 +1,17 @@
 package gapi
 
 import (
+	"bank/async"
 	db "bank/db/sqlc"
 	"bank/pb"
+	"bank/rbac"
 	"bank/utils"
 	"bank/validation"
 	"context"
-	"database/sql"
 	"errors"
 	"log"
 
+	"github.com/hibiken/asynq"
 	"github.com/jackc/pgx/v5/pgtype"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
@@ -17,22 +19,29 @@ import (
 )
 
 func (server *Server) UpdateUser(ctx context.Context, r *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
-	authPayload, err := server.authorizeUser(ctx, []utils.Role{utils.Banker, utils.Depositor})
+	authPayload, err := authPayloadFromContext(ctx)
 	if err != nil {
-		return nil, unauthenticatedError(err)
+		return nil, err
 	}
 	if violations := validateUpdateUserRequest(r); violations != nil {
 		return nil, validationError(violations)
 	}
 
-	if authPayload.Role != utils.Banker && authPayload.UserID != r.GetId() {
-		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	if authPayload.UserID != r.GetId() {
+		updateAny, err := server.hasPermission(ctx, authPayload, rbac.UsersUpdateAny)
+		if err != nil {
+			return nil, err
+		}
+		if !updateAny {
+			return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+		}
 	}
 
 	arg := db.UpdateUserParams{
 		ID:       r.GetId(),
 		FullName: pgtype.Text{String: r.GetFullName(), Valid: r.FullName != nil},
 		Email:    pgtype.Text{String: r.GetEmail(), Valid: r.Email != nil},
+		Locale:   pgtype.Text{String: r.GetLocale(), Valid: r.Locale != nil},
 	}
 
 	if r.Password != nil {
@@ -43,17 +52,27 @@ func (server *Server) UpdateUser(ctx context.Context, r *pb.UpdateUserRequest) (
 		arg.HashedPassword = pgtype.Text{String: hashedPassword, Valid: true}
 	}
 
-	user, err := server.store.UpdateUser(ctx, arg)
+	result, err := server.store.UpdateUserTx(ctx, db.UpdateUserTxParams{
+		UpdateUserParams: arg,
+		// a changed email is unverified until the user follows the code sent to it
+		AfterEmailChange: func(ctx context.Context, user db.User) error {
+			payload := &async.PayloadSendVerifyEmail{UserID: user.ID}
+			return server.taskDistributor.DistributeTaskVerifyEmail(ctx, payload, asynq.MaxRetry(5))
+		},
+	})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, db.ErrRecordNotFound) {
 			return nil, status.Errorf(codes.NotFound, "user not found")
 		}
 		log.Println(err)
 		return nil, status.Errorf(codes.Internal, "failed to update user: %s ", err.Error())
 	}
+	if r.Password != nil {
+		server.revocation.Forget(result.User.ID)
+	}
 
 	return &pb.UpdateUserResponse{
-		User: convertUser(user),
+		User: convertUser(result.User),
 	}, nil
 }
 
@@ -68,6 +87,11 @@ func validateUpdateUserRequest(r *pb.UpdateUserRequest) (violations []*errdetail
 			violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
 		}
 	}
+	if r.Locale != nil {
+		if valErr := validation.ValidateLocale(r.GetLocale()); valErr != nil {
+			violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
+		}
+	}
 	if r.Password != nil {
 		if valErr := validation.ValidatePassword(r.GetPassword()); valErr != nil {
 			violations = append(violations, fieldViolation(valErr.Field, valErr.Error))
@@ -3,7 +3,10 @@ package gapi
 import (
 	"bank/async"
 	db "bank/db/sqlc"
+	"bank/fx"
+	"bank/lockout"
 	"bank/pb"
+	"bank/rbac"
 	"bank/token"
 	"bank/utils"
 )
@@ -12,20 +15,33 @@ type Server struct {
 	pb.UnimplementedBankServer
 	store           db.Store
 	tokenMaker      token.Maker
+	revocation      *token.RevocationChecker
+	authorizer      *rbac.Authorizer
 	config          *utils.Config
 	taskDistributor async.TaskDistributor
+	fxRateProvider  fx.FXRateProvider
+	loginGuard      *lockout.Guard
 }
 
-func NewServer(config utils.Config, store db.Store, taskDistributor async.TaskDistributor) (*Server, error) {
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+// NewServer takes the revocation checker from the caller, which shares it between the servers of the process.
+func NewServer(config utils.Config, store db.Store, taskDistributor async.TaskDistributor, revocation *token.RevocationChecker) (*Server, error) {
+	tokenMaker, err := token.NewMaker(config)
+	if err != nil {
+		return nil, err
+	}
+	fxRateProvider, err := fx.NewRateProvider(config, store)
 	if err != nil {
 		return nil, err
 	}
 	server := &Server{
 		store:           store,
 		tokenMaker:      tokenMaker,
+		revocation:      revocation,
+		authorizer:      rbac.NewAuthorizer(store, config.PermissionCacheTTL),
 		config:          &config,
 		taskDistributor: taskDistributor,
+		fxRateProvider:  fxRateProvider,
+		loginGuard:      lockout.NewGuard(store, config),
 	}
 
 	return server, nil
@@ -0,0 +1,70 @@
+package gapi
+
+import (
+	db "bank/db/sqlc"
+	"bank/token"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sessionOfRefreshToken finds the session the refresh token was issued for
+// and makes sure it belongs to the user. The returned error is already a gRPC status.
+func (server *Server) sessionOfRefreshToken(ctx context.Context, refreshToken string, userID int64) (db.Session, error) {
+	if refreshToken == "" {
+		return db.Session{}, validationError([]*errdetails.BadRequest_FieldViolation{
+			fieldViolation("refresh_token", fmt.Errorf("is required")),
+		})
+	}
+
+	payload, err := server.tokenMaker.VerifyToken(refreshToken)
+	if err != nil {
+		return db.Session{}, unauthenticatedError(err)
+	}
+	if err = payload.CheckType(token.TokenTypeRefresh); err != nil {
+		return db.Session{}, unauthenticatedError(err)
+	}
+
+	session, err := server.store.GetSession(ctx, payload.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return session, unauthenticatedError(db.ErrSessionNotFound)
+		}
+		log.Println(err)
+		return session, status.Errorf(codes.Internal, "failed to get session: %s", err.Error())
+	}
+
+	if session.UserID != userID || session.RefreshToken != refreshToken {
+		return session, unauthenticatedError(db.ErrRefreshTokenInvalid)
+	}
+
+	return session, nil
+}
+
+// blockSessions blocks the sessions of the user, optionally narrowed down to one family
+// or to every family but one, and returns how many rows were blocked.
+func (server *Server) blockSessions(ctx context.Context, userID int64, familyID, exceptFamilyID *uuid.UUID) (int64, error) {
+	arg := db.BlockUserSessionsParams{UserID: userID}
+	if familyID != nil {
+		arg.FamilyID = pgtype.UUID{Bytes: *familyID, Valid: true}
+	}
+	if exceptFamilyID != nil {
+		arg.ExceptFamilyID = pgtype.UUID{Bytes: *exceptFamilyID, Valid: true}
+	}
+
+	blocked, err := server.store.BlockUserSessions(ctx, arg)
+	if err != nil {
+		log.Println(err)
+		return 0, status.Errorf(codes.Internal, "failed to revoke sessions: %s", err.Error())
+	}
+	server.revocation.Forget(userID)
+
+	return blocked, nil
+}
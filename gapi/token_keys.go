@@ -0,0 +1,122 @@
+package gapi
+
+import (
+	"bank/token"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	TokenKeysPath = "/v1/token_keys"
+	JWKSPath      = "/.well-known/jwks.json"
+
+	// verifiers may cache the keys for this long; a token signed with an unknown key ID
+	// should make them fetch the keys again, which is how they pick up a rotation.
+	tokenKeysMaxAge = 5 * time.Minute
+)
+
+type tokenKey struct {
+	KeyID     string     `json:"kid"`
+	Algorithm string     `json:"alg"`
+	PublicKey string     `json:"public_key"`
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	NotAfter  *time.Time `json:"not_after,omitempty"`
+}
+
+type tokenKeysResponse struct {
+	Keys []tokenKey `json:"keys"`
+}
+
+type jwk struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	X         string `json:"x"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// TokenKeysHandler publishes the keys that verify access tokens, with their validity windows.
+// The list is empty when tokens are signed with a shared secret.
+func (server *Server) TokenKeysHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		algorithm := "v4.public"
+		if _, isJWT := server.tokenMaker.(*token.JWTMaker); isJWT {
+			algorithm = "EdDSA"
+		}
+
+		response := tokenKeysResponse{Keys: []tokenKey{}}
+		for _, key := range publicKeys(server.tokenMaker) {
+			published := tokenKey{
+				KeyID:     key.ID,
+				Algorithm: algorithm,
+				PublicKey: hex.EncodeToString(key.Key),
+			}
+			if !key.NotBefore.IsZero() {
+				published.NotBefore = &key.NotBefore
+			}
+			if !key.NotAfter.IsZero() {
+				published.NotAfter = &key.NotAfter
+			}
+			response.Keys = append(response.Keys, published)
+		}
+
+		writeKeys(w, response)
+	})
+}
+
+// JWKSHandler publishes the keys of JWTMaker as a JSON Web Key Set.
+func (server *Server) JWKSHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if _, isJWT := server.tokenMaker.(*token.JWTMaker); !isJWT {
+			http.NotFound(w, r)
+			return
+		}
+
+		response := jwks{Keys: []jwk{}}
+		for _, key := range publicKeys(server.tokenMaker) {
+			response.Keys = append(response.Keys, jwk{
+				KeyType:   "OKP",
+				Curve:     "Ed25519",
+				KeyID:     key.ID,
+				Use:       "sig",
+				Algorithm: "EdDSA",
+				X:         base64.RawURLEncoding.EncodeToString(key.Key),
+			})
+		}
+
+		writeKeys(w, response)
+	})
+}
+
+func publicKeys(maker token.Maker) []token.PublicKey {
+	publisher, isOk := maker.(token.KeyPublisher)
+	if !isOk {
+		return nil
+	}
+	return publisher.PublicKeys()
+}
+
+func writeKeys(w http.ResponseWriter, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(tokenKeysMaxAge.Seconds())))
+	json.NewEncoder(w).Encode(response)
+}
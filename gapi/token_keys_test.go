@@ -0,0 +1,91 @@
+package gapi
+
+import (
+	"bank/token"
+	"bank/utils"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func randomSigningKey() token.SigningKey {
+	secretKey := paseto.NewV4AsymmetricSecretKey()
+	return token.SigningKey{
+		ID:        utils.RandomString(8),
+		SecretKey: &secretKey,
+		PublicKey: secretKey.Public(),
+	}
+}
+
+func TestTokenKeysHandler(t *testing.T) {
+	key := randomSigningKey()
+	maker, err := token.NewPasetoPublicMaker([]token.SigningKey{key}, time.Hour)
+	require.NoError(t, err)
+
+	server := newTestServer(t, nil, nil)
+	server.tokenMaker = maker
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, TokenKeysPath, nil)
+	server.TokenKeysHandler().ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Header().Get("Cache-Control"), "max-age=")
+
+	var response tokenKeysResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Keys, 1)
+	require.Equal(t, key.ID, response.Keys[0].KeyID)
+	require.Equal(t, "v4.public", response.Keys[0].Algorithm)
+	require.Equal(t, hex.EncodeToString(key.PublicKey.ExportBytes()), response.Keys[0].PublicKey)
+	require.Nil(t, response.Keys[0].NotAfter)
+
+	// the published key verifies the tokens of the maker
+	accessToken, _, err := maker.CreateToken(1, utils.Depositor, token.TokenTypeAccess, uuid.Nil, time.Minute)
+	require.NoError(t, err)
+	publicKey, err := paseto.NewV4AsymmetricPublicKeyFromHex(response.Keys[0].PublicKey)
+	require.NoError(t, err)
+	_, err = paseto.NewParser().ParseV4Public(publicKey, accessToken, nil)
+	require.NoError(t, err)
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodPost, TokenKeysPath, nil)
+	server.TokenKeysHandler().ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestJWKSHandler(t *testing.T) {
+	key := randomSigningKey()
+	maker, err := token.NewJWTPublicMaker([]token.SigningKey{key}, time.Hour)
+	require.NoError(t, err)
+
+	server := newTestServer(t, nil, nil)
+
+	// there is no JWKS for PASETO tokens
+	recorder := httptest.NewRecorder()
+	server.JWKSHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, JWKSPath, nil))
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	server.tokenMaker = maker
+
+	recorder = httptest.NewRecorder()
+	server.JWKSHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, JWKSPath, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response jwks
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Keys, 1)
+	require.Equal(t, key.ID, response.Keys[0].KeyID)
+	require.Equal(t, "OKP", response.Keys[0].KeyType)
+	require.Equal(t, "Ed25519", response.Keys[0].Curve)
+	require.Equal(t, "EdDSA", response.Keys[0].Algorithm)
+	require.Equal(t, base64.RawURLEncoding.EncodeToString(key.PublicKey.ExportBytes()), response.Keys[0].X)
+}
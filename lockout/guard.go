@@ -0,0 +1,178 @@
+package lockout
+
+import (
+	db "bank/db/sqlc"
+	"bank/utils"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrLocked          = errors.New("too many failed login attempts, login is temporarily locked")
+	ErrTooManyAttempts = errors.New("too many failed login attempts")
+)
+
+// AttemptError tells when the login may be attempted again.
+type AttemptError struct {
+	Err     error
+	RetryAt time.Time
+}
+
+func (e *AttemptError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", e.Err, e.RetryAt.Format(time.RFC3339))
+}
+
+func (e *AttemptError) Unwrap() error {
+	return e.Err
+}
+
+// Guard slows down password guessing. Failed logins are tracked per email and per client IP:
+// every failure doubles the wait before the next attempt, the email is locked out
+// after LOGIN_MAX_FAILURES failures and the IP is turned away after LOGIN_IP_MAX_FAILURES.
+// The email typed in is tracked whether it belongs to a user or not, so the answers don't tell them apart.
+// The guard is off when LOGIN_FAILURE_WINDOW is not set.
+type Guard struct {
+	store           db.Store
+	maxFailures     int64
+	ipMaxFailures   int64
+	window          time.Duration
+	lockoutDuration time.Duration
+	delayBase       time.Duration
+	delayMax        time.Duration
+}
+
+func NewGuard(store db.Store, config utils.Config) *Guard {
+	return &Guard{
+		store:           store,
+		maxFailures:     config.LoginMaxFailures,
+		ipMaxFailures:   config.LoginIPMaxFailures,
+		window:          config.LoginFailureWindow,
+		lockoutDuration: config.LoginLockoutDuration,
+		delayBase:       config.LoginDelayBase,
+		delayMax:        config.LoginDelayMax,
+	}
+}
+
+// Check returns an *AttemptError if the login must not be attempted now.
+func (g *Guard) Check(ctx context.Context, email, clientIP string) error {
+	if g.window <= 0 {
+		return nil
+	}
+
+	lockout, err := g.store.GetActiveLoginLockout(ctx, email)
+	if err == nil {
+		return &AttemptError{Err: ErrLocked, RetryAt: lockout.LockedUntil}
+	}
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		return err
+	}
+
+	stats, err := g.store.GetLoginFailureStats(ctx, db.GetLoginFailureStatsParams{
+		Email:    email,
+		ClientIp: clientHost(clientIP),
+		Since:    time.Now().Add(-g.window),
+	})
+	if err != nil {
+		return err
+	}
+
+	retryAt := stats.EmailLastFailure.Add(g.delay(stats.EmailFailures))
+	if ipRetryAt := stats.IpLastFailure.Add(g.delay(stats.IpFailures)); ipRetryAt.After(retryAt) {
+		retryAt = ipRetryAt
+	}
+	if g.ipMaxFailures > 0 && stats.IpFailures >= g.ipMaxFailures {
+		retryAt = stats.IpLastFailure.Add(g.window)
+	}
+
+	if time.Now().Before(retryAt) {
+		return &AttemptError{Err: ErrTooManyAttempts, RetryAt: retryAt}
+	}
+
+	return nil
+}
+
+// Fail records a failed login and locks the email out once it reaches the limit.
+// userID is nil when the email does not belong to any user.
+func (g *Guard) Fail(ctx context.Context, email, clientIP string, userID *int64) error {
+	if g.window <= 0 {
+		return nil
+	}
+
+	clientIP = clientHost(clientIP)
+	err := g.store.CreateLoginFailure(ctx, db.CreateLoginFailureParams{
+		Email:    email,
+		ClientIp: clientIP,
+	})
+	if err != nil {
+		return err
+	}
+
+	if g.maxFailures <= 0 {
+		return nil
+	}
+
+	stats, err := g.store.GetLoginFailureStats(ctx, db.GetLoginFailureStatsParams{
+		Email:    email,
+		ClientIp: clientIP,
+		Since:    time.Now().Add(-g.window),
+	})
+	if err != nil {
+		return err
+	}
+	if stats.EmailFailures < g.maxFailures {
+		return nil
+	}
+
+	arg := db.CreateLoginLockoutParams{
+		Email:          email,
+		ClientIp:       clientIP,
+		FailedAttempts: int32(stats.EmailFailures),
+		LockedUntil:    time.Now().Add(g.lockoutDuration),
+	}
+	if userID != nil {
+		arg.UserID = pgtype.Int8{Int64: *userID, Valid: true}
+	}
+	_, err = g.store.LockLoginTx(ctx, db.LockLoginTxParams{CreateLoginLockoutParams: arg})
+	return err
+}
+
+// Succeed forgets the failures of the email after a successful login.
+func (g *Guard) Succeed(ctx context.Context, email string) error {
+	if g.window <= 0 {
+		return nil
+	}
+	return g.store.ClearLoginFailures(ctx, email)
+}
+
+// delay is the wait after the given number of failures: none before the first failure,
+// then delayBase doubled with every further failure, up to delayMax.
+func (g *Guard) delay(failures int64) time.Duration {
+	if failures <= 0 || g.delayBase <= 0 {
+		return 0
+	}
+
+	delay := g.delayBase
+	for i := int64(1); i < failures; i++ {
+		delay *= 2
+		if g.delayMax > 0 && delay >= g.delayMax {
+			return g.delayMax
+		}
+	}
+	if g.delayMax > 0 && delay > g.delayMax {
+		return g.delayMax
+	}
+	return delay
+}
+
+// clientHost drops the port, so that the failures of one client are counted together.
+func clientHost(clientIP string) string {
+	if host, _, err := net.SplitHostPort(clientIP); err == nil {
+		return host
+	}
+	return clientIP
+}
@@ -0,0 +1,171 @@
+package lockout
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/utils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestGuard(store db.Store) *Guard {
+	return NewGuard(store, utils.Config{
+		LoginMaxFailures:     3,
+		LoginIPMaxFailures:   10,
+		LoginFailureWindow:   time.Hour,
+		LoginLockoutDuration: 15 * time.Minute,
+		LoginDelayBase:       time.Second,
+		LoginDelayMax:        30 * time.Second,
+	})
+}
+
+func TestDelay(t *testing.T) {
+	guard := newTestGuard(nil)
+
+	require.Zero(t, guard.delay(0))
+	require.Equal(t, time.Second, guard.delay(1))
+	require.Equal(t, 2*time.Second, guard.delay(2))
+	require.Equal(t, 16*time.Second, guard.delay(5))
+	require.Equal(t, 30*time.Second, guard.delay(6))
+	require.Equal(t, 30*time.Second, guard.delay(100))
+}
+
+func TestCheck(t *testing.T) {
+	email := utils.RandomEmail()
+	now := time.Now()
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkError func(t *testing.T, err error)
+	}{
+		{
+			name: "No failures",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveLoginLockout(gomock.Any(), gomock.Eq(email)).Times(1).Return(db.LoginLockout{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					GetLoginFailureStats(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.GetLoginFailureStatsParams) (db.GetLoginFailureStatsRow, error) {
+						require.Equal(t, "10.0.0.1", arg.ClientIp)
+						return db.GetLoginFailureStatsRow{EmailLastFailure: time.Unix(0, 0), IpLastFailure: time.Unix(0, 0)}, nil
+					})
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Locked",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetActiveLoginLockout(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(db.LoginLockout{Email: email, LockedUntil: now.Add(time.Minute)}, nil)
+				store.EXPECT().GetLoginFailureStats(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrLocked)
+				var attemptErr *AttemptError
+				require.ErrorAs(t, err, &attemptErr)
+				require.WithinDuration(t, now.Add(time.Minute), attemptErr.RetryAt, time.Second)
+			},
+		},
+		{
+			name: "Email delayed",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveLoginLockout(gomock.Any(), gomock.Eq(email)).Times(1).Return(db.LoginLockout{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					GetLoginFailureStats(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetLoginFailureStatsRow{EmailFailures: 2, EmailLastFailure: now, IpFailures: 2, IpLastFailure: now}, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrTooManyAttempts)
+			},
+		},
+		{
+			name: "Delay passed",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveLoginLockout(gomock.Any(), gomock.Eq(email)).Times(1).Return(db.LoginLockout{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					GetLoginFailureStats(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetLoginFailureStatsRow{EmailFailures: 2, EmailLastFailure: now.Add(-3 * time.Second), IpFailures: 2, IpLastFailure: now.Add(-3 * time.Second)}, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "IP over the limit",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveLoginLockout(gomock.Any(), gomock.Eq(email)).Times(1).Return(db.LoginLockout{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					GetLoginFailureStats(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetLoginFailureStatsRow{EmailLastFailure: time.Unix(0, 0), IpFailures: 10, IpLastFailure: now.Add(-time.Minute)}, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrTooManyAttempts)
+				var attemptErr *AttemptError
+				require.ErrorAs(t, err, &attemptErr)
+				require.WithinDuration(t, now.Add(59*time.Minute), attemptErr.RetryAt, time.Second)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			err := newTestGuard(store).Check(context.Background(), email, "10.0.0.1:51234")
+			tc.checkError(t, err)
+		})
+	}
+}
+
+func TestFail(t *testing.T) {
+	email := utils.RandomEmail()
+	userID := utils.RandomInt(1, 1000)
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	guard := newTestGuard(store)
+
+	store.EXPECT().CreateLoginFailure(gomock.Any(), gomock.Eq(db.CreateLoginFailureParams{Email: email, ClientIp: "10.0.0.1"})).Times(2)
+	gomock.InOrder(
+		store.EXPECT().GetLoginFailureStats(gomock.Any(), gomock.Any()).Times(1).Return(db.GetLoginFailureStatsRow{EmailFailures: 2}, nil),
+		store.EXPECT().GetLoginFailureStats(gomock.Any(), gomock.Any()).Times(1).Return(db.GetLoginFailureStatsRow{EmailFailures: 3}, nil),
+	)
+	store.EXPECT().
+		LockLoginTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.LockLoginTxParams) (db.LockLoginTxResult, error) {
+			require.Equal(t, email, arg.Email)
+			require.Equal(t, userID, arg.UserID.Int64)
+			require.Equal(t, int32(3), arg.FailedAttempts)
+			require.WithinDuration(t, time.Now().Add(15*time.Minute), arg.LockedUntil, time.Second)
+			return db.LockLoginTxResult{}, nil
+		})
+
+	// the lockout comes with the failure that reaches the limit
+	require.NoError(t, guard.Fail(context.Background(), email, "10.0.0.1", &userID))
+	require.NoError(t, guard.Fail(context.Background(), email, "10.0.0.1", &userID))
+}
+
+func TestGuardDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	guard := NewGuard(store, utils.Config{})
+
+	require.NoError(t, guard.Check(context.Background(), utils.RandomEmail(), "10.0.0.1"))
+	require.NoError(t, guard.Fail(context.Background(), utils.RandomEmail(), "10.0.0.1", nil))
+	require.NoError(t, guard.Succeed(context.Background(), utils.RandomEmail()))
+}
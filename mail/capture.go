@@ -0,0 +1,83 @@
+package mail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Message is an email kept by CaptureSender.
+type Message struct {
+	Email
+	// Raw is the message as it would go over SMTP
+	Raw []byte
+	// Path is the .eml file of the message, empty if the sender keeps messages in memory only
+	Path string
+}
+
+// CaptureSender doesn't deliver the emails. It keeps them in memory and, when given a directory,
+// writes them there as .eml files that any mail client opens. Meant for local runs and tests.
+type CaptureSender struct {
+	fromName    string
+	fromAddress string
+	dir         string
+
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewCaptureSender creates the directory if needed; an empty dir keeps the messages in memory only.
+func NewCaptureSender(fromName, fromAddress, dir string) (*CaptureSender, error) {
+	if fromAddress == "" {
+		return nil, errFromAddressRequired
+	}
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("cannot create email capture dir: %w", err)
+		}
+	}
+	return &CaptureSender{fromName: fromName, fromAddress: fromAddress, dir: dir}, nil
+}
+
+// Send implements EmailSender.
+func (sender *CaptureSender) Send(email Email) error {
+	letter, err := newEmail(sender.fromName, sender.fromAddress, email)
+	if err != nil {
+		return err
+	}
+	raw, err := letter.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to build an email: %w", err)
+	}
+
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+
+	message := Message{Email: email, Raw: raw}
+	if sender.dir != "" {
+		name := fmt.Sprintf("%s-%04d.eml", time.Now().UTC().Format("20060102T150405.000000000"), len(sender.messages)+1)
+		message.Path = filepath.Join(sender.dir, name)
+		if err = os.WriteFile(message.Path, raw, 0o644); err != nil {
+			return fmt.Errorf("failed to write email %s: %w", message.Path, err)
+		}
+	}
+	sender.messages = append(sender.messages, message)
+
+	return nil
+}
+
+// Messages returns the emails sent so far, the oldest first.
+func (sender *CaptureSender) Messages() []Message {
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+	return append([]Message(nil), sender.messages...)
+}
+
+// Reset forgets the emails sent so far. The .eml files stay.
+func (sender *CaptureSender) Reset() {
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+	sender.messages = nil
+}
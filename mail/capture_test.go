@@ -0,0 +1,60 @@
+package mail
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureSender(t *testing.T) {
+	dir := t.TempDir()
+	sender, err := NewCaptureSender("Mini Bank", "noreply@example.com", dir)
+	require.NoError(t, err)
+
+	err = sender.Send(Email{
+		Subject: "Hello",
+		HTML:    "<h1>Hello</h1>",
+		Text:    "Hello",
+		To:      []string{"to@example.com"},
+		Cc:      []string{"cc@example.com"},
+		Bcc:     []string{"bcc@example.com"},
+	})
+	require.NoError(t, err)
+	err = sender.Send(Email{Subject: "Bye", HTML: "<h1>Bye</h1>", To: []string{"to@example.com"}})
+	require.NoError(t, err)
+
+	messages := sender.Messages()
+	require.Len(t, messages, 2)
+	require.Equal(t, "Hello", messages[0].Subject)
+	require.Equal(t, []string{"bcc@example.com"}, messages[0].Bcc)
+	require.Equal(t, "Bye", messages[1].Subject)
+
+	raw, err := os.ReadFile(messages[0].Path)
+	require.NoError(t, err)
+	require.Equal(t, messages[0].Raw, raw)
+	require.Contains(t, string(raw), "Subject: Hello")
+	require.Contains(t, string(raw), "From: \"Mini Bank\" <noreply@example.com>")
+	require.NotContains(t, string(raw), "bcc@example.com")
+	require.Contains(t, string(raw), "multipart/alternative")
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	sender.Reset()
+	require.Empty(t, sender.Messages())
+}
+
+func TestCaptureSenderInMemory(t *testing.T) {
+	sender, err := NewCaptureSender("Mini Bank", "noreply@example.com", "")
+	require.NoError(t, err)
+
+	err = sender.Send(Email{Subject: "Hello", HTML: "<h1>Hello</h1>", To: []string{"to@example.com"}})
+	require.NoError(t, err)
+
+	messages := sender.Messages()
+	require.Len(t, messages, 1)
+	require.Empty(t, messages[0].Path)
+	require.NotEmpty(t, messages[0].Raw)
+}
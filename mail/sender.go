@@ -1,59 +1,74 @@
 package mail
 
 import (
+	"bank/utils"
+	"errors"
 	"fmt"
-	"net/smtp"
 
 	"github.com/jordan-wright/email"
 )
 
 const (
-	smtpAuthAddress   = "smtp.gmail.com"
-	smtpServerAddress = "smtp.gmail.com:587"
+	SenderSMTP    = "smtp"
+	SenderCapture = "capture"
 )
 
+var errFromAddressRequired = errors.New("email from address is required")
+
+// Email is a message with an HTML body and its plain-text alternative.
+type Email struct {
+	Subject     string
+	HTML        string
+	Text        string
+	To          []string
+	Cc          []string
+	Bcc         []string
+	AttachFiles []string
+}
+
 type EmailSender interface {
-	Send(subject string, content string, to []string, cc []string, bcc []string, attachFiles []string) error
+	Send(email Email) error
 }
 
-type GmailSender struct {
-	name              string
-	fromEmailAddr     string
-	fromEmailPassword string
+// NewSender picks the sender named by config.EmailSender, SMTP by default.
+func NewSender(config utils.Config) (EmailSender, error) {
+	switch config.EmailSender {
+	case SenderCapture:
+		sender, err := NewCaptureSender(config.EmailFromName, config.EmailFromAddress, config.EmailCaptureDir)
+		if err != nil {
+			return nil, err
+		}
+		return sender, nil
+	case SenderSMTP, "":
+		return NewSMTPSender(SMTPConfig{
+			Host:        config.SMTPHost,
+			Port:        config.SMTPPort,
+			Username:    config.SMTPUsername,
+			Password:    config.SMTPPassword,
+			AuthMode:    config.SMTPAuth,
+			TLSMode:     config.SMTPTLS,
+			FromName:    config.EmailFromName,
+			FromAddress: config.EmailFromAddress,
+		})
+	}
+	return nil, fmt.Errorf("unknown email sender %q", config.EmailSender)
 }
 
-// Send implements EmailSender.
-func (g *GmailSender) Send(
-	subject string,
-	content string,
-	to []string,
-	cc []string,
-	bcc []string,
-	attachFiles []string,
-) error {
+// newEmail builds the message the senders deliver.
+func newEmail(fromName string, fromAddress string, message Email) (*email.Email, error) {
 	letter := email.NewEmail()
-	letter.From = fmt.Sprintf("%s <%s>", g.name, g.fromEmailAddr)
-	letter.To = to
-	letter.Subject = subject
-	letter.HTML = []byte(content)
-	letter.Cc = cc
-	letter.Bcc = bcc
-	for _, filename := range attachFiles {
+	letter.From = fmt.Sprintf("%s <%s>", fromName, fromAddress)
+	letter.To = message.To
+	letter.Subject = message.Subject
+	letter.HTML = []byte(message.HTML)
+	letter.Text = []byte(message.Text)
+	letter.Cc = message.Cc
+	letter.Bcc = message.Bcc
+	for _, filename := range message.AttachFiles {
 		_, err := letter.AttachFile(filename)
 		if err != nil {
-			return fmt.Errorf("failed to attach file %s: %w", filename, err)
+			return nil, fmt.Errorf("failed to attach file %s: %w", filename, err)
 		}
 	}
-
-	smtpAuth := smtp.PlainAuth("", g.fromEmailAddr, g.fromEmailPassword, smtpAuthAddress)
-	err := letter.Send(smtpServerAddress, smtpAuth)
-	if err != nil {
-		return fmt.Errorf("failed to send an email via gmail: %w", err)
-	}
-
-	return nil
-}
-
-func NewGmailSender(name, fromEmailAddr, fromEmailPassword string) EmailSender {
-	return &GmailSender{name, fromEmailAddr, fromEmailPassword}
+	return letter, nil
 }
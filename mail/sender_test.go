@@ -14,18 +14,45 @@ func TestSendEmail(t *testing.T) {
 	config, err := utils.LoadConfig("..")
 	require.NoError(t, err)
 
-	gmail := NewGmailSender(config.GmailName, config.GmailFrom, config.GmailAccPassword)
-
-	err = gmail.Send(
-		"Test",
-		`
-			<h1>Test mail</h1>
-		`,
-		[]string{"gegviktor@yandex.ru"},
-		nil,
-		nil,
-		[]string{"../README.md"},
-	)
+	sender, err := NewSender(config)
+	require.NoError(t, err)
+
+	err = sender.Send(Email{
+		Subject:     "Test",
+		HTML:        "<h1>Test mail</h1>",
+		Text:        "Test mail",
+		To:          []string{"gegviktor@yandex.ru"},
+		AttachFiles: []string{"../README.md"},
+	})
+
+	require.NoError(t, err)
+}
+
+func TestNewSenderFromAppEnv(t *testing.T) {
+	// the checked-in config must start without any email credentials
+	config, err := utils.LoadConfig("..")
+	require.NoError(t, err)
 
+	sender, err := NewSender(config)
 	require.NoError(t, err)
+	require.IsType(t, &CaptureSender{}, sender)
+}
+
+func TestNewSender(t *testing.T) {
+	sender, err := NewSender(utils.Config{EmailSender: SenderCapture, EmailFromAddress: "noreply@example.com"})
+	require.NoError(t, err)
+	require.IsType(t, &CaptureSender{}, sender)
+
+	_, err = NewSender(utils.Config{EmailSender: SenderCapture})
+	require.ErrorIs(t, err, errFromAddressRequired)
+
+	sender, err = NewSender(utils.Config{SMTPHost: "localhost", SMTPPort: 1025, SMTPAuth: AuthNone, SMTPTLS: TLSNone, EmailFromAddress: "noreply@example.com"})
+	require.NoError(t, err)
+	require.IsType(t, &SMTPSender{}, sender)
+
+	_, err = NewSender(utils.Config{SMTPHost: "localhost", SMTPPort: 1025, SMTPTLS: "ssl3", EmailFromAddress: "noreply@example.com"})
+	require.ErrorContains(t, err, "tls mode")
+
+	_, err = NewSender(utils.Config{EmailSender: "pigeon"})
+	require.ErrorContains(t, err, "unknown email sender")
 }
@@ -0,0 +1,196 @@
+package mail
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	AuthNone    = "none"
+	AuthPlain   = "plain"
+	AuthLogin   = "login"
+	AuthCRAMMD5 = "crammd5"
+
+	// TLSNone talks plain SMTP, TLSStartTLS upgrades the connection and refuses servers
+	// that can't, TLSImplicit connects over TLS straight away (usually port 465).
+	TLSNone     = "none"
+	TLSStartTLS = "starttls"
+	TLSImplicit = "tls"
+)
+
+const smtpDialTimeout = 10 * time.Second
+
+// SMTPConfig describes the SMTP server the emails are relayed through.
+type SMTPConfig struct {
+	Host        string
+	Port        int
+	Username    string
+	Password    string
+	AuthMode    string
+	TLSMode     string
+	FromName    string
+	FromAddress string
+}
+
+// SMTPSender delivers the emails to any SMTP server: a mail provider or a local stand-in like MailHog.
+type SMTPSender struct {
+	config SMTPConfig
+	auth   smtp.Auth
+}
+
+// NewSMTPSender validates the config. Empty modes default to the plain auth over STARTTLS,
+// every auth mode but AuthNone requires the credentials.
+func NewSMTPSender(config SMTPConfig) (EmailSender, error) {
+	if config.Host == "" || config.Port == 0 {
+		return nil, errors.New("smtp host and port are required")
+	}
+	if config.FromAddress == "" {
+		return nil, errFromAddressRequired
+	}
+
+	switch config.TLSMode {
+	case "":
+		config.TLSMode = TLSStartTLS
+	case TLSNone, TLSStartTLS, TLSImplicit:
+	default:
+		return nil, fmt.Errorf("unknown smtp tls mode %q", config.TLSMode)
+	}
+
+	sender := &SMTPSender{config: config}
+	switch config.AuthMode {
+	case AuthPlain, "":
+		sender.auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	case AuthLogin:
+		sender.auth = &loginAuth{username: config.Username, password: config.Password}
+	case AuthCRAMMD5:
+		sender.auth = smtp.CRAMMD5Auth(config.Username, config.Password)
+	case AuthNone:
+	default:
+		return nil, fmt.Errorf("unknown smtp auth mode %q", config.AuthMode)
+	}
+	if sender.auth != nil && (config.Username == "" || config.Password == "") {
+		return nil, errors.New("smtp username and password are required unless the auth mode is none")
+	}
+
+	return sender, nil
+}
+
+// Send implements EmailSender.
+func (sender *SMTPSender) Send(message Email) error {
+	letter, err := newEmail(sender.config.FromName, sender.config.FromAddress, message)
+	if err != nil {
+		return err
+	}
+	raw, err := letter.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to build an email: %w", err)
+	}
+
+	recipients := make([]string, 0, len(message.To)+len(message.Cc)+len(message.Bcc))
+	recipients = append(append(append(recipients, message.To...), message.Cc...), message.Bcc...)
+
+	if err = sender.deliver(raw, recipients); err != nil {
+		return fmt.Errorf("failed to send an email via %s: %w", sender.config.Host, err)
+	}
+	return nil
+}
+
+func (sender *SMTPSender) deliver(message []byte, recipients []string) error {
+	client, err := sender.dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if sender.config.TLSMode == TLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("server doesn't support STARTTLS")
+		}
+		if err = client.StartTLS(&tls.Config{ServerName: sender.config.Host}); err != nil {
+			return err
+		}
+	}
+
+	if sender.auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("server doesn't support AUTH")
+		}
+		if err = client.Auth(sender.auth); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(sender.config.FromAddress); err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err = client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write(message); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (sender *SMTPSender) dial() (*smtp.Client, error) {
+	addr := net.JoinHostPort(sender.config.Host, strconv.Itoa(sender.config.Port))
+	dialer := &net.Dialer{Timeout: smtpDialTimeout}
+
+	var conn net.Conn
+	var err error
+	if sender.config.TLSMode == TLSImplicit {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: sender.config.Host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := smtp.NewClient(conn, sender.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// loginAuth implements the LOGIN mechanism that net/smtp lacks but e.g. Office 365 requires.
+type loginAuth struct {
+	username string
+	password string
+}
+
+func (auth *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	return "LOGIN", nil, nil
+}
+
+func (auth *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(auth.username), nil
+	case "password:":
+		return []byte(auth.password), nil
+	}
+	return nil, fmt.Errorf("unexpected server challenge %q", fromServer)
+}
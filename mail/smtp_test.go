@@ -0,0 +1,175 @@
+package mail
+
+import (
+	"encoding/base64"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// smtpSession is what the fake server got from the client.
+type smtpSession struct {
+	auth       string
+	from       string
+	recipients []string
+	data       string
+}
+
+// startFakeSMTPServer serves one plain SMTP session on localhost that advertises AUTH PLAIN and LOGIN.
+func startFakeSMTPServer(t *testing.T) (port int, sessions <-chan smtpSession) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	result := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		var session smtpSession
+		text.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.Fields(line + " ")[0])
+			switch command {
+			case "EHLO", "HELO":
+				text.PrintfLine("250-localhost")
+				text.PrintfLine("250 AUTH PLAIN LOGIN")
+			case "AUTH":
+				session.auth = readAuth(text, line)
+				text.PrintfLine("235 authenticated")
+			case "MAIL":
+				session.from = line
+				text.PrintfLine("250 ok")
+			case "RCPT":
+				session.recipients = append(session.recipients, line)
+				text.PrintfLine("250 ok")
+			case "DATA":
+				text.PrintfLine("354 go ahead")
+				data, err := text.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				session.data = string(data)
+				text.PrintfLine("250 queued")
+			case "QUIT":
+				text.PrintfLine("221 bye")
+				result <- session
+				return
+			default:
+				text.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, result
+}
+
+// readAuth returns the decoded credentials of AUTH PLAIN or AUTH LOGIN.
+func readAuth(text *textproto.Conn, line string) string {
+	fields := strings.Fields(line)
+	if strings.ToUpper(fields[1]) == "PLAIN" {
+		decoded, _ := base64.StdEncoding.DecodeString(fields[2])
+		return "PLAIN " + strings.ReplaceAll(string(decoded), "\x00", " ")
+	}
+
+	text.PrintfLine("334 " + base64.StdEncoding.EncodeToString([]byte("Username:")))
+	username, _ := text.ReadLine()
+	text.PrintfLine("334 " + base64.StdEncoding.EncodeToString([]byte("Password:")))
+	password, _ := text.ReadLine()
+	decodedUsername, _ := base64.StdEncoding.DecodeString(username)
+	decodedPassword, _ := base64.StdEncoding.DecodeString(password)
+	return "LOGIN " + string(decodedUsername) + " " + string(decodedPassword)
+}
+
+func TestSMTPSender(t *testing.T) {
+	testCases := []struct {
+		name     string
+		authMode string
+		wantAuth string
+	}{
+		{name: "No auth", authMode: AuthNone, wantAuth: ""},
+		{name: "Plain auth", authMode: AuthPlain, wantAuth: "PLAIN  user secret"},
+		{name: "Login auth", authMode: AuthLogin, wantAuth: "LOGIN user secret"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			port, sessions := startFakeSMTPServer(t)
+
+			sender, err := NewSMTPSender(SMTPConfig{
+				Host:        "127.0.0.1",
+				Port:        port,
+				Username:    "user",
+				Password:    "secret",
+				AuthMode:    tc.authMode,
+				TLSMode:     TLSNone,
+				FromName:    "Mini Bank",
+				FromAddress: "noreply@example.com",
+			})
+			require.NoError(t, err)
+
+			err = sender.Send(Email{
+				Subject: "Hello",
+				HTML:    "<h1>Hello</h1>",
+				To:      []string{"to@example.com"},
+				Bcc:     []string{"bcc@example.com"},
+			})
+			require.NoError(t, err)
+
+			session := <-sessions
+			require.Equal(t, tc.wantAuth, session.auth)
+			require.Equal(t, "MAIL FROM:<noreply@example.com>", session.from)
+			require.Equal(t, []string{"RCPT TO:<to@example.com>", "RCPT TO:<bcc@example.com>"}, session.recipients)
+			require.Contains(t, session.data, "Subject: Hello")
+			require.NotContains(t, session.data, "bcc@example.com")
+		})
+	}
+}
+
+func TestSMTPSenderRequiresStartTLS(t *testing.T) {
+	port, _ := startFakeSMTPServer(t)
+
+	sender, err := NewSMTPSender(SMTPConfig{
+		Host:        "127.0.0.1",
+		Port:        port,
+		AuthMode:    AuthNone,
+		TLSMode:     TLSStartTLS,
+		FromAddress: "noreply@example.com",
+	})
+	require.NoError(t, err)
+
+	err = sender.Send(Email{Subject: "Hello", HTML: "<h1>Hello</h1>", To: []string{"to@example.com"}})
+	require.ErrorContains(t, err, "STARTTLS")
+}
+
+func TestNewSMTPSender(t *testing.T) {
+	_, err := NewSMTPSender(SMTPConfig{Port: 25})
+	require.Error(t, err)
+
+	_, err = NewSMTPSender(SMTPConfig{Host: "localhost", Port: 25, Username: "user", Password: "secret"})
+	require.ErrorIs(t, err, errFromAddressRequired)
+
+	_, err = NewSMTPSender(SMTPConfig{Host: "localhost", Port: 25, AuthMode: "oauth", FromAddress: "noreply@example.com"})
+	require.ErrorContains(t, err, "auth mode")
+
+	_, err = NewSMTPSender(SMTPConfig{Host: "localhost", Port: 25, FromAddress: "noreply@example.com"})
+	require.ErrorContains(t, err, "username and password are required")
+
+	_, err = NewSMTPSender(SMTPConfig{Host: "localhost", Port: 25, AuthMode: AuthNone, FromAddress: "noreply@example.com"})
+	require.NoError(t, err)
+
+	sender, err := NewSMTPSender(SMTPConfig{Host: "localhost", Port: 25, Username: "user", Password: "secret", FromAddress: "noreply@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, TLSStartTLS, sender.(*SMTPSender).config.TLSMode)
+}
@@ -0,0 +1,181 @@
+package mail
+
+import (
+	"bank/utils"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// The names of the email templates. Each one is a templates/<locale>/<name>.tmpl file
+// that defines the "subject", "html" and "text" blocks.
+const (
+	TemplateVerifyEmail      = "verify_email"
+	TemplatePasswordReset    = "password_reset"
+	TemplateTransferSent     = "transfer_sent"
+	TemplateTransferReceived = "transfer_received"
+	TemplateNewDeviceLogin   = "new_device_login"
+)
+
+// layoutFile wraps the "html" block of every email, so the branding lives in one place.
+const layoutFile = "layout.html.tmpl"
+
+//go:embed templates
+var embeddedTemplates embed.FS
+
+// VerifyEmailData is rendered by TemplateVerifyEmail.
+type VerifyEmailData struct {
+	FullName         string
+	Link             string
+	ExpiresInMinutes int
+}
+
+// PasswordResetData is rendered by TemplatePasswordReset.
+type PasswordResetData struct {
+	FullName         string
+	Link             string
+	ExpiresInMinutes int
+}
+
+// TransferSentData is rendered by TemplateTransferSent.
+type TransferSentData struct {
+	FullName      string
+	Amount        string
+	FromAccountID int64
+	ToAccountID   int64
+}
+
+// TransferReceivedData is rendered by TemplateTransferReceived.
+type TransferReceivedData struct {
+	FullName      string
+	Amount        string
+	FromAccountID int64
+	ToAccountID   int64
+}
+
+// NewDeviceLoginData is rendered by TemplateNewDeviceLogin.
+type NewDeviceLoginData struct {
+	FullName  string
+	Time      string
+	ClientIP  string
+	UserAgent string
+}
+
+// Renderer renders the localized email templates. The templates are embedded in the binary;
+// the files of an override directory with the same layout take precedence over them.
+type Renderer struct {
+	fsys          fs.FS
+	defaultLocale string
+	appName       string
+}
+
+// NewRenderer creates a renderer over the embedded templates and the optional override dir.
+func NewRenderer(overrideDir, defaultLocale, appName string) (*Renderer, error) {
+	embedded, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+
+	fsys := fs.FS(embedded)
+	if overrideDir != "" {
+		if info, err := os.Stat(overrideDir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("email templates dir %s is not a directory", overrideDir)
+		}
+		fsys = overlayFS{override: os.DirFS(overrideDir), base: embedded}
+	}
+
+	if defaultLocale == "" {
+		defaultLocale = utils.DefaultLocale
+	}
+
+	return &Renderer{fsys: fsys, defaultLocale: defaultLocale, appName: appName}, nil
+}
+
+// NewRendererFromConfig creates the renderer configured by the EMAIL_* settings.
+func NewRendererFromConfig(config utils.Config) (*Renderer, error) {
+	return NewRenderer(config.EmailTemplatesDir, config.EmailDefaultLocale, config.EmailFromName)
+}
+
+// Render fills the subject and both bodies of the email in the locale of the user.
+// A locale without the template falls back to its language, then to the default locale.
+// The templates are parsed on every call, so edits of the override dir apply without a restart.
+func (renderer *Renderer) Render(name, locale string, data any) (Email, error) {
+	source, err := renderer.readTemplate(name, locale)
+	if err != nil {
+		return Email{}, err
+	}
+	layout, err := fs.ReadFile(renderer.fsys, layoutFile)
+	if err != nil {
+		return Email{}, fmt.Errorf("cannot read email layout: %w", err)
+	}
+
+	funcs := map[string]any{"appName": func() string { return renderer.appName }}
+
+	text, err := texttemplate.New(name).Funcs(funcs).Parse(source)
+	if err != nil {
+		return Email{}, fmt.Errorf("cannot parse email template %s: %w", name, err)
+	}
+	html, err := htmltemplate.New(name).Funcs(funcs).Parse(string(layout))
+	if err == nil {
+		_, err = html.Parse(source)
+	}
+	if err != nil {
+		return Email{}, fmt.Errorf("cannot parse email template %s: %w", name, err)
+	}
+
+	var subject, textBody, htmlBody bytes.Buffer
+	if err = text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Email{}, fmt.Errorf("cannot render email template %s: %w", name, err)
+	}
+	if err = text.ExecuteTemplate(&textBody, "text", data); err != nil {
+		return Email{}, fmt.Errorf("cannot render email template %s: %w", name, err)
+	}
+	if err = html.ExecuteTemplate(&htmlBody, "layout", data); err != nil {
+		return Email{}, fmt.Errorf("cannot render email template %s: %w", name, err)
+	}
+
+	return Email{
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    htmlBody.String(),
+		Text:    strings.TrimSpace(textBody.String()) + "\n",
+	}, nil
+}
+
+// readTemplate looks the template up in the locale, its language and the default locales in turn.
+func (renderer *Renderer) readTemplate(name, locale string) (string, error) {
+	candidates := []string{locale, utils.LocaleLanguage(locale), renderer.defaultLocale, utils.DefaultLocale}
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		source, err := fs.ReadFile(renderer.fsys, path.Join(candidate, name+".tmpl"))
+		if err == nil {
+			return string(source), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("cannot read email template %s: %w", name, err)
+		}
+	}
+	return "", fmt.Errorf("email template %s not found", name)
+}
+
+// overlayFS serves the files of override and falls back to base for the missing ones.
+type overlayFS struct {
+	override fs.FS
+	base     fs.FS
+}
+
+func (overlay overlayFS) Open(name string) (fs.File, error) {
+	file, err := overlay.override.Open(name)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return file, err
+	}
+	return overlay.base.Open(name)
+}
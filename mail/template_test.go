@@ -0,0 +1,100 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplates(t *testing.T) {
+	renderer, err := NewRenderer("", "en", "Mini Bank")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		data any
+		// text is a value of the data the text part must show
+		text string
+	}{
+		{TemplateVerifyEmail, VerifyEmailData{FullName: "John Doe", Link: "https://bank.example.com/verify", ExpiresInMinutes: 15}, "https://bank.example.com/verify"},
+		{TemplatePasswordReset, PasswordResetData{FullName: "John Doe", Link: "https://bank.example.com/reset", ExpiresInMinutes: 30}, "https://bank.example.com/reset"},
+		{TemplateTransferSent, TransferSentData{FullName: "John Doe", Amount: "$10.00", FromAccountID: 1, ToAccountID: 2}, "$10.00"},
+		{TemplateTransferReceived, TransferReceivedData{FullName: "John Doe", Amount: "$10.00", FromAccountID: 1, ToAccountID: 2}, "$10.00"},
+		{TemplateNewDeviceLogin, NewDeviceLoginData{FullName: "John Doe", Time: "2024-01-02 15:04 UTC", ClientIP: "10.0.0.1", UserAgent: "curl"}, "10.0.0.1"},
+	}
+
+	for _, tc := range testCases {
+		for _, locale := range []string{"en", "ru"} {
+			t.Run(tc.name+"/"+locale, func(t *testing.T) {
+				email, err := renderer.Render(tc.name, locale, tc.data)
+				require.NoError(t, err)
+				require.NotEmpty(t, email.Subject)
+				require.NotContains(t, email.Subject, "\n")
+				require.Contains(t, email.HTML, "Mini Bank")
+				require.Contains(t, email.HTML, "John Doe")
+				require.Contains(t, email.Text, "John Doe")
+				require.Contains(t, email.Text, tc.text)
+				require.NotContains(t, email.Text, "<p>")
+			})
+		}
+	}
+}
+
+func TestRenderLocaleFallback(t *testing.T) {
+	renderer, err := NewRenderer("", "ru", "Mini Bank")
+	require.NoError(t, err)
+	data := VerifyEmailData{FullName: "John Doe", Link: "https://bank.example.com/verify", ExpiresInMinutes: 15}
+
+	email, err := renderer.Render(TemplateVerifyEmail, "en-GB", data)
+	require.NoError(t, err)
+	require.Equal(t, "Verify your email", email.Subject)
+
+	email, err = renderer.Render(TemplateVerifyEmail, "de", data)
+	require.NoError(t, err)
+	require.Equal(t, "Подтвердите email", email.Subject)
+
+	email, err = renderer.Render(TemplateVerifyEmail, "", data)
+	require.NoError(t, err)
+	require.Equal(t, "Подтвердите email", email.Subject)
+
+	_, err = renderer.Render("unknown", "en", data)
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestRenderEscapesHTML(t *testing.T) {
+	renderer, err := NewRenderer("", "en", "Mini Bank")
+	require.NoError(t, err)
+
+	email, err := renderer.Render(TemplateVerifyEmail, "en", VerifyEmailData{FullName: "<script>", Link: "https://bank.example.com/verify"})
+	require.NoError(t, err)
+	require.NotContains(t, email.HTML, "<script>")
+	require.Contains(t, email.Text, "<script>")
+}
+
+func TestRenderOverrideDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "layout.html.tmpl"),
+		[]byte(`{{define "layout"}}<div class="rebranded">{{appName}} {{template "html" .}}</div>{{end}}`), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "en"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "en", "password_reset.tmpl"),
+		[]byte(`{{define "subject"}}New password{{end}}{{define "html"}}<a href="{{.Link}}">reset</a>{{end}}{{define "text"}}{{.Link}}{{end}}`), 0o644))
+
+	renderer, err := NewRenderer(dir, "en", "Mini Bank")
+	require.NoError(t, err)
+
+	email, err := renderer.Render(TemplatePasswordReset, "en", PasswordResetData{Link: "https://bank.example.com/reset"})
+	require.NoError(t, err)
+	require.Equal(t, "New password", email.Subject)
+	require.Contains(t, email.HTML, `class="rebranded"`)
+
+	// the templates missing from the dir come from the embedded ones
+	email, err = renderer.Render(TemplateVerifyEmail, "en", VerifyEmailData{Link: "https://bank.example.com/verify"})
+	require.NoError(t, err)
+	require.Equal(t, "Verify your email", email.Subject)
+	require.Contains(t, email.HTML, `class="rebranded"`)
+
+	_, err = NewRenderer(filepath.Join(dir, "missing"), "en", "Mini Bank")
+	require.Error(t, err)
+}
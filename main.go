@@ -6,11 +6,13 @@ import (
 	"bank/gapi"
 	"bank/mail"
 	"bank/pb"
+	"bank/token"
 	"bank/utils"
 	"context"
 	"errors"
 	"net"
 	"net/http"
+	"strings"
 
 	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog/log"
@@ -22,6 +24,7 @@ import (
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -45,21 +48,36 @@ func main() {
 	migrateDB(config.MigrationURL, config.DBURI)
 
 	store := db.NewDBStore(connPool)
+	if err = db.LoadCurrencies(ctx, store); err != nil {
+		log.Fatal().Err(err).Msg("cannot load currencies")
+	}
 
 	redisOpt := asynq.RedisClientOpt{
 		Addr: config.RedisAddr,
 	}
-	taskDistributor := async.NewRedisTaskDistributor(redisOpt)
+	// tasks go through the outbox, so that they are never lost or sent for a rolled back transaction
+	taskDistributor := async.NewOutboxTaskDistributor(store)
 
+	go async.NewOutboxRelay(store, redisOpt, config.OutboxRelayInterval).Run(ctx)
 	go runTaskProcessor(config, redisOpt, store)
 
-	go runGatewayServer(config, store, taskDistributor)
-	startGRPCerver(config, store, taskDistributor)
+	// both servers share the checker, so that a revoked session is forgotten by both
+	revocation := token.NewRevocationChecker(store, config.RevocationCacheTTL)
+
+	go runGatewayServer(config, store, taskDistributor, revocation)
+	startGRPCerver(config, store, taskDistributor, revocation)
 }
 
 func runTaskProcessor(config utils.Config, redisOpt asynq.RedisClientOpt, store db.Store) {
-	mailSender := mail.NewGmailSender(config.GmailName, config.GmailFrom, config.GmailAccPassword)
-	taskProcessor := async.NewRedisTaskProcessor(redisOpt, store, mailSender)
+	mailSender, err := mail.NewSender(config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create the email sender")
+	}
+	renderer, err := mail.NewRendererFromConfig(config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load the email templates")
+	}
+	taskProcessor := async.NewRedisTaskProcessor(config, redisOpt, store, mailSender, renderer)
 	if err := taskProcessor.Start(); err != nil {
 		log.Fatal().Err(err)
 	}
@@ -83,14 +101,16 @@ func migrateDB(migrationURL, dbURI string) {
 	log.Info().Msgf("DB migrations ran successfully \n")
 }
 
-func startGRPCerver(config utils.Config, store db.Store, taskDistributor async.TaskDistributor) {
-	server, err := gapi.NewServer(config, store, taskDistributor)
+func startGRPCerver(config utils.Config, store db.Store, taskDistributor async.TaskDistributor, revocation *token.RevocationChecker) {
+	server, err := gapi.NewServer(config, store, taskDistributor, revocation)
 	if err != nil {
 		log.Fatal().Err(err).Send()
 	}
 
-	logger := grpc.UnaryInterceptor(gapi.GRPCLogger)
-	grpcServer := grpc.NewServer(logger)
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(gapi.GRPCLogger, server.AuthInterceptor),
+		grpc.ChainStreamInterceptor(server.StreamAuthInterceptor),
+	)
 
 	pb.RegisterBankServer(grpcServer, server)
 	reflection.Register(grpcServer)
@@ -107,8 +127,8 @@ func startGRPCerver(config utils.Config, store db.Store, taskDistributor async.T
 	}
 }
 
-func runGatewayServer(config utils.Config, store db.Store, taskDistributor async.TaskDistributor) {
-	server, err := gapi.NewServer(config, store, taskDistributor)
+func runGatewayServer(config utils.Config, store db.Store, taskDistributor async.TaskDistributor, revocation *token.RevocationChecker) {
+	server, err := gapi.NewServer(config, store, taskDistributor, revocation)
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot create server")
 	}
@@ -122,18 +142,30 @@ func runGatewayServer(config utils.Config, store db.Store, taskDistributor async
 		},
 	})
 
-	grpcMux := runtime.NewServeMux(jsonOption)
+	headerMatcher := runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+		if strings.ToLower(key) == gapi.IdempotencyHeader {
+			return gapi.IdempotencyHeader, true
+		}
+		return runtime.DefaultHeaderMatcher(key)
+	})
+
+	grpcMux := runtime.NewServeMux(jsonOption, headerMatcher)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	err = pb.RegisterBankHandlerServer(ctx, grpcMux, server)
+	// the gateway calls the gRPC server rather than the handlers, so that the interceptors apply to it too
+	dialOptions := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	err = pb.RegisterBankHandlerFromEndpoint(ctx, grpcMux, config.GRPCServerAddress, dialOptions)
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot register handler server")
 	}
 
 	mux := http.NewServeMux()
 	mux.Handle("/", grpcMux)
+	mux.Handle(gapi.TokenKeysPath, server.TokenKeysHandler())
+	mux.Handle(gapi.JWKSPath, server.JWKSHandler())
+	mux.Handle(gapi.PasswordResetPagePath, gapi.PasswordResetPageHandler())
 
 	fileServer := http.FileServer(http.Dir("doc/swagger"))
 
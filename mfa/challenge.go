@@ -0,0 +1,71 @@
+package mfa
+
+import (
+	db "bank/db/sqlc"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxChallengeAttempts limits the codes tried against one challenge, so that they can't be guessed.
+const maxChallengeAttempts = 5
+
+var ErrInvalidChallenge = errors.New("mfa challenge is invalid or expired")
+
+// StartChallenge is the outcome of a password check for a user with two-factor authentication:
+// the challenge ID is handed to the client instead of tokens.
+func StartChallenge(ctx context.Context, querier db.Querier, userID int64, duration time.Duration) (db.MfaChallenge, error) {
+	return querier.CreateMFAChallenge(ctx, db.CreateMFAChallengeParams{
+		ID:        uuid.New(),
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(duration),
+	})
+}
+
+// ChallengeUser returns the ID of the user the pending challenge belongs to, so that the login
+// can be checked before any code is tried against the challenge.
+func ChallengeUser(ctx context.Context, querier db.Querier, challengeID uuid.UUID) (int64, error) {
+	challenge, err := querier.GetPendingMFAChallenge(ctx, challengeID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return 0, ErrInvalidChallenge
+		}
+		return 0, err
+	}
+	return challenge.UserID, nil
+}
+
+// CompleteChallenge checks the code against the challenge and returns the ID of the user who passed it.
+// A challenge is completed only once and gives up after a few wrong codes.
+// A wrong code returns the ID of the user along with the error, so that the failure can be counted against them.
+func CompleteChallenge(ctx context.Context, querier db.Querier, challengeID uuid.UUID, code string) (int64, error) {
+	challenge, err := querier.AttemptMFAChallenge(ctx, db.AttemptMFAChallengeParams{
+		ID:          challengeID,
+		MaxAttempts: maxChallengeAttempts,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return 0, ErrInvalidChallenge
+		}
+		return 0, err
+	}
+
+	if err = Verify(ctx, querier, challenge.UserID, code); err != nil {
+		if errors.Is(err, ErrInvalidCode) {
+			return challenge.UserID, err
+		}
+		return 0, err
+	}
+
+	consumed, err := querier.ConsumeMFAChallenge(ctx, challenge.ID)
+	if err != nil {
+		return 0, err
+	}
+	if consumed == 0 {
+		return 0, ErrInvalidChallenge
+	}
+
+	return challenge.UserID, nil
+}
@@ -0,0 +1,39 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	RecoveryCodeCount = 10
+	recoveryCodeSize  = 10
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateRecoveryCodes returns one-time codes that replace a TOTP code when the device is lost,
+// formatted as "abcde-fghij". Only their hashes are stored.
+func GenerateRecoveryCodes(count int) ([]string, error) {
+	codes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		random := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(random); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(recoveryEncoding.EncodeToString(random))[:recoveryCodeSize]
+		codes = append(codes, code[:recoveryCodeSize/2]+"-"+code[recoveryCodeSize/2:])
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode hashes the code the way it is stored, ignoring case and separators.
+// The codes are random enough for a plain hash.
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,101 @@
+package mfa
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The parameters of RFC 6238 every authenticator app supports.
+const (
+	totpPeriod     = 30 * time.Second
+	totpDigits     = 6
+	totpSecretSize = 20
+	// codes of the neighbouring steps are accepted too, to make up for clock drift
+	totpSkew = 1
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random TOTP secret, base32 encoded.
+func GenerateSecret() (string, error) {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return secretEncoding.EncodeToString(secret), nil
+}
+
+// ProvisioningURI builds the otpauth:// URI authenticator apps import, usually from a QR code.
+func ProvisioningURI(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%d", totpDigits))
+	query.Set("period", fmt.Sprintf("%d", int(totpPeriod.Seconds())))
+
+	label := url.PathEscape(issuer + ":" + account)
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, query.Encode())
+}
+
+// Code returns the TOTP code of the secret at the given time.
+func Code(secret string, t time.Time) (string, error) {
+	return codeAtStep(secret, timeStep(t))
+}
+
+// validateCode returns the time step the code belongs to, if it is valid around the given time.
+func validateCode(secret, code string, t time.Time) (int64, bool) {
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	step := timeStep(t)
+	for i := step - totpSkew; i <= step+totpSkew; i++ {
+		expected, err := codeAtStep(secret, i)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+func timeStep(t time.Time) int64 {
+	return t.Unix() / int64(totpPeriod.Seconds())
+}
+
+// codeAtStep is the HOTP value of RFC 4226 for the counter of the time step.
+func codeAtStep(secret string, step int64) (string, error) {
+	key, err := secretEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("invalid totp secret: %w", err)
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, value%modulo), nil
+}
@@ -0,0 +1,92 @@
+package mfa
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// the SHA1 secret of the RFC 6238 test vectors, "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	testCases := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+	}
+
+	for _, tc := range testCases {
+		code, err := Code(rfcSecret, time.Unix(tc.unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, tc.code, code)
+	}
+}
+
+func TestValidateCode(t *testing.T) {
+	now := time.Now()
+	code, err := Code(rfcSecret, now)
+	require.NoError(t, err)
+
+	step, isValid := validateCode(rfcSecret, code, now)
+	require.True(t, isValid)
+	require.Equal(t, timeStep(now), step)
+
+	// a code of the previous step is accepted for clock drift
+	_, isValid = validateCode(rfcSecret, code, now.Add(totpPeriod))
+	require.True(t, isValid)
+
+	_, isValid = validateCode(rfcSecret, code, now.Add(3*totpPeriod))
+	require.False(t, isValid)
+
+	_, isValid = validateCode(rfcSecret, "12345", now)
+	require.False(t, isValid)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	other, err := GenerateSecret()
+	require.NoError(t, err)
+	require.NotEqual(t, secret, other)
+
+	_, err = Code(secret, time.Now())
+	require.NoError(t, err)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Mini Bank", "user@example.com", rfcSecret)
+	require.True(t, strings.HasPrefix(uri, "otpauth://totp/"))
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	require.Equal(t, "/Mini Bank:user@example.com", parsed.Path)
+	require.Equal(t, rfcSecret, parsed.Query().Get("secret"))
+	require.Equal(t, "Mini Bank", parsed.Query().Get("issuer"))
+	require.Equal(t, "6", parsed.Query().Get("digits"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(RecoveryCodeCount)
+	require.NoError(t, err)
+	require.Len(t, codes, RecoveryCodeCount)
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		require.Len(t, code, recoveryCodeSize+1)
+		require.False(t, seen[code])
+		seen[code] = true
+	}
+
+	require.Equal(t, HashRecoveryCode(codes[0]), HashRecoveryCode(strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))))
+	require.NotEqual(t, HashRecoveryCode(codes[0]), HashRecoveryCode(codes[1]))
+}
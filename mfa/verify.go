@@ -0,0 +1,98 @@
+package mfa
+
+import (
+	db "bank/db/sqlc"
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotEnrolled = errors.New("two-factor authentication is not enabled")
+	ErrInvalidCode = errors.New("invalid two-factor code")
+)
+
+// Enabled tells whether the user has a confirmed TOTP secret.
+func Enabled(ctx context.Context, querier db.Querier, userID int64) (bool, error) {
+	totp, err := querier.GetUserTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return totp.ConfirmedAt.Valid, nil
+}
+
+// Verify checks a TOTP code or a recovery code of a user with a confirmed secret.
+// Either code is accepted only once.
+func Verify(ctx context.Context, querier db.Querier, userID int64, code string) error {
+	totp, err := querier.GetUserTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return ErrNotEnrolled
+		}
+		return err
+	}
+	if !totp.ConfirmedAt.Valid {
+		return ErrNotEnrolled
+	}
+
+	if len(code) == totpDigits {
+		return useTOTPCode(ctx, querier, totp, code)
+	}
+
+	used, err := querier.UseRecoveryCode(ctx, db.UseRecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: HashRecoveryCode(code),
+	})
+	if err != nil {
+		return err
+	}
+	if used == 0 {
+		return ErrInvalidCode
+	}
+
+	return nil
+}
+
+// Confirm enables the enrolled secret once the user proves to hold it with a TOTP code.
+func Confirm(ctx context.Context, querier db.Querier, userID int64, code string) error {
+	totp, err := querier.GetUserTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return ErrNotEnrolled
+		}
+		return err
+	}
+	if totp.ConfirmedAt.Valid {
+		return db.ErrTOTPAlreadyEnabled
+	}
+
+	if err = useTOTPCode(ctx, querier, totp, code); err != nil {
+		return err
+	}
+
+	if _, err = querier.ConfirmUserTOTP(ctx, userID); errors.Is(err, db.ErrRecordNotFound) {
+		return db.ErrTOTPAlreadyEnabled
+	}
+	return err
+}
+
+func useTOTPCode(ctx context.Context, querier db.Querier, totp db.UserTotp, code string) error {
+	step, isValid := validateCode(totp.Secret, code, time.Now())
+	if !isValid {
+		return ErrInvalidCode
+	}
+
+	// the step moves forward only, so a code that has been used is rejected
+	used, err := querier.UseTOTPStep(ctx, db.UseTOTPStepParams{UserID: totp.UserID, Step: step})
+	if err != nil {
+		return err
+	}
+	if used == 0 {
+		return ErrInvalidCode
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package mfa
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/utils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestVerify(t *testing.T) {
+	userID := utils.RandomInt(1, 1000)
+	confirmed := db.UserTotp{
+		UserID:      userID,
+		Secret:      rfcSecret,
+		ConfirmedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+	code, err := Code(rfcSecret, time.Now())
+	require.NoError(t, err)
+	wrongCode := string('0'+(code[0]-'0'+1)%10) + code[1:]
+
+	testCases := []struct {
+		name       string
+		code       string
+		buildStubs func(store *mockdb.MockStore)
+		checkError func(t *testing.T, err error)
+	}{
+		{
+			name: "TOTP code",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(userID)).Times(1).Return(confirmed, nil)
+				store.EXPECT().
+					UseTOTPStep(gomock.Any(), gomock.Eq(db.UseTOTPStepParams{UserID: userID, Step: timeStep(time.Now())})).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Reused TOTP code",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(userID)).Times(1).Return(confirmed, nil)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInvalidCode)
+			},
+		},
+		{
+			name: "Wrong TOTP code",
+			code: wrongCode,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(userID)).Times(1).Return(confirmed, nil)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInvalidCode)
+			},
+		},
+		{
+			name: "Recovery code",
+			code: "abcde-fghij",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(userID)).Times(1).Return(confirmed, nil)
+				store.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Eq(db.UseRecoveryCodeParams{UserID: userID, CodeHash: HashRecoveryCode("abcdefghij")})).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Used recovery code",
+			code: "abcde-fghij",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(userID)).Times(1).Return(confirmed, nil)
+				store.EXPECT().UseRecoveryCode(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInvalidCode)
+			},
+		},
+		{
+			name: "Not confirmed",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(db.UserTotp{UserID: userID, Secret: rfcSecret}, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrNotEnrolled)
+			},
+		},
+		{
+			name: "Not enrolled",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(userID)).Times(1).Return(db.UserTotp{}, db.ErrRecordNotFound)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrNotEnrolled)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ctrl := gomock.NewController(t)
+
+		store := mockdb.NewMockStore(ctrl)
+
+		tc.buildStubs(store)
+
+		tc.checkError(t, Verify(context.Background(), store, userID, tc.code))
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	userID := utils.RandomInt(1, 1000)
+	enrolled := db.UserTotp{UserID: userID, Secret: rfcSecret}
+	code, err := Code(rfcSecret, time.Now())
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(userID)).Times(1).Return(enrolled, nil)
+	store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+	store.EXPECT().ConfirmUserTOTP(gomock.Any(), gomock.Eq(userID)).Times(1).Return(enrolled, nil)
+
+	require.NoError(t, Confirm(context.Background(), store, userID, code))
+
+	enrolled.ConfirmedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	store.EXPECT().GetUserTOTP(gomock.Any(), gomock.Eq(userID)).Times(1).Return(enrolled, nil)
+
+	require.ErrorIs(t, Confirm(context.Background(), store, userID, code), db.ErrTOTPAlreadyEnabled)
+}
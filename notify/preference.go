@@ -0,0 +1,73 @@
+package notify
+
+import (
+	db "bank/db/sqlc"
+	"context"
+)
+
+// The events the users are notified about.
+const (
+	EventTransferSent     = "transfer_sent"
+	EventTransferReceived = "transfer_received"
+)
+
+// Events lists the supported events in the order the preferences are shown.
+var Events = []string{EventTransferReceived, EventTransferSent}
+
+// IsEventSupported tells whether the users can be notified about the event.
+func IsEventSupported(event string) bool {
+	for _, supported := range Events {
+		if supported == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Preference tells through which channels the user hears about the event.
+type Preference struct {
+	Event string
+	InApp bool
+	Email bool
+}
+
+// defaultPreference applies to the events the user has not set up: in-app only,
+// the emails are sent to the users who opt in.
+func defaultPreference(event string) Preference {
+	return Preference{Event: event, InApp: true, Email: false}
+}
+
+// Preferences returns the preferences of the user for every supported event.
+func Preferences(ctx context.Context, querier db.Querier, userID int64) ([]Preference, error) {
+	stored, err := querier.ListNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := make([]Preference, 0, len(Events))
+	for _, event := range Events {
+		preference := defaultPreference(event)
+		for _, row := range stored {
+			if row.Event == event {
+				preference.InApp = row.InApp
+				preference.Email = row.Email
+			}
+		}
+		preferences = append(preferences, preference)
+	}
+	return preferences, nil
+}
+
+// PreferenceOf returns the preference of the user for one event.
+func PreferenceOf(ctx context.Context, querier db.Querier, userID int64, event string) (Preference, error) {
+	preferences, err := Preferences(ctx, querier, userID)
+	if err != nil {
+		return Preference{}, err
+	}
+	for _, preference := range preferences {
+		if preference.Event == event {
+			return preference, nil
+		}
+	}
+	return defaultPreference(event), nil
+}
@@ -0,0 +1,35 @@
+package notify
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPreferences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		ListNotificationPreferences(gomock.Any(), gomock.Eq(int64(7))).
+		Times(2).
+		Return([]db.NotificationPreference{{UserID: 7, Event: EventTransferSent, InApp: false, Email: true}}, nil)
+
+	preferences, err := Preferences(context.Background(), store, 7)
+	require.NoError(t, err)
+	require.Equal(t, []Preference{
+		{Event: EventTransferReceived, InApp: true, Email: false},
+		{Event: EventTransferSent, InApp: false, Email: true},
+	}, preferences)
+
+	preference, err := PreferenceOf(context.Background(), store, 7, EventTransferSent)
+	require.NoError(t, err)
+	require.Equal(t, Preference{Event: EventTransferSent, InApp: false, Email: true}, preference)
+
+	require.True(t, IsEventSupported(EventTransferReceived))
+	require.False(t, IsEventSupported("account_closed"))
+}
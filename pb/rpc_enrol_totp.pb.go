@@ -0,0 +1,218 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        v3.20.1
+// source: rpc_enrol_totp.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EnrolTOTPRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *EnrolTOTPRequest) Reset() {
+	*x = EnrolTOTPRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_enrol_totp_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnrolTOTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrolTOTPRequest) ProtoMessage() {}
+
+func (x *EnrolTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_enrol_totp_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrolTOTPRequest.ProtoReflect.Descriptor instead.
+func (*EnrolTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_enrol_totp_proto_rawDescGZIP(), []int{0}
+}
+
+type EnrolTOTPResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// base32 secret for authenticator apps that take it typed in
+	Secret string `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	// otpauth:// URI to render as a QR code
+	ProvisioningUri string `protobuf:"bytes,2,opt,name=provisioning_uri,json=provisioningUri,proto3" json:"provisioning_uri,omitempty"`
+	// one-time codes replacing a TOTP code when the device is lost; they are shown only once
+	RecoveryCodes []string `protobuf:"bytes,3,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"`
+}
+
+func (x *EnrolTOTPResponse) Reset() {
+	*x = EnrolTOTPResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_enrol_totp_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnrolTOTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrolTOTPResponse) ProtoMessage() {}
+
+func (x *EnrolTOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_enrol_totp_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrolTOTPResponse.ProtoReflect.Descriptor instead.
+func (*EnrolTOTPResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_enrol_totp_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EnrolTOTPResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *EnrolTOTPResponse) GetProvisioningUri() string {
+	if x != nil {
+		return x.ProvisioningUri
+	}
+	return ""
+}
+
+func (x *EnrolTOTPResponse) GetRecoveryCodes() []string {
+	if x != nil {
+		return x.RecoveryCodes
+	}
+	return nil
+}
+
+var File_rpc_enrol_totp_proto protoreflect.FileDescriptor
+
+var file_rpc_enrol_totp_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x72, 0x70, 0x63, 0x5f, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x5f, 0x74, 0x6f, 0x74, 0x70,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x22, 0x12, 0x0a, 0x10, 0x45, 0x6e,
+	0x72, 0x6f, 0x6c, 0x54, 0x4f, 0x54, 0x50, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x7d,
+	0x0a, 0x11, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x54, 0x4f, 0x54, 0x50, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x70,
+	0x72, 0x6f, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x75, 0x72, 0x69, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e,
+	0x69, 0x6e, 0x67, 0x55, 0x72, 0x69, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x63, 0x6f, 0x76, 0x65,
+	0x72, 0x79, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d,
+	0x72, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x64, 0x65, 0x73, 0x42, 0x05, 0x5a,
+	0x03, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_enrol_totp_proto_rawDescOnce sync.Once
+	file_rpc_enrol_totp_proto_rawDescData = file_rpc_enrol_totp_proto_rawDesc
+)
+
+func file_rpc_enrol_totp_proto_rawDescGZIP() []byte {
+	file_rpc_enrol_totp_proto_rawDescOnce.Do(func() {
+		file_rpc_enrol_totp_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_enrol_totp_proto_rawDescData)
+	})
+	return file_rpc_enrol_totp_proto_rawDescData
+}
+
+var file_rpc_enrol_totp_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_enrol_totp_proto_goTypes = []interface{}{
+	(*EnrolTOTPRequest)(nil),  // 0: pb.EnrolTOTPRequest
+	(*EnrolTOTPResponse)(nil), // 1: pb.EnrolTOTPResponse
+}
+var file_rpc_enrol_totp_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_rpc_enrol_totp_proto_init() }
+func file_rpc_enrol_totp_proto_init() {
+	if File_rpc_enrol_totp_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_enrol_totp_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnrolTOTPRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_enrol_totp_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnrolTOTPResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_enrol_totp_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_enrol_totp_proto_goTypes,
+		DependencyIndexes: file_rpc_enrol_totp_proto_depIdxs,
+		MessageInfos:      file_rpc_enrol_totp_proto_msgTypes,
+	}.Build()
+	File_rpc_enrol_totp_proto = out.File
+	file_rpc_enrol_totp_proto_rawDesc = nil
+	file_rpc_enrol_totp_proto_goTypes = nil
+	file_rpc_enrol_totp_proto_depIdxs = nil
+}
@@ -0,0 +1,154 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        v3.20.1
+// source: rpc_login_user_mfa.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type LoginUserMFARequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// challenge returned by LoginUser
+	MfaToken string `protobuf:"bytes,1,opt,name=mfa_token,json=mfaToken,proto3" json:"mfa_token,omitempty"`
+	// TOTP code or recovery code
+	Code string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+}
+
+func (x *LoginUserMFARequest) Reset() {
+	*x = LoginUserMFARequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_login_user_mfa_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoginUserMFARequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginUserMFARequest) ProtoMessage() {}
+
+func (x *LoginUserMFARequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_login_user_mfa_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginUserMFARequest.ProtoReflect.Descriptor instead.
+func (*LoginUserMFARequest) Descriptor() ([]byte, []int) {
+	return file_rpc_login_user_mfa_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LoginUserMFARequest) GetMfaToken() string {
+	if x != nil {
+		return x.MfaToken
+	}
+	return ""
+}
+
+func (x *LoginUserMFARequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+var File_rpc_login_user_mfa_proto protoreflect.FileDescriptor
+
+var file_rpc_login_user_mfa_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x72, 0x70, 0x63, 0x5f, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x5f, 0x75, 0x73, 0x65, 0x72,
+	0x5f, 0x6d, 0x66, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x22, 0x46,
+	0x0a, 0x13, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x55, 0x73, 0x65, 0x72, 0x4d, 0x46, 0x41, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x66, 0x61, 0x5f, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x66, 0x61, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x42, 0x05, 0x5a, 0x03, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_login_user_mfa_proto_rawDescOnce sync.Once
+	file_rpc_login_user_mfa_proto_rawDescData = file_rpc_login_user_mfa_proto_rawDesc
+)
+
+func file_rpc_login_user_mfa_proto_rawDescGZIP() []byte {
+	file_rpc_login_user_mfa_proto_rawDescOnce.Do(func() {
+		file_rpc_login_user_mfa_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_login_user_mfa_proto_rawDescData)
+	})
+	return file_rpc_login_user_mfa_proto_rawDescData
+}
+
+var file_rpc_login_user_mfa_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_rpc_login_user_mfa_proto_goTypes = []interface{}{
+	(*LoginUserMFARequest)(nil), // 0: pb.LoginUserMFARequest
+}
+var file_rpc_login_user_mfa_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_rpc_login_user_mfa_proto_init() }
+func file_rpc_login_user_mfa_proto_init() {
+	if File_rpc_login_user_mfa_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_login_user_mfa_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoginUserMFARequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_login_user_mfa_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_login_user_mfa_proto_goTypes,
+		DependencyIndexes: file_rpc_login_user_mfa_proto_depIdxs,
+		MessageInfos:      file_rpc_login_user_mfa_proto_msgTypes,
+	}.Build()
+	File_rpc_login_user_mfa_proto = out.File
+	file_rpc_login_user_mfa_proto_rawDesc = nil
+	file_rpc_login_user_mfa_proto_goTypes = nil
+	file_rpc_login_user_mfa_proto_depIdxs = nil
+}
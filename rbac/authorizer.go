@@ -0,0 +1,87 @@
+package rbac
+
+import (
+	"bank/utils"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrPermissionDenied = errors.New("permission denied")
+
+// PermissionStore is the part of db.Store the permissions are read from.
+type PermissionStore interface {
+	ListRolePermissions(ctx context.Context, role string) ([]string, error)
+}
+
+type cachedRole struct {
+	permissions map[Permission]bool
+	cachedAt    time.Time
+}
+
+// Authorizer tells whether a role holds a permission. The permissions of the roles live in the DB,
+// so that a new role needs no code change; they are cached for ttl.
+type Authorizer struct {
+	store PermissionStore
+	ttl   time.Duration
+	mu    sync.Mutex
+	roles map[utils.Role]cachedRole
+}
+
+func NewAuthorizer(store PermissionStore, ttl time.Duration) *Authorizer {
+	return &Authorizer{
+		store: store,
+		ttl:   ttl,
+		roles: make(map[utils.Role]cachedRole),
+	}
+}
+
+// Has tells whether the role holds the permission. A role unknown to the DB holds none.
+func (authorizer *Authorizer) Has(ctx context.Context, role utils.Role, permission Permission) (bool, error) {
+	permissions, err := authorizer.permissions(ctx, role)
+	if err != nil {
+		return false, err
+	}
+	return permissions[permission], nil
+}
+
+// RequireAny returns ErrPermissionDenied unless the role holds at least one of the permissions.
+func (authorizer *Authorizer) RequireAny(ctx context.Context, role utils.Role, required []Permission) error {
+	permissions, err := authorizer.permissions(ctx, role)
+	if err != nil {
+		return err
+	}
+
+	for _, permission := range required {
+		if permissions[permission] {
+			return nil
+		}
+	}
+	return ErrPermissionDenied
+}
+
+func (authorizer *Authorizer) permissions(ctx context.Context, role utils.Role) (map[Permission]bool, error) {
+	authorizer.mu.Lock()
+	cached, isOk := authorizer.roles[role]
+	authorizer.mu.Unlock()
+	if isOk && time.Since(cached.cachedAt) < authorizer.ttl {
+		return cached.permissions, nil
+	}
+
+	names, err := authorizer.store.ListRolePermissions(ctx, string(role))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role permissions: %w", err)
+	}
+
+	cached = cachedRole{permissions: make(map[Permission]bool, len(names)), cachedAt: time.Now()}
+	for _, name := range names {
+		cached.permissions[Permission(name)] = true
+	}
+	authorizer.mu.Lock()
+	authorizer.roles[role] = cached
+	authorizer.mu.Unlock()
+
+	return cached.permissions, nil
+}
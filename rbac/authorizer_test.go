@@ -0,0 +1,44 @@
+package rbac
+
+import (
+	mockdb "bank/db/mock"
+	"bank/utils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuthorizer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		ListRolePermissions(gomock.Any(), gomock.Eq(string(utils.Depositor))).
+		Times(1).
+		Return([]string{string(AccountsReadOwn), string(TransfersCreateOwn)}, nil)
+	store.EXPECT().
+		ListRolePermissions(gomock.Any(), gomock.Eq("unknown")).
+		Times(1).
+		Return([]string{}, nil)
+
+	authorizer := NewAuthorizer(store, time.Minute)
+
+	// the second lookup of the role is served from the cache
+	has, err := authorizer.Has(context.Background(), utils.Depositor, AccountsReadOwn)
+	require.NoError(t, err)
+	require.True(t, has)
+	has, err = authorizer.Has(context.Background(), utils.Depositor, AccountsReadAny)
+	require.NoError(t, err)
+	require.False(t, has)
+
+	err = authorizer.RequireAny(context.Background(), utils.Depositor, []Permission{AccountsReadAny, AccountsReadOwn})
+	require.NoError(t, err)
+	err = authorizer.RequireAny(context.Background(), utils.Depositor, []Permission{AccountsUpdateAny})
+	require.ErrorIs(t, err, ErrPermissionDenied)
+
+	err = authorizer.RequireAny(context.Background(), utils.Role("unknown"), []Permission{AccountsReadOwn})
+	require.ErrorIs(t, err, ErrPermissionDenied)
+}
@@ -0,0 +1,23 @@
+package rbac
+
+// Permission is named resource:action:scope. The "own" scope limits the action
+// to the resources of the user, the "any" scope does not.
+type Permission string
+
+const (
+	AccountsCreateOwn      Permission = "accounts:create:own"
+	AccountsReadOwn        Permission = "accounts:read:own"
+	AccountsReadAny        Permission = "accounts:read:any"
+	AccountsUpdateAny      Permission = "accounts:update:any"
+	TransfersCreateOwn     Permission = "transfers:create:own"
+	TransfersReadOwn       Permission = "transfers:read:own"
+	TransfersReadAny       Permission = "transfers:read:any"
+	UsersUpdateOwn         Permission = "users:update:own"
+	UsersUpdateAny         Permission = "users:update:any"
+	UsersUnlockAny         Permission = "users:unlock:any"
+	SessionsManageOwn      Permission = "sessions:manage:own"
+	SessionsRevokeAny      Permission = "sessions:revoke:any"
+	MFAManageOwn           Permission = "mfa:manage:own"
+	LockoutsReadAny        Permission = "lockouts:read:any"
+	NotificationsManageOwn Permission = "notifications:manage:own"
+)
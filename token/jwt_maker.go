@@ -2,6 +2,7 @@ package token
 
 import (
 	"bank/utils"
+	"crypto/ed25519"
 	"errors"
 	"fmt"
 	"time"
@@ -10,8 +11,11 @@ import (
 	"github.com/google/uuid"
 )
 
+// JWTMaker signs tokens with HS512 and a shared secret,
+// or with EdDSA and a key set, naming the key in the "kid" header.
 type JWTMaker struct {
 	secretKey string
+	keys      *keySet
 }
 
 var (
@@ -24,7 +28,16 @@ func NewJWTMaker(secretKey string) (Maker, error) {
 		return nil, ErrSecretKeyTooShort
 	}
 
-	return &JWTMaker{secretKey}, nil
+	return &JWTMaker{secretKey: secretKey}, nil
+}
+
+func NewJWTPublicMaker(keys []SigningKey, gracePeriod time.Duration) (Maker, error) {
+	keySet, err := newKeySet(keys, gracePeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JWTMaker{keys: keySet}, nil
 }
 
 type JWTPayload struct {
@@ -32,7 +45,7 @@ type JWTPayload struct {
 	jwt.RegisteredClaims
 }
 
-func NewJWTPayload(userID int64, role utils.Role, duration time.Duration) (*JWTPayload, error) {
+func NewJWTPayload(userID int64, role utils.Role, tokenType TokenType, sessionID uuid.UUID, duration time.Duration) (*JWTPayload, error) {
 	uuid, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -47,6 +60,8 @@ func NewJWTPayload(userID int64, role utils.Role, duration time.Duration) (*JWTP
 		ID:        uuid,
 		UserID:    userID,
 		Role:      role,
+		Type:      tokenType,
+		SessionID: sessionID,
 		IssuedAt:  issuedAt,
 		ExpiresAt: expiresAt,
 	}
@@ -63,24 +78,52 @@ func NewJWTPayload(userID int64, role utils.Role, duration time.Duration) (*JWTP
 	return payload, nil
 }
 
-func (maker *JWTMaker) CreateToken(userID int64, role utils.Role, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewJWTPayload(userID, role, duration)
+func (maker *JWTMaker) CreateToken(userID int64, role utils.Role, tokenType TokenType, sessionID uuid.UUID, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewJWTPayload(userID, role, tokenType, sessionID, duration)
 	if err != nil {
 		return "", &payload.Payload, err
 	}
 
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS512, payload)
-	tokenString, err := jwtToken.SignedString([]byte(maker.secretKey))
+	if maker.keys == nil {
+		jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS512, payload)
+		tokenString, err := jwtToken.SignedString([]byte(maker.secretKey))
+
+		return tokenString, &payload.Payload, err
+	}
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodEdDSA, payload)
+	jwtToken.Header["kid"] = maker.keys.current.ID
+	tokenString, err := jwtToken.SignedString(ed25519.PrivateKey(maker.keys.current.SecretKey.ExportBytes()))
 
 	return tokenString, &payload.Payload, err
 }
 
+// PublicKeys lists the verification keys; there are none to publish for a shared secret.
+func (maker *JWTMaker) PublicKeys() []PublicKey {
+	if maker.keys == nil {
+		return nil
+	}
+	return maker.keys.PublicKeys()
+}
+
 func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
 	keyFunc := func(token *jwt.Token) (interface{}, error) {
-		if _, isOk := token.Method.(*jwt.SigningMethodHMAC); !isOk {
+		if maker.keys == nil {
+			if _, isOk := token.Method.(*jwt.SigningMethodHMAC); !isOk {
+				return nil, ErrInvalidToken
+			}
+			return []byte(maker.secretKey), nil
+		}
+
+		if _, isOk := token.Method.(*jwt.SigningMethodEd25519); !isOk {
 			return nil, ErrInvalidToken
 		}
-		return []byte(maker.secretKey), nil
+		keyID, _ := token.Header["kid"].(string)
+		key, err := maker.keys.verificationKey(keyID)
+		if err != nil {
+			return nil, err
+		}
+		return ed25519.PublicKey(key.PublicKey.ExportBytes()), nil
 	}
 
 	jwtToken, err := jwt.ParseWithClaims(token, &JWTPayload{}, keyFunc)
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -14,12 +15,13 @@ func TestJWTMaker(t *testing.T) {
 	require.NoError(t, err)
 
 	userID := utils.RandomInt(1, 1000)
+	sessionID := uuid.New()
 	duration := time.Minute
 
 	issuedAt := time.Now()
 	expiresAt := issuedAt.Add(duration)
 
-	token, payload, err := maker.CreateToken(userID, utils.Depositor, duration)
+	token, payload, err := maker.CreateToken(userID, utils.Depositor, TokenTypeAccess, sessionID, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -29,6 +31,8 @@ func TestJWTMaker(t *testing.T) {
 	require.NotEmpty(t, payload)
 	require.NotZero(t, payload.ID)
 	require.Equal(t, payload.UserID, userID)
+	require.Equal(t, sessionID, payload.SessionID)
+	require.Equal(t, TokenTypeAccess, payload.Type)
 	require.WithinDuration(t, expiresAt, payload.ExpiresAt, time.Second)
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
 }
@@ -40,7 +44,7 @@ func TestExpiredToken(t *testing.T) {
 	userID := utils.RandomInt(1, 1000)
 	duration := -time.Minute
 
-	token, payload, err := maker.CreateToken(userID, utils.Depositor, duration)
+	token, payload, err := maker.CreateToken(userID, utils.Depositor, TokenTypeAccess, uuid.Nil, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -59,7 +63,7 @@ func TestAlgNone(t *testing.T) {
 	userID := utils.RandomInt(1, 1000)
 	duration := time.Minute
 
-	jwtPayload, err := NewJWTPayload(userID, utils.Depositor, duration)
+	jwtPayload, err := NewJWTPayload(userID, utils.Depositor, TokenTypeAccess, uuid.Nil, duration)
 	require.NoError(t, err)
 
 	token := jwt.NewWithClaims(jwt.SigningMethodNone, jwtPayload)
@@ -73,3 +77,41 @@ func TestAlgNone(t *testing.T) {
 	_, err = maker.VerifyToken(tokenString)
 	require.ErrorIs(t, err, ErrInvalidToken)
 }
+
+func TestJWTPublicMaker(t *testing.T) {
+	oldKey := randomSigningKey(time.Now().Add(-time.Minute))
+	newKey := randomSigningKey(time.Time{})
+
+	oldMaker, err := NewJWTPublicMaker([]SigningKey{{ID: oldKey.ID, SecretKey: oldKey.SecretKey, PublicKey: oldKey.PublicKey}}, time.Hour)
+	require.NoError(t, err)
+	oldToken, _, err := oldMaker.CreateToken(utils.RandomInt(1, 1000), utils.Depositor, TokenTypeAccess, uuid.Nil, time.Minute)
+	require.NoError(t, err)
+
+	maker, err := NewJWTPublicMaker([]SigningKey{oldKey, newKey}, time.Hour)
+	require.NoError(t, err)
+
+	userID := utils.RandomInt(1, 1000)
+	token, _, err := maker.CreateToken(userID, utils.Depositor, TokenTypeAccess, uuid.Nil, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, userID, payload.UserID)
+
+	_, err = maker.VerifyToken(oldToken)
+	require.NoError(t, err)
+
+	hmacMaker, err := NewJWTMaker(utils.RandomString(32))
+	require.NoError(t, err)
+	hmacToken, _, err := hmacMaker.CreateToken(userID, utils.Depositor, TokenTypeAccess, uuid.Nil, time.Minute)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(hmacToken)
+	require.Error(t, err)
+
+	keys := maker.(KeyPublisher).PublicKeys()
+	require.Len(t, keys, 2)
+	require.Equal(t, newKey.ID, keys[0].ID)
+	require.Zero(t, keys[0].NotAfter)
+	require.Equal(t, oldKey.ID, keys[1].ID)
+	require.WithinDuration(t, oldKey.RetiredAt.Add(time.Hour), keys[1].NotAfter, time.Second)
+}
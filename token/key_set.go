@@ -0,0 +1,128 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+)
+
+var (
+	ErrNoSigningKey = errors.New("exactly one signing key must not be retired")
+	ErrUnknownKey   = errors.New("token is signed with an unknown key")
+)
+
+// SigningKey is an Ed25519 key pair identified by ID.
+// Retired keys don't sign new tokens and verify the issued ones until their grace period is over;
+// they may come without the secret half.
+type SigningKey struct {
+	ID        string
+	SecretKey *paseto.V4AsymmetricSecretKey
+	PublicKey paseto.V4AsymmetricPublicKey
+	CreatedAt time.Time
+	RetiredAt time.Time
+}
+
+func (key SigningKey) isRetired() bool {
+	return !key.RetiredAt.IsZero()
+}
+
+// PublicKey is a verification key as published to other services.
+// NotBefore is zero when the creation time of the key is unknown,
+// NotAfter is zero while the key signs tokens.
+type PublicKey struct {
+	ID        string
+	Key       ed25519.PublicKey
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// KeyPublisher is implemented by the makers whose tokens are verified with public keys.
+type KeyPublisher interface {
+	PublicKeys() []PublicKey
+}
+
+// keySet holds the current signing key along with the retired ones.
+type keySet struct {
+	current     SigningKey
+	keys        map[string]SigningKey
+	gracePeriod time.Duration
+}
+
+func newKeySet(keys []SigningKey, gracePeriod time.Duration) (*keySet, error) {
+	set := &keySet{
+		keys:        make(map[string]SigningKey, len(keys)),
+		gracePeriod: gracePeriod,
+	}
+
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, fmt.Errorf("signing key ID is empty")
+		}
+		if _, exists := set.keys[key.ID]; exists {
+			return nil, fmt.Errorf("signing key %q is duplicated", key.ID)
+		}
+		set.keys[key.ID] = key
+
+		if key.isRetired() {
+			continue
+		}
+		if set.current.ID != "" || key.SecretKey == nil {
+			return nil, ErrNoSigningKey
+		}
+		set.current = key
+	}
+
+	if set.current.ID == "" {
+		return nil, ErrNoSigningKey
+	}
+
+	return set, nil
+}
+
+func (set *keySet) isExpired(key SigningKey, now time.Time) bool {
+	return key.isRetired() && now.Sub(key.RetiredAt) > set.gracePeriod
+}
+
+// verificationKey returns the key of the ID unless its grace period is over.
+func (set *keySet) verificationKey(id string) (SigningKey, error) {
+	key, isOk := set.keys[id]
+	if !isOk || set.isExpired(key, time.Now()) {
+		return key, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// PublicKeys lists the keys that still verify tokens, the current one first.
+func (set *keySet) PublicKeys() []PublicKey {
+	now := time.Now()
+
+	keys := make([]PublicKey, 0, len(set.keys))
+	for _, key := range set.keys {
+		if set.isExpired(key, now) {
+			continue
+		}
+
+		publicKey := PublicKey{
+			ID:        key.ID,
+			Key:       ed25519.PublicKey(key.PublicKey.ExportBytes()),
+			NotBefore: key.CreatedAt,
+		}
+		if key.isRetired() {
+			publicKey.NotAfter = key.RetiredAt.Add(set.gracePeriod)
+		}
+		keys = append(keys, publicKey)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].ID == set.current.ID || keys[j].ID == set.current.ID {
+			return keys[i].ID == set.current.ID
+		}
+		return keys[i].ID < keys[j].ID
+	})
+
+	return keys
+}
@@ -0,0 +1,121 @@
+package token
+
+import (
+	"bank/utils"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+)
+
+// keyFile is the format of the files in the key directory, one key per file:
+//
+//	{"id": "2024-06", "secret_key": "<hex>", "created_at": "2024-06-01T00:00:00Z", "retired_at": "2024-07-01T00:00:00Z"}
+//
+// A retired key may hold only "public_key".
+type keyFile struct {
+	ID        string    `json:"id"`
+	SecretKey string    `json:"secret_key"`
+	PublicKey string    `json:"public_key"`
+	CreatedAt time.Time `json:"created_at"`
+	RetiredAt time.Time `json:"retired_at"`
+}
+
+const (
+	FormatPaseto = "paseto"
+	FormatJWT    = "jwt"
+)
+
+// NewMaker builds the token maker the config asks for. TOKEN_FORMAT picks PASETO (the default) or JWT;
+// the tokens are signed with the keys of TOKEN_SECRET_KEY and TOKEN_KEYS_DIR,
+// or with TOKEN_SYMMETRIC_KEY when none is set.
+func NewMaker(config utils.Config) (Maker, error) {
+	if config.TokenFormat != "" && config.TokenFormat != FormatPaseto && config.TokenFormat != FormatJWT {
+		return nil, fmt.Errorf("unsupported token format %q", config.TokenFormat)
+	}
+
+	var keys []SigningKey
+	if config.TokenKeysDir != "" {
+		dirKeys, err := LoadSigningKeys(config.TokenKeysDir)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, dirKeys...)
+	}
+	if config.TokenSecretKey != "" {
+		key, err := newSigningKey(keyFile{ID: config.TokenKeyID, SecretKey: config.TokenSecretKey})
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if config.TokenFormat == FormatJWT {
+		if len(keys) == 0 {
+			return NewJWTMaker(config.TokenSymmetricKey)
+		}
+		return NewJWTPublicMaker(keys, config.TokenKeyGracePeriod)
+	}
+
+	if len(keys) == 0 {
+		return NewPasetoMaker(config.TokenSymmetricKey)
+	}
+	return NewPasetoPublicMaker(keys, config.TokenKeyGracePeriod)
+}
+
+// LoadSigningKeys reads every *.json key file of the directory.
+func LoadSigningKeys(dir string) ([]SigningKey, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]SigningKey, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var file keyFile
+		if err = json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse key file %s: %w", path, err)
+		}
+
+		key, err := newSigningKey(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key file %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func newSigningKey(file keyFile) (SigningKey, error) {
+	key := SigningKey{ID: file.ID, CreatedAt: file.CreatedAt, RetiredAt: file.RetiredAt}
+
+	if file.SecretKey != "" {
+		secretKey, err := paseto.NewV4AsymmetricSecretKeyFromHex(file.SecretKey)
+		if err != nil {
+			return key, fmt.Errorf("invalid secret key of %q: %w", file.ID, err)
+		}
+		key.SecretKey = &secretKey
+		key.PublicKey = secretKey.Public()
+		return key, nil
+	}
+
+	if file.PublicKey == "" {
+		return key, fmt.Errorf("key %q has neither secret nor public key", file.ID)
+	}
+	publicKey, err := paseto.NewV4AsymmetricPublicKeyFromHex(file.PublicKey)
+	if err != nil {
+		return key, fmt.Errorf("invalid public key of %q: %w", file.ID, err)
+	}
+	key.PublicKey = publicKey
+
+	return key, nil
+}
@@ -3,12 +3,14 @@ package token
 import (
 	"bank/utils"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 const minSecretKeySize = 32
 
 type Maker interface {
-	CreateToken(userID int64, role utils.Role, duration time.Duration) (string, *Payload, error)
+	CreateToken(userID int64, role utils.Role, tokenType TokenType, sessionID uuid.UUID, duration time.Duration) (string, *Payload, error)
 
 	VerifyToken(token string) (*Payload, error)
 }
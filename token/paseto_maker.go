@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"aidanwoods.dev/go-paseto"
+	"github.com/google/uuid"
 )
 
 const implicitString = "azazaz nahooy lalka"
@@ -31,8 +32,8 @@ func NewPasetoMaker(symmetricKey string) (Maker, error) {
 	return &PasetoMaker{v4SymmetricKey}, nil
 }
 
-func (pm *PasetoMaker) CreateToken(userID int64, role utils.Role, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(userID, role, duration)
+func (pm *PasetoMaker) CreateToken(userID int64, role utils.Role, tokenType TokenType, sessionID uuid.UUID, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(userID, role, tokenType, sessionID, duration)
 	claims := Claims{*payload, payload.ExpiresAt}
 	if err != nil {
 		return "", payload, err
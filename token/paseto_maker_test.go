@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -13,12 +14,13 @@ func TestPasetoMaker(t *testing.T) {
 	require.NoError(t, err)
 
 	userID := utils.RandomInt(1, 1000)
+	sessionID := uuid.New()
 	duration := time.Minute
 
 	issuedAt := time.Now()
 	expiresAt := issuedAt.Add(duration)
 
-	token, payload, err := maker.CreateToken(userID, utils.Depositor, duration)
+	token, payload, err := maker.CreateToken(userID, utils.Depositor, TokenTypeAccess, sessionID, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -28,6 +30,8 @@ func TestPasetoMaker(t *testing.T) {
 	require.NotEmpty(t, payload)
 	require.NotZero(t, payload.ID)
 	require.Equal(t, payload.UserID, userID)
+	require.Equal(t, sessionID, payload.SessionID)
+	require.Equal(t, TokenTypeAccess, payload.Type)
 	require.WithinDuration(t, expiresAt, payload.ExpiresAt, time.Second)
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
 }
@@ -39,7 +43,7 @@ func TestExpiredPasetoToken(t *testing.T) {
 	userID := utils.RandomInt(1, 1000)
 	duration := -time.Minute
 
-	token, payload, err := maker.CreateToken(userID, utils.Depositor, duration)
+	token, payload, err := maker.CreateToken(userID, utils.Depositor, TokenTypeAccess, uuid.Nil, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 
@@ -0,0 +1,81 @@
+package token
+
+import (
+	"bank/utils"
+	"encoding/json"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+	"github.com/google/uuid"
+)
+
+type footer struct {
+	KeyID string `json:"kid"`
+}
+
+// PasetoPublicMaker signs tokens with the current key and puts its ID into the footer,
+// so that the tokens can be verified by anybody who holds the public keys.
+type PasetoPublicMaker struct {
+	*keySet
+}
+
+func NewPasetoPublicMaker(keys []SigningKey, gracePeriod time.Duration) (Maker, error) {
+	keySet, err := newKeySet(keys, gracePeriod)
+	if err != nil {
+		return nil, err
+	}
+	return &PasetoPublicMaker{keySet}, nil
+}
+
+func (pm *PasetoPublicMaker) CreateToken(userID int64, role utils.Role, tokenType TokenType, sessionID uuid.UUID, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(userID, role, tokenType, sessionID, duration)
+	if err != nil {
+		return "", payload, err
+	}
+
+	claimsJSON, err := json.Marshal(Claims{*payload, payload.ExpiresAt})
+	if err != nil {
+		return "", payload, err
+	}
+
+	footerJSON, err := json.Marshal(footer{KeyID: pm.current.ID})
+	if err != nil {
+		return "", payload, err
+	}
+
+	token, err := paseto.NewTokenFromClaimsJSON(claimsJSON, footerJSON)
+	if err != nil {
+		return "", payload, err
+	}
+
+	return token.V4Sign(*pm.current.SecretKey, nil), payload, nil
+}
+
+func (pm *PasetoPublicMaker) VerifyToken(token string) (*Payload, error) {
+	parser := paseto.NewParser()
+
+	footerJSON, err := parser.UnsafeParseFooter(paseto.V4Public, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var f footer
+	if err = json.Unmarshal(footerJSON, &f); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	key, err := pm.verificationKey(f.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	pToken, err := parser.ParseV4Public(key.PublicKey, token, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := Claims{}
+
+	err = json.Unmarshal(pToken.ClaimsJSON(), &claims)
+	return &claims.Payload, err
+}
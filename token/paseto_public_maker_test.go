@@ -0,0 +1,172 @@
+package token
+
+import (
+	"bank/utils"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func randomSigningKey(retiredAt time.Time) SigningKey {
+	secretKey := paseto.NewV4AsymmetricSecretKey()
+	return SigningKey{
+		ID:        utils.RandomString(8),
+		SecretKey: &secretKey,
+		PublicKey: secretKey.Public(),
+		RetiredAt: retiredAt,
+	}
+}
+
+func TestPasetoPublicMaker(t *testing.T) {
+	maker, err := NewPasetoPublicMaker([]SigningKey{randomSigningKey(time.Time{})}, time.Hour)
+	require.NoError(t, err)
+
+	userID := utils.RandomInt(1, 1000)
+	sessionID := uuid.New()
+	duration := time.Minute
+
+	issuedAt := time.Now()
+	expiresAt := issuedAt.Add(duration)
+
+	token, payload, err := maker.CreateToken(userID, utils.Depositor, TokenTypeAccess, sessionID, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+	require.NotZero(t, payload.ID)
+	require.Equal(t, payload.UserID, userID)
+	require.Equal(t, sessionID, payload.SessionID)
+	require.Equal(t, TokenTypeAccess, payload.Type)
+	require.WithinDuration(t, expiresAt, payload.ExpiresAt, time.Second)
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+}
+
+func TestExpiredPasetoPublicToken(t *testing.T) {
+	maker, err := NewPasetoPublicMaker([]SigningKey{randomSigningKey(time.Time{})}, time.Hour)
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(utils.RandomInt(1, 1000), utils.Depositor, TokenTypeAccess, uuid.Nil, -time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.ErrorContains(t, err, "expired")
+	require.Nil(t, payload)
+}
+
+func TestPasetoPublicKeyRotation(t *testing.T) {
+	oldKey := randomSigningKey(time.Time{})
+	oldMaker, err := NewPasetoPublicMaker([]SigningKey{oldKey}, time.Hour)
+	require.NoError(t, err)
+
+	token, _, err := oldMaker.CreateToken(utils.RandomInt(1, 1000), utils.Depositor, TokenTypeAccess, uuid.Nil, time.Minute)
+	require.NoError(t, err)
+
+	newKey := randomSigningKey(time.Time{})
+
+	// the retired key still verifies the tokens it signed during the grace period
+	oldKey.RetiredAt = time.Now().Add(-time.Minute)
+	maker, err := NewPasetoPublicMaker([]SigningKey{oldKey, newKey}, time.Hour)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+
+	newToken, _, err := maker.CreateToken(utils.RandomInt(1, 1000), utils.Depositor, TokenTypeAccess, uuid.Nil, time.Minute)
+	require.NoError(t, err)
+	_, err = oldMaker.VerifyToken(newToken)
+	require.ErrorIs(t, err, ErrUnknownKey)
+
+	// and rejects them afterwards
+	oldKey.RetiredAt = time.Now().Add(-2 * time.Hour)
+	maker, err = NewPasetoPublicMaker([]SigningKey{oldKey, newKey}, time.Hour)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(token)
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestPasetoPublicMakerKeys(t *testing.T) {
+	retired := randomSigningKey(time.Now())
+	withoutSecret := randomSigningKey(time.Time{})
+	withoutSecret.SecretKey = nil
+	duplicate := randomSigningKey(time.Time{})
+	duplicate.ID = retired.ID
+
+	testCases := []struct {
+		name string
+		keys []SigningKey
+	}{
+		{name: "No keys", keys: nil},
+		{name: "Only retired keys", keys: []SigningKey{retired}},
+		{name: "Two current keys", keys: []SigningKey{randomSigningKey(time.Time{}), randomSigningKey(time.Time{})}},
+		{name: "Current key without secret", keys: []SigningKey{retired, withoutSecret}},
+		{name: "Duplicated ID", keys: []SigningKey{retired, duplicate}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewPasetoPublicMaker(tc.keys, time.Hour)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestNewMakerFromKeyDir(t *testing.T) {
+	dir := t.TempDir()
+	current := randomSigningKey(time.Time{})
+	retired := randomSigningKey(time.Now())
+
+	writeKeyFile := func(file keyFile) {
+		data, err := json.Marshal(file)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file.ID+".json"), data, 0o600))
+	}
+	writeKeyFile(keyFile{ID: current.ID, SecretKey: current.SecretKey.ExportHex()})
+	writeKeyFile(keyFile{ID: retired.ID, PublicKey: retired.PublicKey.ExportHex(), RetiredAt: retired.RetiredAt})
+
+	maker, err := NewMaker(utils.Config{TokenKeysDir: dir, TokenKeyGracePeriod: time.Hour})
+	require.NoError(t, err)
+	require.IsType(t, &PasetoPublicMaker{}, maker)
+
+	// a token of the retired key is accepted
+	retiredMaker, err := NewPasetoPublicMaker([]SigningKey{{ID: retired.ID, SecretKey: retired.SecretKey, PublicKey: retired.PublicKey}}, time.Hour)
+	require.NoError(t, err)
+	token, _, err := retiredMaker.CreateToken(utils.RandomInt(1, 1000), utils.Depositor, TokenTypeAccess, uuid.Nil, time.Minute)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+}
+
+func TestNewMakerFallsBackToSymmetricKey(t *testing.T) {
+	maker, err := NewMaker(utils.Config{TokenSymmetricKey: utils.RandomString(32)})
+	require.NoError(t, err)
+	require.IsType(t, &PasetoMaker{}, maker)
+}
+
+func TestPasetoPublicMakerPublicKeys(t *testing.T) {
+	current := randomSigningKey(time.Time{})
+	current.CreatedAt = time.Now().Add(-time.Hour)
+	inGrace := randomSigningKey(time.Now().Add(-time.Minute))
+	expired := randomSigningKey(time.Now().Add(-2 * time.Hour))
+
+	maker, err := NewPasetoPublicMaker([]SigningKey{expired, inGrace, current}, time.Hour)
+	require.NoError(t, err)
+
+	keys := maker.(KeyPublisher).PublicKeys()
+	require.Len(t, keys, 2)
+
+	require.Equal(t, current.ID, keys[0].ID)
+	require.Equal(t, current.PublicKey.ExportBytes(), []byte(keys[0].Key))
+	require.Equal(t, current.CreatedAt, keys[0].NotBefore)
+	require.Zero(t, keys[0].NotAfter)
+
+	require.Equal(t, inGrace.ID, keys[1].ID)
+	require.Equal(t, inGrace.RetiredAt.Add(time.Hour), keys[1].NotAfter)
+}
@@ -2,20 +2,37 @@ package token
 
 import (
 	"bank/utils"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// TokenType tells access tokens, accepted on authenticated routes, apart from refresh tokens,
+// accepted only to renew or end a session.
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
+
+var ErrWrongTokenType = errors.New("wrong token type")
+
 type Payload struct {
-	ID        uuid.UUID  `json:"id"`
-	UserID    int64      `json:"user_id"`
-	Role      utils.Role `json:"role"`
-	IssuedAt  time.Time  `json:"issued_at"`
-	ExpiresAt time.Time  `json:"expires_at"`
+	ID     uuid.UUID  `json:"id"`
+	UserID int64      `json:"user_id"`
+	Role   utils.Role `json:"role"`
+	Type   TokenType  `json:"type"`
+	// SessionID is the family of the session an access token was issued for,
+	// so that the token can be revoked along with the session.
+	SessionID uuid.UUID `json:"session_id"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
-func NewPayload(userID int64, role utils.Role, duration time.Duration) (*Payload, error) {
+func NewPayload(userID int64, role utils.Role, tokenType TokenType, sessionID uuid.UUID, duration time.Duration) (*Payload, error) {
 	uuid, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -25,7 +42,18 @@ func NewPayload(userID int64, role utils.Role, duration time.Duration) (*Payload
 		ID:        uuid,
 		UserID:    userID,
 		Role:      role,
+		Type:      tokenType,
+		SessionID: sessionID,
 		IssuedAt:  time.Now(),
 		ExpiresAt: time.Now().Add(duration),
 	}, nil
 }
+
+// CheckType returns ErrWrongTokenType unless the token is of the expected type.
+// Tokens issued before the type was introduced have none and fail the check.
+func (payload *Payload) CheckType(tokenType TokenType) error {
+	if payload.Type != tokenType {
+		return fmt.Errorf("%w: expected %s token", ErrWrongTokenType, tokenType)
+	}
+	return nil
+}
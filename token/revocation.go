@@ -0,0 +1,140 @@
+package token
+
+import (
+	db "bank/db/sqlc"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+// RevocationStore is the part of db.Store the revocation check reads from.
+type RevocationStore interface {
+	GetUser(ctx context.Context, id int64) (db.User, error)
+	GetSession(ctx context.Context, id uuid.UUID) (db.Session, error)
+}
+
+type cachedUser struct {
+	passwordChangedAt time.Time
+	cachedAt          time.Time
+}
+
+type cachedSession struct {
+	userID    int64
+	isBlocked bool
+	cachedAt  time.Time
+}
+
+// RevocationChecker rejects tokens that are still valid cryptographically
+// but were issued before the user changed the password or for a session that has been blocked.
+// The lookups are cached for ttl, so a revocation made by another instance
+// takes effect after at most ttl; the ones made by this instance should be reported with Forget.
+type RevocationChecker struct {
+	store    RevocationStore
+	ttl      time.Duration
+	mu       sync.Mutex
+	users    map[int64]cachedUser
+	sessions map[uuid.UUID]cachedSession
+}
+
+func NewRevocationChecker(store RevocationStore, ttl time.Duration) *RevocationChecker {
+	return &RevocationChecker{
+		store:    store,
+		ttl:      ttl,
+		users:    make(map[int64]cachedUser),
+		sessions: make(map[uuid.UUID]cachedSession),
+	}
+}
+
+// Check returns ErrTokenRevoked if the token must not be accepted anymore.
+// Every access token is bound to a session, so a token without one is rejected as well.
+func (checker *RevocationChecker) Check(ctx context.Context, payload *Payload) error {
+	if payload.SessionID == uuid.Nil {
+		return ErrTokenRevoked
+	}
+
+	passwordChangedAt, err := checker.passwordChangedAt(ctx, payload.UserID)
+	if err != nil {
+		return err
+	}
+	if payload.IssuedAt.Before(passwordChangedAt) {
+		return ErrTokenRevoked
+	}
+
+	session, err := checker.session(ctx, payload.SessionID)
+	if err != nil {
+		return err
+	}
+	if session.isBlocked || session.userID != payload.UserID {
+		return ErrTokenRevoked
+	}
+
+	return nil
+}
+
+// Forget drops everything cached about the user and their sessions,
+// so that a revocation made by this instance applies to the next request.
+func (checker *RevocationChecker) Forget(userID int64) {
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	delete(checker.users, userID)
+	for id, session := range checker.sessions {
+		if session.userID == userID {
+			delete(checker.sessions, id)
+		}
+	}
+}
+
+func (checker *RevocationChecker) passwordChangedAt(ctx context.Context, userID int64) (time.Time, error) {
+	checker.mu.Lock()
+	cached, isOk := checker.users[userID]
+	checker.mu.Unlock()
+	if isOk && time.Since(cached.cachedAt) < checker.ttl {
+		return cached.passwordChangedAt, nil
+	}
+
+	user, err := checker.store.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return time.Time{}, ErrTokenRevoked
+		}
+		return time.Time{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	cached = cachedUser{passwordChangedAt: user.PasswordChangedAt, cachedAt: time.Now()}
+	checker.mu.Lock()
+	checker.users[userID] = cached
+	checker.mu.Unlock()
+
+	return cached.passwordChangedAt, nil
+}
+
+func (checker *RevocationChecker) session(ctx context.Context, sessionID uuid.UUID) (cachedSession, error) {
+	checker.mu.Lock()
+	cached, isOk := checker.sessions[sessionID]
+	checker.mu.Unlock()
+	if isOk && time.Since(cached.cachedAt) < checker.ttl {
+		return cached, nil
+	}
+
+	session, err := checker.store.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return cached, ErrTokenRevoked
+		}
+		return cached, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	cached = cachedSession{userID: session.UserID, isBlocked: session.IsBlocked, cachedAt: time.Now()}
+	checker.mu.Lock()
+	checker.sessions[sessionID] = cached
+	checker.mu.Unlock()
+
+	return cached, nil
+}
@@ -0,0 +1,174 @@
+package token
+
+import (
+	mockdb "bank/db/mock"
+	db "bank/db/sqlc"
+	"bank/utils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRevocationChecker(t *testing.T) {
+	userID := utils.RandomInt(1, 1000)
+	sessionID := uuid.New()
+	issuedAt := time.Now()
+
+	testCases := []struct {
+		name       string
+		payload    Payload
+		buildStubs func(store *mockdb.MockStore)
+		checkError func(t *testing.T, err error)
+	}{
+		{
+			name:    "OK",
+			payload: Payload{UserID: userID, SessionID: sessionID, IssuedAt: issuedAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(db.User{ID: userID, PasswordChangedAt: issuedAt.Add(-time.Hour)}, nil)
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(db.Session{ID: sessionID, UserID: userID}, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:    "No session",
+			payload: Payload{UserID: userID, IssuedAt: issuedAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrTokenRevoked)
+			},
+		},
+		{
+			name:    "Password changed",
+			payload: Payload{UserID: userID, SessionID: sessionID, IssuedAt: issuedAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(db.User{ID: userID, PasswordChangedAt: issuedAt.Add(time.Second)}, nil)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrTokenRevoked)
+			},
+		},
+		{
+			name:    "Session blocked",
+			payload: Payload{UserID: userID, SessionID: sessionID, IssuedAt: issuedAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(db.User{ID: userID}, nil)
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(db.Session{ID: sessionID, UserID: userID, IsBlocked: true}, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrTokenRevoked)
+			},
+		},
+		{
+			name:    "Foreign session",
+			payload: Payload{UserID: userID, SessionID: sessionID, IssuedAt: issuedAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(db.User{ID: userID}, nil)
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(db.Session{ID: sessionID, UserID: userID + 1}, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrTokenRevoked)
+			},
+		},
+		{
+			name:    "User not found",
+			payload: Payload{UserID: userID, SessionID: sessionID, IssuedAt: issuedAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrTokenRevoked)
+			},
+		},
+		{
+			name:    "Internal error",
+			payload: Payload{UserID: userID, SessionID: sessionID, IssuedAt: issuedAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ctrl := gomock.NewController(t)
+
+		store := mockdb.NewMockStore(ctrl)
+
+		tc.buildStubs(store)
+
+		checker := NewRevocationChecker(store, time.Minute)
+
+		tc.checkError(t, checker.Check(context.Background(), &tc.payload))
+	}
+}
+
+func TestRevocationCheckerCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	userID := utils.RandomInt(1, 1000)
+	sessionID := uuid.New()
+	payload := &Payload{UserID: userID, SessionID: sessionID, IssuedAt: time.Now()}
+
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(userID)).
+		Times(2).
+		Return(db.User{ID: userID}, nil)
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Eq(sessionID)).
+		Times(1).
+		Return(db.Session{ID: sessionID, UserID: userID}, nil)
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Eq(sessionID)).
+		Times(1).
+		Return(db.Session{ID: sessionID, UserID: userID, IsBlocked: true}, nil)
+
+	checker := NewRevocationChecker(store, time.Minute)
+
+	// the second check is served from the cache
+	require.NoError(t, checker.Check(context.Background(), payload))
+	require.NoError(t, checker.Check(context.Background(), payload))
+
+	checker.Forget(userID)
+	require.ErrorIs(t, checker.Check(context.Background(), payload), ErrTokenRevoked)
+}
@@ -14,13 +14,53 @@ type Config struct {
 	MigrationURL         string        `mapstructure:"MIGRATION_URL"`
 	HTTPServerAddress    string        `mapstructure:"HTTP_SERVER_ADDRESS"`
 	GRPCServerAddress    string        `mapstructure:"GRPC_SERVER_ADDRESS"`
+	TokenFormat          string        `mapstructure:"TOKEN_FORMAT"`
 	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	TokenSecretKey       string        `mapstructure:"TOKEN_SECRET_KEY"`
+	TokenKeyID           string        `mapstructure:"TOKEN_KEY_ID"`
+	TokenKeysDir         string        `mapstructure:"TOKEN_KEYS_DIR"`
+	TokenKeyGracePeriod  time.Duration `mapstructure:"TOKEN_KEY_GRACE_PERIOD"`
 	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	RevocationCacheTTL   time.Duration `mapstructure:"REVOCATION_CACHE_TTL"`
+	PermissionCacheTTL   time.Duration `mapstructure:"PERMISSION_CACHE_TTL"`
+	IdempotencyKeyTTL    time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
+	TOTPIssuer           string        `mapstructure:"TOTP_ISSUER"`
+	MFAChallengeDuration time.Duration `mapstructure:"MFA_CHALLENGE_DURATION"`
+	TransferMFAThreshold int64         `mapstructure:"TRANSFER_MFA_THRESHOLD"`
+	PasswordResetLimit   int64         `mapstructure:"PASSWORD_RESET_LIMIT"`
+	PasswordResetWindow  time.Duration `mapstructure:"PASSWORD_RESET_WINDOW"`
+	VerifyEmailLimit     int64         `mapstructure:"VERIFY_EMAIL_LIMIT"`
+	VerifyEmailWindow    time.Duration `mapstructure:"VERIFY_EMAIL_WINDOW"`
+	LoginMaxFailures     int64         `mapstructure:"LOGIN_MAX_FAILURES"`
+	LoginIPMaxFailures   int64         `mapstructure:"LOGIN_IP_MAX_FAILURES"`
+	LoginFailureWindow   time.Duration `mapstructure:"LOGIN_FAILURE_WINDOW"`
+	LoginLockoutDuration time.Duration `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+	LoginDelayBase       time.Duration `mapstructure:"LOGIN_DELAY_BASE"`
+	LoginDelayMax        time.Duration `mapstructure:"LOGIN_DELAY_MAX"`
+	FXRateSource         string        `mapstructure:"FX_RATE_SOURCE"`
+	FXRatesFile          string        `mapstructure:"FX_RATES_FILE"`
 	RedisAddr            string        `mapstructure:"REDIS_ADDR"`
-	GmailName            string        `mapstructure:"GMAIL_NAME"`
-	GmailFrom            string        `mapstructure:"GMAIL_FROM"`
-	GmailAccPassword     string        `mapstructure:"GMAIL_APP_PASSWORD"`
+	OutboxRelayInterval  time.Duration `mapstructure:"OUTBOX_RELAY_INTERVAL"`
+	EmailSender          string        `mapstructure:"EMAIL_SENDER"`
+	EmailFromName        string        `mapstructure:"EMAIL_FROM_NAME"`
+	EmailFromAddress     string        `mapstructure:"EMAIL_FROM_ADDRESS"`
+	EmailCaptureDir      string        `mapstructure:"EMAIL_CAPTURE_DIR"`
+	EmailTemplatesDir    string        `mapstructure:"EMAIL_TEMPLATES_DIR"`
+	EmailDefaultLocale   string        `mapstructure:"EMAIL_DEFAULT_LOCALE"`
+	PublicBaseURL        string        `mapstructure:"PUBLIC_BASE_URL"`
+	PasswordResetURL     string        `mapstructure:"PASSWORD_RESET_URL"`
+	SMTPHost             string        `mapstructure:"SMTP_HOST"`
+	SMTPPort             int           `mapstructure:"SMTP_PORT"`
+	SMTPUsername         string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword         string        `mapstructure:"SMTP_PASSWORD"`
+	SMTPAuth             string        `mapstructure:"SMTP_AUTH"`
+	SMTPTLS              string        `mapstructure:"SMTP_TLS"`
+	// Deprecated: the GMAIL_* keys predate the SMTP settings. They only fill in
+	// the sender and the credentials left empty, see applyGmailKeys.
+	GmailName        string `mapstructure:"GMAIL_NAME"`
+	GmailFrom        string `mapstructure:"GMAIL_FROM"`
+	GmailAppPassword string `mapstructure:"GMAIL_APP_PASSWORD"`
 }
 
 // LoadConfig reads configuration from environment file or variables
@@ -52,6 +92,34 @@ func LoadConfig(path string) (config Config, err error) {
 		return
 	}
 
+	// the deprecated keys are not in app.env, so viper learns about them from the environment only
+	for _, key := range []string{"GMAIL_NAME", "GMAIL_FROM", "GMAIL_APP_PASSWORD"} {
+		if err = viper.BindEnv(key); err != nil {
+			return
+		}
+	}
+
 	err = viper.Unmarshal(&config)
+	config.applyGmailKeys()
 	return
 }
+
+// applyGmailKeys keeps the deployments configured with the GMAIL_* keys sending mail:
+// the Gmail address is both the sender and the SMTP user, the app password is the SMTP password.
+func (config *Config) applyGmailKeys() {
+	if config.GmailFrom == "" {
+		return
+	}
+	if config.EmailFromName == "" {
+		config.EmailFromName = config.GmailName
+	}
+	if config.EmailFromAddress == "" {
+		config.EmailFromAddress = config.GmailFrom
+	}
+	if config.SMTPUsername == "" {
+		config.SMTPUsername = config.GmailFrom
+	}
+	if config.SMTPPassword == "" {
+		config.SMTPPassword = config.GmailAppPassword
+	}
+}
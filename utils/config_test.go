@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyGmailKeys(t *testing.T) {
+	config := Config{GmailName: "Mini Bank", GmailFrom: "bank@gmail.com", GmailAppPassword: "app-password"}
+	config.applyGmailKeys()
+	require.Equal(t, "Mini Bank", config.EmailFromName)
+	require.Equal(t, "bank@gmail.com", config.EmailFromAddress)
+	require.Equal(t, "bank@gmail.com", config.SMTPUsername)
+	require.Equal(t, "app-password", config.SMTPPassword)
+
+	// the SMTP settings win over the deprecated keys
+	config = Config{GmailFrom: "bank@gmail.com", GmailAppPassword: "app-password", EmailFromAddress: "noreply@example.com", SMTPUsername: "relay", SMTPPassword: "secret"}
+	config.applyGmailKeys()
+	require.Equal(t, "noreply@example.com", config.EmailFromAddress)
+	require.Equal(t, "relay", config.SMTPUsername)
+	require.Equal(t, "secret", config.SMTPPassword)
+}
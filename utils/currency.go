@@ -1,20 +1,122 @@
 package utils
 
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
 const (
 	USD = "USD"
 	EUR = "EUR"
 	UAH = "UAH"
 )
 
+// Currency describes an ISO 4217 currency. Amounts are stored in minor units,
+// so an amount of 1234 is 12.34 in a currency with the exponent of 2.
+type Currency struct {
+	Code        string `json:"code"`
+	NumericCode int32  `json:"numeric_code"`
+	Exponent    int32  `json:"exponent"`
+	Symbol      string `json:"symbol"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// currencies is the process-wide registry, filled from the currencies table at startup.
+// Until then it holds the currencies the bank was originally launched with.
+var currencies = struct {
+	sync.RWMutex
+	byCode map[string]Currency
+}{
+	byCode: map[string]Currency{
+		USD: {Code: USD, NumericCode: 840, Exponent: 2, Symbol: "$", Enabled: true},
+		EUR: {Code: EUR, NumericCode: 978, Exponent: 2, Symbol: "€", Enabled: true},
+		UAH: {Code: UAH, NumericCode: 980, Exponent: 2, Symbol: "₴", Enabled: true},
+	},
+}
+
+// SetCurrencies replaces the registry with the given currencies.
+func SetCurrencies(list []Currency) {
+	byCode := make(map[string]Currency, len(list))
+	for _, currency := range list {
+		byCode[currency.Code] = currency
+	}
+
+	currencies.Lock()
+	defer currencies.Unlock()
+	currencies.byCode = byCode
+}
+
+// GetCurrency looks the currency up by its code, whether it is enabled or not.
+func GetCurrency(code string) (Currency, bool) {
+	currencies.RLock()
+	defer currencies.RUnlock()
+	currency, ok := currencies.byCode[code]
+	return currency, ok
+}
+
+// CurrencyExponents maps the given currency codes to their exponents.
+// It fails for a currency missing from the registry rather than guess its exponent.
+func CurrencyExponents(codes ...string) (map[string]int32, error) {
+	exponents := make(map[string]int32, len(codes))
+	for _, code := range codes {
+		currency, ok := GetCurrency(code)
+		if !ok {
+			return nil, fmt.Errorf("unknown currency %s", code)
+		}
+		exponents[code] = currency.Exponent
+	}
+	return exponents, nil
+}
+
+// GetEnabledCurrencies returns the enabled currencies ordered by code.
+func GetEnabledCurrencies() []Currency {
+	currencies.RLock()
+	defer currencies.RUnlock()
+
+	list := make([]Currency, 0, len(currencies.byCode))
+	for _, currency := range currencies.byCode {
+		if currency.Enabled {
+			list = append(list, currency)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list
+}
+
 func GetSupportedCurrencies() []string {
-	return []string{USD, EUR, UAH}
+	list := GetEnabledCurrencies()
+	codes := make([]string, 0, len(list))
+	for _, currency := range list {
+		codes = append(codes, currency.Code)
+	}
+	return codes
 }
 
 func IsCurrencySupported(currency string) bool {
-	for _, cur := range GetSupportedCurrencies() {
-		if cur == currency {
-			return true
-		}
+	cur, ok := GetCurrency(currency)
+	return ok && cur.Enabled
+}
+
+// FormatAmount renders an amount of minor units with the symbol and exponent of its currency,
+// e.g. 123456 USD as "$1234.56" and 500 JPY as "¥500".
+func FormatAmount(amount int64, code string) string {
+	currency, ok := GetCurrency(code)
+	if !ok {
+		return fmt.Sprintf("%d %s", amount, code)
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
 	}
-	return false
+
+	digits := fmt.Sprintf("%0*d", currency.Exponent+1, amount)
+	if currency.Exponent == 0 {
+		return sign + currency.Symbol + digits
+	}
+
+	point := len(digits) - int(currency.Exponent)
+	return sign + currency.Symbol + digits[:point] + "." + digits[point:]
 }
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setTestCurrencies(t *testing.T, list []Currency) {
+	previous := GetEnabledCurrencies()
+	SetCurrencies(list)
+	t.Cleanup(func() { SetCurrencies(previous) })
+}
+
+func TestCurrencyRegistry(t *testing.T) {
+	setTestCurrencies(t, []Currency{
+		{Code: USD, NumericCode: 840, Exponent: 2, Symbol: "$", Enabled: true},
+		{Code: "JPY", NumericCode: 392, Exponent: 0, Symbol: "¥", Enabled: true},
+		{Code: "GBP", NumericCode: 826, Exponent: 2, Symbol: "£", Enabled: false},
+	})
+
+	require.Equal(t, []string{"JPY", USD}, GetSupportedCurrencies())
+	require.True(t, IsCurrencySupported("JPY"))
+	require.False(t, IsCurrencySupported("GBP"))
+	require.False(t, IsCurrencySupported(EUR))
+
+	gbp, ok := GetCurrency("GBP")
+	require.True(t, ok)
+	require.Equal(t, int32(826), gbp.NumericCode)
+
+	exponents, err := CurrencyExponents(USD, "JPY", "GBP")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int32{USD: 2, "JPY": 0, "GBP": 2}, exponents)
+
+	_, err = CurrencyExponents(USD, EUR)
+	require.Error(t, err)
+}
+
+func TestFormatAmount(t *testing.T) {
+	setTestCurrencies(t, []Currency{
+		{Code: USD, Exponent: 2, Symbol: "$", Enabled: true},
+		{Code: "JPY", Exponent: 0, Symbol: "¥", Enabled: true},
+		{Code: "BHD", Exponent: 3, Symbol: "BD", Enabled: true},
+	})
+
+	testCases := []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{123456, USD, "$1234.56"},
+		{5, USD, "$0.05"},
+		{-250, USD, "-$2.50"},
+		{500, "JPY", "¥500"},
+		{1500, "BHD", "BD1.500"},
+		{42, "XXX", "42 XXX"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.want, FormatAmount(tc.amount, tc.currency))
+	}
+}
@@ -0,0 +1,22 @@
+package utils
+
+import "strings"
+
+// DefaultLocale is used when neither the user nor the config picks a locale.
+const DefaultLocale = "en"
+
+var supportedLocales = map[string]bool{
+	"en": true,
+	"ru": true,
+}
+
+// IsLocaleSupported accepts a supported language, optionally with a region like "ru-RU".
+func IsLocaleSupported(locale string) bool {
+	return supportedLocales[LocaleLanguage(locale)]
+}
+
+// LocaleLanguage drops the region of a locale: "ru-RU" becomes "ru".
+func LocaleLanguage(locale string) string {
+	language, _, _ := strings.Cut(locale, "-")
+	return strings.ToLower(language)
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 
 	"golang.org/x/crypto/bcrypt"
@@ -17,3 +20,20 @@ func HashedPassword(password string) (string, error) {
 func CompareHashAndPassword(hash, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 }
+
+// HashCode hashes a random one-time code, such as a password reset code, for storing it.
+// Unlike passwords, the codes have enough entropy for a fast hash.
+func HashCode(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
+
+// GenerateCode returns a one-time code made of size random bytes, hex encoded.
+// The bytes come from crypto/rand, so that the code can't be predicted.
+func GenerateCode(size int) (string, error) {
+	random := make([]byte, size)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(random), nil
+}
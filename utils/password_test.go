@@ -24,3 +24,20 @@ func TestPassword(t *testing.T) {
 	err = CompareHashAndPassword(hash, wrongPassword)
 	require.ErrorIs(t, err, bcrypt.ErrMismatchedHashAndPassword)
 }
+
+func TestHashCode(t *testing.T) {
+	code := RandomString(32)
+	require.Equal(t, HashCode(code), HashCode(code))
+	require.NotEqual(t, HashCode(code), HashCode(RandomString(32)))
+	require.NotContains(t, HashCode(code), code)
+}
+
+func TestGenerateCode(t *testing.T) {
+	code, err := GenerateCode(16)
+	require.NoError(t, err)
+	require.Len(t, code, 32)
+
+	other, err := GenerateCode(16)
+	require.NoError(t, err)
+	require.NotEqual(t, code, other)
+}
@@ -21,7 +21,7 @@ func RandomEmail() string {
 }
 
 func RandomCurrency() string {
-	return GetSupportedCurrencies()[rand.Int31n(3)]
+	return []string{USD, EUR, UAH}[rand.Intn(3)]
 }
 
 func RandomInt(min, max int64) int64 {
@@ -1,9 +1,11 @@
 package validation
 
 import (
+	"bank/utils"
 	"fmt"
 	"net/mail"
 	"regexp"
+	"time"
 )
 
 type ValidationError struct {
@@ -14,6 +16,7 @@ type ValidationError struct {
 var (
 	isUsernameValid = regexp.MustCompile(`^[a-z0-9_]+$`).MatchString
 	isFullNameValid = regexp.MustCompile(`^[a-zA-Z\s]+$`).MatchString
+	isLocaleValid   = regexp.MustCompile(`^[a-z]{2}(-[A-Z]{2})?$`).MatchString
 )
 
 func ValidateString(val string, minLength, maxLength int) error {
@@ -60,3 +63,75 @@ func ValidateEmail(email string) *ValidationError {
 	}
 	return nil
 }
+
+func ValidateLocale(locale string) *ValidationError {
+	if !isLocaleValid(locale) || !utils.IsLocaleSupported(locale) {
+		return &ValidationError{fmt.Errorf("unsupported locale %s", locale), "locale"}
+	}
+	return nil
+}
+
+func ValidateOwner(owner string) *ValidationError {
+	if err := ValidateString(owner, 3, 100); err != nil {
+		return &ValidationError{err, "owner"}
+	}
+	if !isFullNameValid(owner) {
+		return &ValidationError{fmt.Errorf("must contain letters and spaces only"), "owner"}
+	}
+	return nil
+}
+
+func ValidateCurrency(currency string) *ValidationError {
+	if !utils.IsCurrencySupported(currency) {
+		return &ValidationError{fmt.Errorf("unsupported currency %s", currency), "currency"}
+	}
+	return nil
+}
+
+func ValidateID(id int64, field string) *ValidationError {
+	if id < 1 {
+		return &ValidationError{fmt.Errorf("must be a positive number"), field}
+	}
+	return nil
+}
+
+func ValidatePageSize(pageSize int32) *ValidationError {
+	if pageSize < 1 || pageSize > 100 {
+		return &ValidationError{fmt.Errorf("should be of 1-100"), "page_size"}
+	}
+	return nil
+}
+
+func ValidateAmount(amount int64) *ValidationError {
+	if amount <= 0 {
+		return &ValidationError{fmt.Errorf("must be greater than 0"), "amount"}
+	}
+	return nil
+}
+
+func ValidateIdempotencyKey(key string) *ValidationError {
+	if err := ValidateString(key, 1, 255); err != nil {
+		return &ValidationError{err, "idempotency_key"}
+	}
+	return nil
+}
+
+func ValidateTimeRange(from, to time.Time) *ValidationError {
+	if from.IsZero() {
+		return &ValidationError{fmt.Errorf("is required"), "from"}
+	}
+	if to.IsZero() {
+		return &ValidationError{fmt.Errorf("is required"), "to"}
+	}
+	if !from.Before(to) {
+		return &ValidationError{fmt.Errorf("must be before to"), "from"}
+	}
+	return nil
+}
+
+func ValidateStatusReason(reason string) *ValidationError {
+	if err := ValidateString(reason, 1, 255); err != nil {
+		return &ValidationError{err, "reason"}
+	}
+	return nil
+}